@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/JustJay7/court-data-fetcher/internal/mockcourt"
+)
+
+// Runs the mock court on its own, e.g. for
+//
+//	go run ./cmd/mockcourt -addr :8081 -layout divs
+//	COURT_BASE_URL=http://localhost:8081 go run cmd/server/main.go
+func main() {
+	addr := flag.String("addr", ":8081", "Address to listen on")
+	scenario := flag.String("scenario", string(mockcourt.ScenarioNormal), "Search outcome: normal, not_found or captcha_rejected")
+	layout := flag.String("layout", string(mockcourt.LayoutTable), "Case details markup: table, divs or text")
+	delay := flag.Duration("delay", 0, "Delay added to every response")
+	captchaImage := flag.Bool("captcha-image", false, "Show the CAPTCHA as an image instead of printing the code")
+	flag.Parse()
+
+	switch mockcourt.Scenario(*scenario) {
+	case mockcourt.ScenarioNormal, mockcourt.ScenarioNotFound, mockcourt.ScenarioCaptchaRejected:
+	default:
+		fmt.Fprintf(os.Stderr, "unknown scenario %q\n", *scenario)
+		os.Exit(2)
+	}
+	switch mockcourt.Layout(*layout) {
+	case mockcourt.LayoutTable, mockcourt.LayoutDivs, mockcourt.LayoutText:
+	default:
+		fmt.Fprintf(os.Stderr, "unknown layout %q\n", *layout)
+		os.Exit(2)
+	}
+
+	server := mockcourt.New(mockcourt.Config{
+		Scenario:     mockcourt.Scenario(*scenario),
+		Layout:       mockcourt.Layout(*layout),
+		Delay:        *delay,
+		CaptchaImage: *captchaImage,
+	})
+
+	fmt.Printf("Mock court listening on %s (scenario %s, layout %s)\n", *addr, *scenario, *layout)
+	srv := &http.Server{Addr: *addr, Handler: server, ReadHeaderTimeout: 10 * time.Second}
+	if err := srv.ListenAndServe(); err != nil {
+		fmt.Fprintf(os.Stderr, "mock court failed: %v\n", err)
+		os.Exit(1)
+	}
+}
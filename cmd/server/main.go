@@ -1,16 +1,25 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/JustJay7/court-data-fetcher/internal/cache"
+	"github.com/JustJay7/court-data-fetcher/internal/causelist"
 	"github.com/JustJay7/court-data-fetcher/internal/config"
 	"github.com/JustJay7/court-data-fetcher/internal/database"
+	"github.com/JustJay7/court-data-fetcher/internal/ordertype"
+	"github.com/JustJay7/court-data-fetcher/internal/retention"
 	"github.com/JustJay7/court-data-fetcher/internal/scraper"
 	"github.com/JustJay7/court-data-fetcher/internal/server"
+	"github.com/JustJay7/court-data-fetcher/internal/upstream"
 	"github.com/JustJay7/court-data-fetcher/pkg/logger"
 	"gorm.io/gorm"
 )
@@ -18,9 +27,18 @@ import (
 func main() {
 	// Parse command line flags
 	var migrate bool
-	flag.BoolVar(&migrate, "migrate", false, "Run database migrations")
+	flag.BoolVar(&migrate, "migrate", false, "Run database migrations (same as \"migrate up\")")
 	flag.Parse()
 
+	// "migrate up|down|status|to N" manages the schema, "purge [--dry-run]"
+	// applies the retention policies, "fixture <query-log-id> [name]" saves
+	// a stored page as a parser test fixture and "reclassify" relabels the
+	// stored orders with the current rules, all then exit
+	args := flag.Args()
+	if migrate && len(args) == 0 {
+		args = []string{"migrate", "up"}
+	}
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
@@ -36,29 +54,74 @@ func main() {
 	}
 	defer log.Sync()
 
+	if len(args) > 0 && args[0] == "migrate" {
+		db, err := database.Open(cfg)
+		if err != nil {
+			log.Fatal("Failed to open database", "error", err)
+		}
+		if err := runMigrate(db, args[1:]); err != nil {
+			log.Fatal("Migration failed", "error", err)
+		}
+		return
+	}
+
 	// Initialize database
-	db, err := database.Initialize(cfg.DatabasePath)
+	db, err := database.Initialize(cfg)
 	if err != nil {
 		log.Fatal("Failed to initialize database", "error", err)
 	}
 
-	// Run migrations if requested
-	if migrate {
-		if err := database.Migrate(db); err != nil {
-			log.Fatal("Failed to run migrations", "error", err)
+	if len(args) > 0 && args[0] == "purge" {
+		if err := runPurge(db, log, cfg, args[1:]); err != nil {
+			log.Fatal("Purge failed", "error", err)
+		}
+		return
+	}
+
+	if len(args) > 0 && args[0] == "fixture" {
+		if err := runFixture(db, args[1:]); err != nil {
+			log.Fatal("Failed to create fixture", "error", err)
+		}
+		return
+	}
+
+	if len(args) > 0 && args[0] == "reclassify" {
+		if err := runReclassify(db, log, cfg); err != nil {
+			log.Fatal("Failed to classify orders", "error", err)
 		}
-		log.Info("Database migrations completed successfully")
 		return
 	}
 
 	// Initialize cache
-	cacheService := cache.NewCache(cfg.CacheSize, cfg.CacheTTL)
+	cacheService, err := cache.NewFromConfig(cfg, db)
+	if err != nil {
+		log.Fatal("Failed to initialize cache", "error", err)
+	}
+
+	// Proxies and per-host limits shared by every fetch from the court
+	up, err := upstream.New(cfg, log)
+	if err != nil {
+		log.Fatal("Failed to configure proxies", "error", err)
+	}
+	if cfg.ProxyCheckInterval > 0 {
+		go up.Start(context.Background(), cfg.ProxyCheckInterval, cfg.CourtBaseURL)
+	}
 
 	// Create and start server
-	srv := server.New(cfg, db, cacheService, log)
+	srv := server.New(cfg, db, cacheService, up, log)
 	
 	// Start PDF download worker in background
-	go startPDFDownloadWorker(db, log, cfg)
+	go startPDFDownloadWorker(db, up, log, cfg)
+
+	// Apply retention policies in background
+	if cfg.RetentionInterval > 0 {
+		go retention.NewPurger(db, log, cfg).Start(context.Background(), cfg.RetentionInterval)
+	}
+
+	// Keep today's cause list current for the listed-today flags
+	if cfg.CauseListInterval > 0 {
+		go causelist.NewService(db, up, log, cfg).Start(context.Background(), cfg.CauseListInterval)
+	}
 	
 	log.Info("Starting Court Data Fetcher", 
 		"host", cfg.Host,
@@ -71,12 +134,183 @@ func main() {
 	}
 }
 
+// runMigrate handles the migrate subcommands
+func runMigrate(db *gorm.DB, args []string) error {
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	switch command {
+	case "up":
+		err = migrator.Up()
+	case "down":
+		err = migrator.Down()
+	case "to":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: migrate to <version>")
+		}
+		version, convErr := strconv.Atoi(args[1])
+		if convErr != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		err = migrator.To(version)
+	case "status":
+		return printMigrationStatus(migrator)
+	default:
+		return fmt.Errorf("unknown migrate command %q (want up, down, status or to <version>)", command)
+	}
+	if err != nil {
+		return err
+	}
+
+	version, err := migrator.Version()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Database schema is at version %d (latest %d)\n", version, migrator.Latest())
+	return nil
+}
+
+// printMigrationStatus writes one line per known migration
+func printMigrationStatus(migrator *database.Migrator) error {
+	statuses, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, status := range statuses {
+		state, appliedAt := "pending", "-"
+		if status.Applied {
+			state = "applied"
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
+		}
+		if status.Modified {
+			state = "modified"
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt)
+	}
+	w.Flush()
+
+	// Still report a schema this build can't run against
+	return migrator.Verify()
+}
+
+// runPurge applies the retention policies once and prints what changed
+func runPurge(db *gorm.DB, log *logger.Logger, cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("purge", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "Report what would be removed without changing anything")
+	flags.Parse(args)
+
+	report, err := retention.NewPurger(db, log, cfg).Run(*dryRun)
+	if err != nil {
+		return err
+	}
+
+	verb := "Removed or changed"
+	if report.DryRun {
+		verb = "Would remove or change"
+	}
+	fmt.Printf("%s:\n", verb)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "  raw HTML dropped\t%d\n", report.RawHTMLDropped)
+	fmt.Fprintf(w, "  IP addresses anonymised\t%d\n", report.IPsAnonymised)
+	fmt.Fprintf(w, "  PDFs removed\t%d\n", report.PDFsRemoved)
+	fmt.Fprintf(w, "  debug artifacts purged\t%d\n", report.DebugArtifactsPurged)
+	fmt.Fprintf(w, "  orphaned parties\t%d\n", report.OrphanedParties)
+	fmt.Fprintf(w, "  orphaned orders\t%d\n", report.OrphanedOrders)
+	fmt.Fprintf(w, "  orphaned raw pages\t%d\n", report.OrphanedRawResponses)
+	fmt.Fprintf(w, "  orphaned debug artifacts\t%d\n", report.OrphanedDebugArtifacts)
+	fmt.Fprintf(w, "  expired cache entries\t%d\n", report.ExpiredCacheEntries)
+	return w.Flush()
+}
+
+// runReclassify labels every stored order with the configured rules and the
+// text of its downloaded PDF
+func runReclassify(db *gorm.DB, log *logger.Logger, cfg *config.Config) error {
+	classifier, err := ordertype.LoadFile(cfg.OrderRulesFile)
+	if err != nil {
+		return err
+	}
+	downloader := scraper.NewPDFDownloader(db, log, cfg.DatabasePath)
+	downloader.SetClassifier(classifier)
+
+	changed, err := downloader.ClassifyOrders()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Classified orders, %d changed type\n", changed)
+	return nil
+}
+
+// runFixture copies the raw page stored for a query log into the parser's
+// golden test fixtures
+func runFixture(db *gorm.DB, args []string) error {
+	flags := flag.NewFlagSet("fixture", flag.ExitOnError)
+	dir := flags.String("dir", filepath.Join("tests", "testdata", "parser"), "Directory holding the parser fixtures")
+	flags.Parse(args)
+
+	if flags.NArg() < 1 {
+		return fmt.Errorf("usage: fixture [--dir DIR] <query-log-id> [name]")
+	}
+	id, err := strconv.ParseUint(flags.Arg(0), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid query log id %q", flags.Arg(0))
+	}
+	name := fmt.Sprintf("query_log_%d", id)
+	if flags.NArg() > 1 {
+		name = flags.Arg(1)
+	}
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid fixture name %q: it must not contain path separators", name)
+	}
+
+	repo := database.NewRepository(db)
+	queryLog, err := repo.FindQueryLog(uint(id))
+	if err != nil {
+		return err
+	}
+	rawHTML, err := repo.RawResponse(queryLog)
+	if err != nil {
+		return err
+	}
+	if rawHTML == "" {
+		return fmt.Errorf("query log %d has no stored page", id)
+	}
+
+	path := filepath.Join(*dir, name+".html")
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists", path)
+	}
+	if err := os.WriteFile(path, []byte(rawHTML), 0644); err != nil {
+		return fmt.Errorf("failed to write fixture: %w", err)
+	}
+
+	fmt.Printf("Wrote %s\n", path)
+	fmt.Println("Review it, then record the expected result with: go test ./tests -run TestParserGolden -update")
+	return nil
+}
+
 // startPDFDownloadWorker runs a background worker to download PDFs
-func startPDFDownloadWorker(db *gorm.DB, log *logger.Logger, cfg *config.Config) {
+func startPDFDownloadWorker(db *gorm.DB, up *upstream.Upstream, log *logger.Logger, cfg *config.Config) {
 	ticker := time.NewTicker(30 * time.Minute)
 	defer ticker.Stop()
 
 	downloader := scraper.NewPDFDownloader(db, log, cfg.DatabasePath)
+	downloader.SetUpstream(up)
+	if classifier, err := ordertype.LoadFile(cfg.OrderRulesFile); err != nil {
+		log.Error("Failed to load order rules, using the defaults", "error", err)
+	} else {
+		downloader.SetClassifier(classifier)
+	}
 
 	for {
 		select {
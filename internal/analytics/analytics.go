@@ -0,0 +1,369 @@
+// Package analytics aggregates the stored cases and orders into pendency,
+// hearing, adjournment, disposal and workload figures for the dashboard
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/JustJay7/court-data-fetcher/internal/ordertype"
+	"github.com/JustJay7/court-data-fetcher/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// latestCases selects the newest snapshot of every stored case, so a case
+// scraped many times is counted once
+const latestCases = `SELECT MAX(id) FROM case_infos WHERE deleted_at IS NULL GROUP BY case_number`
+
+// disposed matches the statuses the court uses for finished cases
+const disposed = `(LOWER(status) LIKE '%dispos%' OR LOWER(status) LIKE '%decided%')`
+
+// adjourned matches the orders the classifier labelled as adjournments
+const adjourned = `o.order_type = '` + ordertype.Adjournment + `'`
+
+// dateFloor excludes zero dates, which mean the page showed no date
+var dateFloor = time.Date(1900, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// mostAdjournedLimit is how many cases the adjournment ranking lists
+const mostAdjournedLimit = 10
+
+// workloadWindow is how far ahead judge workloads count listed hearings
+const workloadWindow = 30 * 24 * time.Hour
+
+// AgeBucket is a pendency age range, From inclusive and To exclusive in days.
+// The last bucket has no upper bound.
+type AgeBucket struct {
+	Label string `json:"label"`
+	From  int    `json:"from_days"`
+	To    int    `json:"to_days,omitempty"`
+}
+
+// AgeBuckets are the ranges pending cases are grouped into by age
+var AgeBuckets = []AgeBucket{
+	{Label: "Under 1 year", From: 0, To: 365},
+	{Label: "1-3 years", From: 365, To: 3 * 365},
+	{Label: "3-5 years", From: 3 * 365, To: 5 * 365},
+	{Label: "5-10 years", From: 5 * 365, To: 10 * 365},
+	{Label: "Over 10 years", From: 10 * 365},
+}
+
+// Dashboard holds every figure shown on the analytics page
+type Dashboard struct {
+	GeneratedAt  time.Time        `json:"generated_at"`
+	Cases        int64            `json:"cases"`
+	Pending      int64            `json:"pending"`
+	Pendency     []Pendency       `json:"pendency"`
+	Hearings     HearingIntervals `json:"hearings"`
+	Adjournments Adjournments     `json:"adjournments"`
+	Disposals    []DisposalYear   `json:"disposals"`
+	Judges       []JudgeWorkload  `json:"judges"`
+}
+
+// Pendency is the age distribution of one case type's pending cases, with
+// a count for each of AgeBuckets
+type Pendency struct {
+	CaseType       string  `json:"case_type"`
+	Pending        int64   `json:"pending"`
+	AverageAgeDays float64 `json:"average_age_days"`
+	Buckets        []int64 `json:"buckets"`
+}
+
+// HearingIntervals is the average gap between consecutive dated orders
+type HearingIntervals struct {
+	AverageDays float64           `json:"average_days"`
+	Intervals   int64             `json:"intervals"`
+	ByCaseType  []HearingInterval `json:"by_case_type"`
+}
+
+// HearingInterval is the average gap between hearings of one case type
+type HearingInterval struct {
+	CaseType    string  `json:"case_type"`
+	AverageDays float64 `json:"average_days"`
+	Intervals   int64   `json:"intervals"`
+}
+
+// Adjournments counts orders labelled as adjournments
+type Adjournments struct {
+	Total         int64              `json:"total"`
+	ByCaseType    []AdjournmentCount `json:"by_case_type"`
+	MostAdjourned []AdjournedCase    `json:"most_adjourned"`
+}
+
+// AdjournmentCount is the adjournments among one case type's orders
+type AdjournmentCount struct {
+	CaseType     string `json:"case_type"`
+	Cases        int64  `json:"cases"`
+	Orders       int64  `json:"orders"`
+	Adjournments int64  `json:"adjournments"`
+}
+
+// AdjournedCase is one case and how often it was adjourned
+type AdjournedCase struct {
+	CaseNumber   string `json:"case_number"`
+	CaseType     string `json:"case_type"`
+	Adjournments int64  `json:"adjournments"`
+}
+
+// DisposalYear is how many of the cases filed in a year have been disposed
+// of. DisposalRate is a percentage.
+type DisposalYear struct {
+	Year         string  `json:"year"`
+	Filed        int64   `json:"filed"`
+	Disposed     int64   `json:"disposed"`
+	DisposalRate float64 `json:"disposal_rate"`
+}
+
+// JudgeWorkload is the cases before one judge
+type JudgeWorkload struct {
+	Judge    string `json:"judge"`
+	Cases    int64  `json:"cases"`
+	Pending  int64  `json:"pending"`
+	Disposed int64  `json:"disposed"`
+	// Upcoming counts pending cases listed within the next 30 days
+	Upcoming int64 `json:"upcoming"`
+}
+
+// Service computes the dashboard with SQL and keeps it for ttl
+type Service struct {
+	db     *gorm.DB
+	ttl    time.Duration
+	logger *logger.Logger
+
+	mu        sync.Mutex
+	dashboard *Dashboard
+}
+
+// NewService creates an analytics service. A ttl of 0 computes the
+// dashboard on every request.
+func NewService(db *gorm.DB, ttl time.Duration, logger *logger.Logger) *Service {
+	return &Service{db: db, ttl: ttl, logger: logger}
+}
+
+// Dashboard returns the cached dashboard, computing it when it is older than
+// the ttl or fresh is set
+func (s *Service) Dashboard(ctx context.Context, fresh bool) (*Dashboard, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !fresh && s.dashboard != nil && time.Since(s.dashboard.GeneratedAt) < s.ttl {
+		return s.dashboard, nil
+	}
+
+	start := time.Now()
+	dashboard, err := s.compute(s.db.WithContext(ctx), start)
+	if err != nil {
+		return nil, err
+	}
+	s.logger.Info("Analytics computed", "cases", dashboard.Cases, "duration", time.Since(start))
+	s.dashboard = dashboard
+	return dashboard, nil
+}
+
+func (s *Service) compute(db *gorm.DB, now time.Time) (*Dashboard, error) {
+	dashboard := &Dashboard{GeneratedAt: now}
+
+	err := db.Raw(`SELECT COUNT(*) FROM case_infos WHERE id IN (` + latestCases + `)`).Scan(&dashboard.Cases).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count cases: %w", err)
+	}
+	if dashboard.Pendency, err = s.pendency(db, now); err != nil {
+		return nil, fmt.Errorf("failed to compute pendency: %w", err)
+	}
+	for _, row := range dashboard.Pendency {
+		dashboard.Pending += row.Pending
+	}
+	if dashboard.Hearings, err = s.hearingIntervals(db); err != nil {
+		return nil, fmt.Errorf("failed to compute hearing intervals: %w", err)
+	}
+	if dashboard.Adjournments, err = s.adjournments(db); err != nil {
+		return nil, fmt.Errorf("failed to count adjournments: %w", err)
+	}
+	if dashboard.Disposals, err = s.disposals(db); err != nil {
+		return nil, fmt.Errorf("failed to compute disposal rates: %w", err)
+	}
+	if dashboard.Judges, err = s.judgeWorkloads(db, now); err != nil {
+		return nil, fmt.Errorf("failed to compute judge workloads: %w", err)
+	}
+	return dashboard, nil
+}
+
+// pendency groups pending cases by case type and age bucket
+func (s *Service) pendency(db *gorm.DB, now time.Time) ([]Pendency, error) {
+	bucket := "CASE"
+	for i, b := range AgeBuckets {
+		if b.To > 0 {
+			bucket += fmt.Sprintf(" WHEN age < %d THEN %d", b.To, i)
+		} else {
+			bucket += fmt.Sprintf(" ELSE %d", i)
+		}
+	}
+	bucket += " END"
+
+	var rows []struct {
+		CaseType string
+		Bucket   int
+		Cases    int64
+		TotalAge float64
+	}
+	err := db.Raw(`SELECT case_type, `+bucket+` AS bucket, COUNT(*) AS cases, SUM(age) AS total_age
+		FROM (
+			SELECT case_type, `+s.daysBetween(s.column("filing_date"), s.param())+` AS age
+			FROM case_infos
+			WHERE id IN (`+latestCases+`) AND NOT `+disposed+` AND `+s.column("filing_date")+` > `+s.param()+`
+		) aged
+		GROUP BY case_type, `+bucket+`
+		ORDER BY case_type`, timestamp(now), timestamp(dateFloor)).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var pendency []Pendency
+	totalAge := 0.0
+	for _, row := range rows {
+		if len(pendency) == 0 || pendency[len(pendency)-1].CaseType != row.CaseType {
+			if len(pendency) > 0 {
+				last := &pendency[len(pendency)-1]
+				last.AverageAgeDays = totalAge / float64(last.Pending)
+			}
+			pendency = append(pendency, Pendency{CaseType: row.CaseType, Buckets: make([]int64, len(AgeBuckets))})
+			totalAge = 0
+		}
+		last := &pendency[len(pendency)-1]
+		last.Buckets[row.Bucket] = row.Cases
+		last.Pending += row.Cases
+		totalAge += row.TotalAge
+	}
+	if len(pendency) > 0 {
+		last := &pendency[len(pendency)-1]
+		last.AverageAgeDays = totalAge / float64(last.Pending)
+	}
+	return pendency, nil
+}
+
+// hearingIntervals averages the days between consecutive orders of a case
+func (s *Service) hearingIntervals(db *gorm.DB) (HearingIntervals, error) {
+	var intervals HearingIntervals
+	previous := `LAG(` + s.column("o.order_date") + `) OVER (PARTITION BY o.case_info_id ORDER BY o.order_date)`
+	err := db.Raw(`SELECT case_type, AVG(gap) AS average_days, COUNT(*) AS intervals
+		FROM (
+			SELECT c.case_type, `+s.daysBetween(previous, s.column("o.order_date"))+` AS gap
+			FROM orders o JOIN case_infos c ON c.id = o.case_info_id
+			WHERE c.id IN (`+latestCases+`) AND o.deleted_at IS NULL AND `+s.column("o.order_date")+` > `+s.param()+`
+		) gaps
+		WHERE gap IS NOT NULL
+		GROUP BY case_type
+		ORDER BY case_type`, timestamp(dateFloor)).Scan(&intervals.ByCaseType).Error
+	if err != nil {
+		return intervals, err
+	}
+
+	totalDays := 0.0
+	for _, row := range intervals.ByCaseType {
+		intervals.Intervals += row.Intervals
+		totalDays += row.AverageDays * float64(row.Intervals)
+	}
+	if intervals.Intervals > 0 {
+		intervals.AverageDays = totalDays / float64(intervals.Intervals)
+	}
+	return intervals, nil
+}
+
+// adjournments counts adjournment orders by case type and ranks the cases
+// adjourned most often
+func (s *Service) adjournments(db *gorm.DB) (Adjournments, error) {
+	var adjournments Adjournments
+	err := db.Raw(`SELECT c.case_type, COUNT(DISTINCT c.id) AS cases, COUNT(o.id) AS orders,
+			SUM(CASE WHEN ` + adjourned + ` THEN 1 ELSE 0 END) AS adjournments
+		FROM case_infos c JOIN orders o ON o.case_info_id = c.id AND o.deleted_at IS NULL
+		WHERE c.id IN (` + latestCases + `)
+		GROUP BY c.case_type
+		ORDER BY c.case_type`).Scan(&adjournments.ByCaseType).Error
+	if err != nil {
+		return adjournments, err
+	}
+	for _, row := range adjournments.ByCaseType {
+		adjournments.Total += row.Adjournments
+	}
+
+	err = db.Raw(`SELECT c.case_number, c.case_type, COUNT(*) AS adjournments
+		FROM case_infos c JOIN orders o ON o.case_info_id = c.id AND o.deleted_at IS NULL
+		WHERE c.id IN (`+latestCases+`) AND `+adjourned+`
+		GROUP BY c.case_number, c.case_type
+		ORDER BY adjournments DESC, c.case_number
+		LIMIT ?`, mostAdjournedLimit).Scan(&adjournments.MostAdjourned).Error
+	return adjournments, err
+}
+
+// disposals compares the cases filed in each year with those disposed of
+func (s *Service) disposals(db *gorm.DB) ([]DisposalYear, error) {
+	var years []DisposalYear
+	err := db.Raw(`SELECT filing_year AS year, COUNT(*) AS filed,
+			SUM(CASE WHEN ` + disposed + ` THEN 1 ELSE 0 END) AS disposed
+		FROM case_infos
+		WHERE id IN (` + latestCases + `) AND filing_year <> ''
+		GROUP BY filing_year
+		ORDER BY filing_year`).Scan(&years).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range years {
+		years[i].DisposalRate = 100 * float64(years[i].Disposed) / float64(years[i].Filed)
+	}
+	return years, nil
+}
+
+// judgeWorkloads counts the cases before each judge, busiest first
+func (s *Service) judgeWorkloads(db *gorm.DB, now time.Time) ([]JudgeWorkload, error) {
+	var judges []JudgeWorkload
+	err := db.Raw(`SELECT judge, COUNT(*) AS cases,
+			SUM(CASE WHEN `+disposed+` THEN 0 ELSE 1 END) AS pending,
+			SUM(CASE WHEN `+disposed+` THEN 1 ELSE 0 END) AS disposed,
+			SUM(CASE WHEN NOT `+disposed+` AND `+s.column("next_hearing")+` >= `+s.param()+`
+				AND `+s.column("next_hearing")+` < `+s.param()+` THEN 1 ELSE 0 END) AS upcoming
+		FROM case_infos
+		WHERE id IN (`+latestCases+`) AND judge <> ''
+		GROUP BY judge`, timestamp(now), timestamp(now.Add(workloadWindow))).Scan(&judges).Error
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(judges, func(i, j int) bool {
+		if judges[i].Pending != judges[j].Pending {
+			return judges[i].Pending > judges[j].Pending
+		}
+		return judges[i].Judge < judges[j].Judge
+	})
+	return judges, nil
+}
+
+// column wraps a timestamp column so it can be compared and subtracted.
+// SQLite stores times as text, which julianday turns into days.
+func (s *Service) column(expr string) string {
+	if s.db.Dialector.Name() == "sqlite" {
+		return "julianday(" + expr + ")"
+	}
+	return expr
+}
+
+// param is a timestamp placeholder comparable with column
+func (s *Service) param() string {
+	if s.db.Dialector.Name() == "sqlite" {
+		return "julianday(?)"
+	}
+	return "CAST(? AS timestamptz)"
+}
+
+// daysBetween is the number of days from one column or param to another
+func (s *Service) daysBetween(from, to string) string {
+	if s.db.Dialector.Name() == "sqlite" {
+		return "(" + to + " - " + from + ")"
+	}
+	return "(EXTRACT(EPOCH FROM (" + to + " - " + from + ")) / 86400)"
+}
+
+// timestamp formats t so both databases read it as an instant
+func timestamp(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05+00:00")
+}
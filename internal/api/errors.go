@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/JustJay7/court-data-fetcher/internal/scraper"
+)
+
+// errorStatus maps a scrape error to the HTTP status returned to clients
+func errorStatus(err error) int {
+	switch scraper.ErrorCode(err) {
+	case scraper.CodeInvalidInput:
+		return http.StatusBadRequest
+	case scraper.CodeCaseNotFound:
+		return http.StatusNotFound
+	case scraper.CodeCaptchaFailed, scraper.CodeLayoutChanged:
+		return http.StatusBadGateway
+	case scraper.CodeCourtUnavailable:
+		return http.StatusServiceUnavailable
+	case scraper.CodeTimeout:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// errorMessage returns a user-facing description of a scrape error
+func errorMessage(err error) string {
+	switch scraper.ErrorCode(err) {
+	case scraper.CodeCaseNotFound:
+		return "No records found for the given case details"
+	case scraper.CodeCaptchaFailed:
+		return "The court website rejected the CAPTCHA, please try again"
+	case scraper.CodeLayoutChanged:
+		return "The court website returned a page we could not read"
+	case scraper.CodeCourtUnavailable:
+		return "The court website is currently unavailable"
+	case scraper.CodeTimeout:
+		return "The court website took too long to respond"
+	default:
+		return err.Error()
+	}
+}
@@ -1,7 +1,7 @@
 package api
 
 import (
-	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,31 +11,49 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/JustJay7/court-data-fetcher/internal/analytics"
 	"github.com/JustJay7/court-data-fetcher/internal/cache"
+	"github.com/JustJay7/court-data-fetcher/internal/caseid"
+	"github.com/JustJay7/court-data-fetcher/internal/catalogue"
+	"github.com/JustJay7/court-data-fetcher/internal/causelist"
 	"github.com/JustJay7/court-data-fetcher/internal/config"
+	"github.com/JustJay7/court-data-fetcher/internal/conflicts"
+	"github.com/JustJay7/court-data-fetcher/internal/courtdate"
 	"github.com/JustJay7/court-data-fetcher/internal/database"
 	"github.com/JustJay7/court-data-fetcher/internal/scraper"
+	"github.com/JustJay7/court-data-fetcher/internal/search"
+	"github.com/JustJay7/court-data-fetcher/internal/upstream"
 	"github.com/JustJay7/court-data-fetcher/pkg/logger"
 	"gorm.io/gorm"
 )
 
 // Handlers holds all HTTP handlers
 type Handlers struct {
-	db      *gorm.DB
-	cache   cache.Cache
-	scraper *scraper.Scraper
-	logger  *logger.Logger
-	cfg     *config.Config
+	db         *gorm.DB
+	repo       *database.Repository
+	cache      cache.Cache
+	search     *search.Service
+	conflicts  *conflicts.Checker
+	causeLists *causelist.Service
+	analytics  *analytics.Service
+	upstream   *upstream.Upstream
+	logger     *logger.Logger
+	cfg        *config.Config
 }
 
 // NewHandlers creates a new handlers instance
-func NewHandlers(db *gorm.DB, cache cache.Cache, scraper *scraper.Scraper, logger *logger.Logger, cfg *config.Config) *Handlers {
+func NewHandlers(db *gorm.DB, cacheService cache.Cache, scraper *scraper.Scraper, up *upstream.Upstream, logger *logger.Logger, cfg *config.Config) *Handlers {
 	return &Handlers{
-		db:      db,
-		cache:   cache,
-		scraper: scraper,
-		logger:  logger,
-		cfg:     cfg,
+		db:         db,
+		repo:       database.NewRepository(db),
+		cache:      cacheService,
+		search:     search.NewService(db, cacheService, scraper, logger, cfg),
+		conflicts:  conflicts.NewChecker(db),
+		causeLists: causelist.NewService(db, up, logger, cfg),
+		analytics:  analytics.NewService(db, cfg.AnalyticsCacheTTL, logger),
+		upstream:   up,
+		logger:     logger,
+		cfg:        cfg,
 	}
 }
 
@@ -43,21 +61,29 @@ func NewHandlers(db *gorm.DB, cache cache.Cache, scraper *scraper.Scraper, logge
 func (h *Handlers) HomePage(c *gin.Context) {
 	// Debug: Log when home page is accessed
 	h.logger.Info("Home page accessed", "ip", c.ClientIP())
+
+	caseTypes, err := h.search.Catalogue().Labels()
+	if err != nil {
+		h.logger.Error("Failed to load case types", "error", err)
+		caseTypes = catalogue.DefaultTypes
+	}
 	
 	c.HTML(http.StatusOK, "index.html", gin.H{
 		"title":     "Court Data Fetcher",
 		"courtName": h.cfg.CourtName,
-		"caseTypes": getCaseTypes(),
+		"caseTypes": caseTypes,
 		"years":     getYearRange(),
 	})
 }
 
 // SearchCase handles case search form submission
 func (h *Handlers) SearchCase(c *gin.Context) {
+	// Type and year may be left out when case_number holds the whole case
+	// number, e.g. "CS(OS) 100/2023"
 	var req struct {
-		CaseType   string `form:"case_type" binding:"required"`
+		CaseType   string `form:"case_type"`
 		CaseNumber string `form:"case_number" binding:"required"`
-		FilingYear string `form:"filing_year" binding:"required"`
+		FilingYear string `form:"filing_year"`
 	}
 
 	if err := c.ShouldBind(&req); err != nil {
@@ -67,86 +93,36 @@ func (h *Handlers) SearchCase(c *gin.Context) {
 		return
 	}
 
-	// Check cache first
-	cacheKey := cache.GenerateCacheKey(req.CaseType, req.CaseNumber, req.FilingYear)
-	if cachedCase, found := h.cache.Get(cacheKey); found {
-		h.logger.Info("Cache hit", "key", cacheKey)
-		
-		// Load the query log for this cached case
-		var queryLog database.QueryLog
-		h.db.Where("id = ?", cachedCase.QueryLogID).First(&queryLog)
-		
-		c.HTML(http.StatusOK, "results.html", gin.H{
-			"case":      cachedCase,
-			"queryLog":  queryLog,
-			"fromCache": true,
-		})
-		return
-	}
-
-	// Create query log entry BEFORE scraping
-	queryLog := &database.QueryLog{
+	result, err := h.search.Search(c.Request.Context(), search.Request{
 		CaseType:   req.CaseType,
 		CaseNumber: req.CaseNumber,
 		FilingYear: req.FilingYear,
-		QueryTime:  time.Now(),
 		IPAddress:  c.ClientIP(),
-	}
-
-	// Save initial query log
-	if err := h.db.Create(queryLog).Error; err != nil {
-		h.logger.Error("Failed to create query log", "error", err)
-	}
-
-	// Perform scraping
-	ctx, cancel := context.WithTimeout(context.Background(), h.cfg.ScraperTimeout)
-	defer cancel()
-
-	caseInfo, rawHTML, err := h.scraper.SearchCase(ctx, req.CaseType, req.CaseNumber, req.FilingYear)
-	
-	// Update query log with results
-	queryLog.RawResponse = rawHTML
+		Source:     search.SourceForm,
+		Refresh:    wantsFreshData(c),
+	})
 	if err != nil {
-		queryLog.Success = false
-		queryLog.ErrorMessage = err.Error()
-		h.db.Save(queryLog)
-		
-		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
-			"error": "Failed to fetch case data: " + err.Error(),
+		c.HTML(errorStatus(err), "error.html", gin.H{
+			"error":     "Failed to fetch case data: " + errorMessage(err),
+			"errorCode": scraper.ErrorCode(err),
 		})
 		return
 	}
 
-	// Update query log as successful
-	queryLog.Success = true
-	h.db.Save(queryLog)
-
-	// Save case info to database with all relationships
-	caseInfo.QueryLogID = queryLog.ID
-	if err := h.db.Create(caseInfo).Error; err != nil {
-		h.logger.Error("Failed to save case info", "error", err)
+	// The refresh button resubmits the case in the form the court uses
+	if id, err := caseid.FromInput(req.CaseType, req.CaseNumber, req.FilingYear); err == nil {
+		req.CaseType, req.CaseNumber, req.FilingYear = id.Type, id.Number, id.Year
 	}
 
-	// Save parties
-	for i := range caseInfo.Parties {
-		caseInfo.Parties[i].CaseInfoID = caseInfo.ID
-		h.db.Create(&caseInfo.Parties[i])
-	}
-
-	// Save orders
-	for i := range caseInfo.Orders {
-		caseInfo.Orders[i].CaseInfoID = caseInfo.ID
-		h.db.Create(&caseInfo.Orders[i])
-	}
-
-	// Cache the result
-	h.cache.Set(cacheKey, caseInfo)
-
 	// Render results with query log
 	c.HTML(http.StatusOK, "results.html", gin.H{
-		"case":      caseInfo,
-		"queryLog":  queryLog,
-		"fromCache": false,
+		"case":      h.withListing(result.CaseInfo),
+		"queryLog":  result.QueryLog,
+		"fromCache": result.FromCache,
+		"cachedAt":  result.CachedAt,
+		"age":       time.Since(result.CachedAt).Round(time.Second),
+		"stale":     result.Stale,
+		"query":     req,
 	})
 }
 
@@ -184,7 +160,7 @@ func (h *Handlers) ViewResults(c *gin.Context) {
 		}
 		
 		c.HTML(http.StatusOK, "results.html", gin.H{
-			"case":     &caseInfo,
+			"case":     h.withListing(&caseInfo),
 			"queryLog": &queryLog,
 		})
 		return
@@ -197,7 +173,7 @@ func (h *Handlers) ViewResults(c *gin.Context) {
 	}
 
 	c.HTML(http.StatusOK, "results.html", gin.H{
-		"case":     &caseInfo,
+		"case":     h.withListing(&caseInfo),
 		"queryLog": &queryLog,
 	})
 }
@@ -208,45 +184,296 @@ func (h *Handlers) GetCaseAPI(c *gin.Context) {
 	caseNumber := c.Query("number")
 	filingYear := c.Query("year")
 
-	if caseType == "" || caseNumber == "" || filingYear == "" {
+	// ?case=CS(OS) 100/2023 stands in for the three parameters
+	if whole := c.Query("case"); whole != "" {
+		caseType, caseNumber, filingYear = "", whole, ""
+	} else if caseType == "" || caseNumber == "" || filingYear == "" {
 		c.JSON(http.StatusBadRequest, gin.H{
+			"success":    false,
+			"error":      "Missing required parameters: type, number, year or case",
+			"error_code": scraper.CodeInvalidInput,
+		})
+		return
+	}
+
+	result, err := h.search.Search(c.Request.Context(), search.Request{
+		CaseType:   caseType,
+		CaseNumber: caseNumber,
+		FilingYear: filingYear,
+		IPAddress:  c.ClientIP(),
+		Source:     search.SourceAPI,
+		Refresh:    wantsFreshData(c),
+	})
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"success":    false,
+			"error":      errorMessage(err),
+			"error_code": scraper.ErrorCode(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":      true,
+		"data":         h.withListing(result.CaseInfo),
+		"fromCache":    result.FromCache,
+		"cached_at":    result.CachedAt,
+		"age":          int64(time.Since(result.CachedAt).Seconds()),
+		"stale":        result.Stale,
+		"query_log_id": result.QueryLog.ID,
+	})
+}
+
+// GetCaseByCNR returns the latest stored snapshot of the case with a CNR
+func (h *Handlers) GetCaseByCNR(c *gin.Context) {
+	cnr, err := caseid.ParseCNR(c.Param("cnr"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":    false,
+			"error":      err.Error(),
+			"error_code": scraper.CodeInvalidInput,
+		})
+		return
+	}
+
+	caseInfo, err := h.repo.FindLatestCaseByCNR(cnr.String())
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			h.logger.Error("Failed to load case by CNR", "cnr", cnr.String(), "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"error":   "Failed to load case",
+			})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{
 			"success": false,
-			"error":   "Missing required parameters: type, number, year",
+			"error":   "No stored case with CNR " + cnr.String(),
 		})
 		return
 	}
 
-	// Check cache
-	cacheKey := cache.GenerateCacheKey(caseType, caseNumber, filingYear)
-	if cachedCase, found := h.cache.Get(cacheKey); found {
-		c.JSON(http.StatusOK, gin.H{
-			"success":   true,
-			"data":      cachedCase,
-			"fromCache": true,
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"cnr":     cnr,
+		"data":    caseInfo,
+	})
+}
+
+// ListOrders returns stored orders filtered by ?type= (see ordertype),
+// ?case= and ?min_confidence=, newest first
+func (h *Handlers) ListOrders(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	filter := database.OrderFilter{Type: c.Query("type"), Limit: limit, Offset: (page - 1) * limit}
+
+	if input := c.Query("case"); input != "" {
+		id, err := caseid.Parse(input)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success":    false,
+				"error":      err.Error(),
+				"error_code": scraper.CodeInvalidInput,
+			})
+			return
+		}
+		filter.CaseNumber = id.String()
+	}
+	if input := c.Query("min_confidence"); input != "" {
+		confidence, err := strconv.ParseFloat(input, 64)
+		if err != nil || confidence < 0 || confidence > 1 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success":    false,
+				"error":      "min_confidence must be between 0 and 1",
+				"error_code": scraper.CodeInvalidInput,
+			})
+			return
+		}
+		filter.MinConfidence = confidence
+	}
+
+	orders, total, err := h.repo.FindOrders(filter)
+	if err != nil {
+		h.logger.Error("Failed to list orders", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Failed to list orders",
 		})
 		return
 	}
 
-	// Perform scraping
-	ctx, cancel := context.WithTimeout(context.Background(), h.cfg.ScraperTimeout)
-	defer cancel()
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    orders,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		},
+	})
+}
+
+// GetCauseList returns the cause list for ?date=YYYY-MM-DD, today by
+// default, fetching it from the court when it isn't stored or refresh=true
+func (h *Handlers) GetCauseList(c *gin.Context) {
+	day := causelist.Today()
+	if date := c.Query("date"); date != "" {
+		parsed, err := time.ParseInLocation(causelist.DateLayout, date, courtdate.IST)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success":    false,
+				"error":      "Invalid date, expected YYYY-MM-DD",
+				"error_code": scraper.CodeInvalidInput,
+			})
+			return
+		}
+		day = parsed
+	}
+
+	entries, err := h.causeLists.Entries(day)
+	if err == nil && (len(entries) == 0 || wantsFreshData(c)) {
+		entries, err = h.causeLists.Fetch(c.Request.Context(), day)
+	}
+	if err != nil {
+		h.logger.Error("Failed to load cause list", "date", day.Format(causelist.DateLayout), "error", err)
+		c.JSON(errorStatus(err), gin.H{
+			"success":    false,
+			"error":      errorMessage(err),
+			"error_code": scraper.ErrorCode(err),
+		})
+		return
+	}
+
+	matched := 0
+	for _, entry := range entries {
+		if entry.CaseInfoID != nil {
+			matched++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"date":    day.Format(causelist.DateLayout),
+		"data":    entries,
+		"matched": matched,
+	})
+}
 
-	caseInfo, _, err := h.scraper.SearchCase(ctx, caseType, caseNumber, filingYear)
+// AnalyticsPage renders the case ageing and order timeline dashboard
+func (h *Handlers) AnalyticsPage(c *gin.Context) {
+	dashboard, err := h.analytics.Dashboard(c.Request.Context(), wantsFreshData(c))
 	if err != nil {
+		h.logger.Error("Failed to compute analytics", "error", err)
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
+			"error": "Failed to compute analytics",
+		})
+		return
+	}
+
+	c.HTML(http.StatusOK, "analytics.html", gin.H{
+		"title":      "Analytics",
+		"dashboard":  dashboard,
+		"ageBuckets": analytics.AgeBuckets,
+	})
+}
+
+// GetAnalytics returns the dashboard, or one section of it when :section is
+// pendency, hearings, adjournments, disposals or judges
+func (h *Handlers) GetAnalytics(c *gin.Context) {
+	dashboard, err := h.analytics.Dashboard(c.Request.Context(), wantsFreshData(c))
+	if err != nil {
+		h.logger.Error("Failed to compute analytics", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"success": false,
-			"error":   err.Error(),
+			"error":   "Failed to compute analytics",
 		})
 		return
 	}
 
-	// Cache result
-	h.cache.Set(cacheKey, caseInfo)
+	var data interface{}
+	switch section := c.Param("section"); section {
+	case "":
+		data = dashboard
+	case "pendency":
+		data = gin.H{"age_buckets": analytics.AgeBuckets, "case_types": dashboard.Pendency}
+	case "hearings":
+		data = dashboard.Hearings
+	case "adjournments":
+		data = dashboard.Adjournments
+	case "disposals":
+		data = dashboard.Disposals
+	case "judges":
+		data = dashboard.Judges
+	default:
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"error":   fmt.Sprintf("Unknown analytics section %q", section),
+		})
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"success":   true,
-		"data":      caseInfo,
-		"fromCache": false,
+		"success":      true,
+		"generated_at": dashboard.GeneratedAt,
+		"data":         data,
+	})
+}
+
+// withListing returns caseInfo with its entry on today's cause list. It
+// copies a listed case, as caseInfo may be shared with the cache.
+func (h *Handlers) withListing(caseInfo *database.CaseInfo) *database.CaseInfo {
+	listings, err := h.causeLists.Listings(causelist.Today(), []string{caseInfo.CaseNumber})
+	if err != nil {
+		h.logger.Error("Failed to load cause list entries", "error", err)
+		return caseInfo
+	}
+	listing, ok := listings[caseInfo.CaseNumber]
+	if !ok {
+		return caseInfo
+	}
+	listed := *caseInfo
+	listed.ListedToday = listing
+	return &listed
+}
+
+// markListedToday sets ListedToday on the cases on today's cause list
+func (h *Handlers) markListedToday(cases []database.CaseInfo) {
+	caseNumbers := make([]string, len(cases))
+	for i := range cases {
+		caseNumbers[i] = cases[i].CaseNumber
+	}
+	listings, err := h.causeLists.Listings(causelist.Today(), caseNumbers)
+	if err != nil {
+		h.logger.Error("Failed to load cause list entries", "error", err)
+		return
+	}
+	for i := range cases {
+		cases[i].ListedToday = listings[cases[i].CaseNumber]
+	}
+}
+
+// ListCaseTypes returns the case types offered by the court's search form.
+// from_court is false until the form has been scraped once.
+func (h *Handlers) ListCaseTypes(c *gin.Context) {
+	types, scraped, err := h.search.Catalogue().List()
+	if err != nil {
+		h.logger.Error("Failed to load case types", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load case types",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":    true,
+		"data":       types,
+		"from_court": scraped,
 	})
 }
 
@@ -268,6 +495,7 @@ func (h *Handlers) ListCasesAPI(c *gin.Context) {
 		Offset(offset).Limit(limit).
 		Order("created_at DESC").
 		Find(&cases)
+	h.markListedToday(cases)
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
@@ -283,42 +511,43 @@ func (h *Handlers) ListCasesAPI(c *gin.Context) {
 // BulkSearchAPI handles bulk case searches
 func (h *Handlers) BulkSearchAPI(c *gin.Context) {
 	var req struct {
-		Queries []scraper.CaseQuery `json:"queries" binding:"required,min=1,max=10"`
+		Queries []search.Request `json:"queries" binding:"required,min=1,max=10"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"success": false,
-			"error":   err.Error(),
+			"success":    false,
+			"error":      err.Error(),
+			"error_code": scraper.CodeInvalidInput,
 		})
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), h.cfg.ScraperTimeout*time.Duration(len(req.Queries)))
-	defer cancel()
-
-	results, err := h.scraper.SearchCaseConcurrent(ctx, req.Queries)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"success": false,
-			"error":   err.Error(),
-		})
-		return
+	refresh := wantsFreshData(c)
+	for i := range req.Queries {
+		req.Queries[i].IPAddress = c.ClientIP()
+		req.Queries[i].Source = search.SourceBulk
+		req.Queries[i].Refresh = refresh
 	}
 
+	results := h.search.SearchBulk(c.Request.Context(), req.Queries)
+
 	// Format results
 	var responseData []gin.H
 	for _, result := range results {
 		data := gin.H{
-			"query": result.Query,
+			"query": result.Request,
 		}
 
 		if result.Error != nil {
 			data["success"] = false
-			data["error"] = result.Error.Error()
+			data["error"] = errorMessage(result.Error)
+			data["error_code"] = scraper.ErrorCode(result.Error)
 		} else {
 			data["success"] = true
-			data["data"] = result.CaseInfo
+			data["data"] = result.Result.CaseInfo
+			data["fromCache"] = result.Result.FromCache
+			data["query_log_id"] = result.Result.QueryLog.ID
 		}
 
 		responseData = append(responseData, data)
@@ -347,9 +576,41 @@ func (h *Handlers) HealthCheck(c *gin.Context) {
 // CacheStats returns cache statistics
 func (h *Handlers) CacheStats(c *gin.Context) {
 	stats := h.cache.Stats()
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"stats":     stats,
+		"coalesced": h.search.Metrics().Coalesced,
+	})
+}
+
+// SearchMetrics returns counters for searches across the form, API and bulk endpoints
+func (h *Handlers) SearchMetrics(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"metrics": h.search.Metrics(),
+		"proxies": h.upstream.Proxies().Status(),
+	})
+}
+
+// InvalidateCache removes a single entry, e.g. case:CS:1234:2023
+func (h *Handlers) InvalidateCache(c *gin.Context) {
+	key := c.Param("key")
+	h.cache.Delete(key)
+
+	h.logger.Info("Cache entry invalidated", "key", key, "ip", c.ClientIP())
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"key":     key,
+	})
+}
+
+// ClearCache removes every cached entry
+func (h *Handlers) ClearCache(c *gin.Context) {
+	h.cache.Clear()
+
+	h.logger.Info("Cache cleared", "ip", c.ClientIP())
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"stats":   stats,
 	})
 }
 
@@ -421,10 +682,8 @@ func (h *Handlers) DownloadPDF(c *gin.Context) {
 		return
 	}
 
-	// Create HTTP client with timeout
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
+	// Fetch through the shared proxies and per-host limits
+	client := h.upstream.Client(30 * time.Second)
 
 	// Create request with proper headers
 	req, err := http.NewRequest("GET", pdfURL, nil)
@@ -531,9 +790,237 @@ func (h *Handlers) GetRawResponse(c *gin.Context) {
 		return
 	}
 
+	rawHTML, err := h.repo.RawResponse(&queryLog)
+	if err != nil {
+		h.logger.Error("Failed to read raw response", "id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to read raw response",
+		})
+		return
+	}
+	if rawHTML == "" {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Raw response not kept for this query",
+		})
+		return
+	}
+
 	// Return raw HTML
 	c.Header("Content-Type", "text/html; charset=utf-8")
-	c.String(http.StatusOK, queryLog.RawResponse)
+	c.String(http.StatusOK, rawHTML)
+}
+
+// GetDebugArtifacts lists the debug bundle captured for a failed query
+func (h *Handlers) GetDebugArtifacts(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid ID",
+		})
+		return
+	}
+
+	artifacts, err := h.repo.FindDebugArtifacts(uint(id))
+	if err != nil {
+		h.logger.Error("Failed to load debug artifacts", "id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load debug artifacts",
+		})
+		return
+	}
+
+	data := make([]gin.H, len(artifacts))
+	for i, artifact := range artifacts {
+		data[i] = gin.H{
+			"id":           artifact.ID,
+			"kind":         artifact.Kind,
+			"content_type": artifact.ContentType,
+			"size":         artifact.Size,
+			"created_at":   artifact.CreatedAt,
+			"url":          fmt.Sprintf("/api/logs/%d/artifacts/%d", id, artifact.ID),
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    data,
+	})
+}
+
+// GetDebugArtifact serves one file of a debug bundle
+func (h *Handlers) GetDebugArtifact(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid ID",
+		})
+		return
+	}
+	artifactID, err := strconv.ParseUint(c.Param("artifactID"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid artifact ID",
+		})
+		return
+	}
+
+	artifact, err := h.repo.FindDebugArtifact(uint(id), uint(artifactID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Artifact not found",
+		})
+		return
+	}
+
+	if artifact.Kind == database.ArtifactHAR {
+		c.Header("Content-Disposition", fmt.Sprintf("inline; filename=query_%d.har", id))
+	}
+	c.Data(http.StatusOK, artifact.ContentType, artifact.Data)
+}
+
+// GetPartyCases lists the cases of the person a party resolved to
+func (h *Handlers) GetPartyCases(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid ID",
+		})
+		return
+	}
+
+	person, err := h.repo.FindPerson(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Party not found",
+		})
+		return
+	}
+
+	cases, err := h.repo.FindCasesForPerson(person.ID)
+	if err != nil {
+		h.logger.Error("Failed to load party cases", "id", id, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load cases",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"party":   person,
+		"data":    cases,
+	})
+}
+
+// GetAdvocateCases lists an advocate's cases. Advocates are addressed by
+// enrolment code, or by ID under id/ when the court never showed their
+// code, so numeric codes are never mistaken for IDs.
+func (h *Handlers) GetAdvocateCases(c *gin.Context) {
+	path := strings.TrimPrefix(c.Param("code"), "/")
+	if !strings.HasSuffix(path, "/cases") {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Not found",
+		})
+		return
+	}
+	code := strings.TrimSuffix(path, "/cases")
+
+	var advocate *database.Advocate
+	var err error
+	if rawID, ok := strings.CutPrefix(code, "id/"); ok {
+		var id uint64
+		if id, err = strconv.ParseUint(rawID, 10, 32); err == nil {
+			advocate, err = h.repo.FindAdvocate(uint(id))
+		}
+	} else {
+		advocate, err = h.repo.FindAdvocateByCode(code)
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Advocate not found",
+		})
+		return
+	}
+
+	cases, err := h.repo.FindCasesForAdvocate(advocate.ID)
+	if err != nil {
+		h.logger.Error("Failed to load advocate cases", "code", code, "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load cases",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":  true,
+		"advocate": advocate,
+		"data":     cases,
+	})
+}
+
+// CheckConflicts matches prospective parties against stored cases and
+// records the check
+func (h *Handlers) CheckConflicts(c *gin.Context) {
+	var req conflicts.Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   "Invalid request: " + err.Error(),
+		})
+		return
+	}
+	req.IPAddress = c.ClientIP()
+
+	check, err := h.conflicts.Check(req)
+	if errors.Is(err, conflicts.ErrNoNames) || errors.Is(err, conflicts.ErrTooManyNames) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"error":   err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Conflict check failed", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"error":   "Conflict check failed",
+		})
+		return
+	}
+
+	h.logger.Info("Conflict check", "id", check.ID, "names", len(check.Names), "matches", check.MatchCount)
+	c.JSON(http.StatusOK, gin.H{
+		"success":     true,
+		"check_id":    check.ID,
+		"match_count": check.MatchCount,
+		"data":        check.Results,
+	})
+}
+
+// ListConflictChecks returns the audit trail of conflict checks
+func (h *Handlers) ListConflictChecks(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset := (page - 1) * limit
+
+	checks, total, err := h.conflicts.List(offset, limit)
+	if err != nil {
+		h.logger.Error("Failed to load conflict checks", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to load conflict checks",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    checks,
+		"pagination": gin.H{
+			"page":  page,
+			"limit": limit,
+			"total": total,
+		},
+	})
 }
 
 // ViewLogs displays query logs page
@@ -563,9 +1050,20 @@ func (h *Handlers) ViewLogs(c *gin.Context) {
 	prevPage := page - 1
 	nextPage := page + 1
 
+	// Failed queries with a debug bundle get a link to it
+	ids := make([]uint, len(logs))
+	for i, queryLog := range logs {
+		ids[i] = queryLog.ID
+	}
+	artifacts, err := h.repo.FindDebugArtifactsFor(ids)
+	if err != nil {
+		h.logger.Error("Failed to load debug artifacts", "error", err)
+	}
+
 	c.HTML(http.StatusOK, "logs.html", gin.H{
-		"title": "Query Logs",
-		"logs":  logs,
+		"title":     "Query Logs",
+		"logs":      logs,
+		"artifacts": artifacts,
 		"pagination": gin.H{
 			"page":       page,
 			"limit":      limit,
@@ -579,11 +1077,25 @@ func (h *Handlers) ViewLogs(c *gin.Context) {
 
 // Helper functions
 
-func getCaseTypes() []string {
-	return []string{
-		"BAIL APPLN.", "CS", "CC", "CRL.M.C", "CRL.A", "CRL.REV.P",
-		"FAO", "RFA", "RSA", "CR", "EXEC",
+// wantsFreshData reports whether the client asked to bypass the cache, via
+// refresh=true or a Cache-Control: no-cache or no-store request header.
+// max-age=0 is ignored because browsers send it on an ordinary reload.
+func wantsFreshData(c *gin.Context) bool {
+	refresh := c.Query("refresh")
+	if refresh == "" {
+		refresh = c.PostForm("refresh")
+	}
+	if fresh, _ := strconv.ParseBool(refresh); fresh {
+		return true
+	}
+
+	for _, directive := range strings.Split(c.GetHeader("Cache-Control"), ",") {
+		switch strings.ToLower(strings.TrimSpace(directive)) {
+		case "no-cache", "no-store":
+			return true
+		}
 	}
+	return false
 }
 
 func getYearRange() []string {
@@ -7,14 +7,15 @@ import (
 	"github.com/JustJay7/court-data-fetcher/internal/cache"
 	"github.com/JustJay7/court-data-fetcher/internal/config"
 	"github.com/JustJay7/court-data-fetcher/internal/scraper"
+	"github.com/JustJay7/court-data-fetcher/internal/upstream"
 	"github.com/JustJay7/court-data-fetcher/pkg/logger"
 	"gorm.io/gorm"
 )
 
 // SetupRoutes configures all application routes
-func SetupRoutes(router *gin.Engine, db *gorm.DB, cache cache.Cache, scraper *scraper.Scraper, logger *logger.Logger, cfg *config.Config) {
+func SetupRoutes(router *gin.Engine, db *gorm.DB, cache cache.Cache, scraper *scraper.Scraper, up *upstream.Upstream, logger *logger.Logger, cfg *config.Config) {
 	// Create handlers
-	h := NewHandlers(db, cache, scraper, logger, cfg)
+	h := NewHandlers(db, cache, scraper, up, logger, cfg)
 
 	// Serve static files
 	router.Static("/static", "./web/static")
@@ -42,22 +43,44 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB, cache cache.Cache, scraper *sc
 	router.GET("/results/:id", h.ViewResults)
 	router.GET("/captcha", h.CaptchaPage)
 	router.GET("/logs", h.ViewLogs)
+	router.GET("/analytics", h.AnalyticsPage)
 
 	// API routes
 	api := router.Group("/api")
 	{
-		// Health check
+		// Health check and metrics
 		api.GET("/health", h.HealthCheck)
+		api.GET("/metrics", h.SearchMetrics)
 
 		// Case endpoints
 		api.GET("/case", h.GetCaseAPI)
+		api.GET("/case/by-cnr/:cnr", h.GetCaseByCNR)
 		api.GET("/cases", h.ListCasesAPI)
+		api.GET("/case-types", h.ListCaseTypes)
+		api.GET("/orders", h.ListOrders)
+		api.GET("/causelist", h.GetCauseList)
+
+		// Pendency, hearing, adjournment, disposal and judge workload figures
+		api.GET("/analytics", h.GetAnalytics)
+		api.GET("/analytics/:section", h.GetAnalytics)
 		
-		// Cache stats
+		// Cache stats and invalidation
 		api.GET("/cache/stats", h.CacheStats)
+		api.DELETE("/cache/:key", h.InvalidateCache)
+		api.DELETE("/cache", h.ClearCache)
 		
 		// Concurrent search
 		api.POST("/cases/bulk", h.BulkSearchAPI)
+
+		// Cases by litigant or advocate. Enrolment codes contain slashes,
+		// e.g. /api/advocates/D/1234/2010/cases, so the advocate route is a catch-all.
+		// Advocates without a code are addressed as /api/advocates/id/:id/cases
+		api.GET("/parties/:id/cases", h.GetPartyCases)
+		api.GET("/advocates/*code", h.GetAdvocateCases)
+
+		// Conflict-of-interest checks and their audit trail
+		api.POST("/conflicts/check", h.CheckConflicts)
+		api.GET("/conflicts/checks", h.ListConflictChecks)
 		
 		// CAPTCHA endpoints
 		api.GET("/captcha/:id", h.GetCaptcha)
@@ -69,6 +92,8 @@ func SetupRoutes(router *gin.Engine, db *gorm.DB, cache cache.Cache, scraper *sc
 		// Query logs
 		api.GET("/logs", h.GetQueryLogs)
 		api.GET("/logs/:id/raw", h.GetRawResponse)
+		api.GET("/logs/:id/artifacts", h.GetDebugArtifacts)
+		api.GET("/logs/:id/artifacts/:artifactID", h.GetDebugArtifact)
 	}
 
 	// Load HTML templates
@@ -1,118 +1,344 @@
 package cache
 
 import (
+	"container/list"
 	"encoding/json"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/patrickmn/go-cache"
+	"github.com/JustJay7/court-data-fetcher/internal/caseid"
 	"github.com/JustJay7/court-data-fetcher/internal/database"
 )
 
 type Cache interface {
 	Get(key string) (*database.CaseInfo, bool)
+	// Lookup is like Get but also reports when the value was cached
+	Lookup(key string) (*database.CaseInfo, time.Time, bool)
 	Set(key string, value *database.CaseInfo) error
+	// IsNotFound reports whether key was recently searched without results
+	IsNotFound(key string) bool
+	// SetNotFound records that a search for key returned no records
+	SetNotFound(key string) error
 	Delete(key string)
 	Clear()
 	Stats() CacheStats
 }
 
 type CacheStats struct {
-	Hits       int64     `json:"hits"`
-	Misses     int64     `json:"misses"`
-	Size       int       `json:"size"`
-	LastAccess time.Time `json:"last_access"`
+	Hits           int64     `json:"hits"`
+	Misses         int64     `json:"misses"`
+	NegativeHits   int64     `json:"negative_hits"`
+	NegativeMisses int64     `json:"negative_misses"`
+	Size           int       `json:"size"`
+	NegativeSize   int       `json:"negative_size"`
+	Evictions      int64     `json:"evictions"`
+	Bytes          int64     `json:"bytes"`
+	LastAccess     time.Time `json:"last_access"`
+	// Tiers holds per-layer statistics for a TieredCache
+	Tiers []CacheStats `json:"tiers,omitempty"`
 }
 
+// LRUCache is an in-memory cache that evicts the least recently used
+// entries once it holds more than maxSize items or maxBytes of serialised
+// case data. Keys are spread over independently locked shards so readers
+// of different keys don't contend.
 type LRUCache struct {
-	cache      *cache.Cache
-	mu         sync.RWMutex
-	stats      CacheStats
-	maxSize    int
+	shards      []*lruShard
+	ttl         time.Duration
+	negativeTTL time.Duration
+	maxAge      time.Duration // 0 leaves entries to their TTL
+
+	hits           atomic.Int64
+	misses         atomic.Int64
+	negativeHits   atomic.Int64
+	negativeMisses atomic.Int64
+	evictions      atomic.Int64
+	lastAccess     atomic.Int64
+}
+
+// lruShard is a doubly linked list ordered by recency plus a map into it
+type lruShard struct {
+	mu       sync.Mutex
+	items    map[string]*list.Element
+	order    *list.List // Front is the most recently used entry
+	maxItems int
+	maxBytes int64
+	bytes    int64
+	negative int
+}
+
+type lruEntry struct {
+	key       string
+	value     *database.CaseInfo
+	notFound  bool
+	size      int64
+	cachedAt  time.Time
+	expiresAt time.Time
+}
+
+// negativeEntrySize approximates the memory held by a not-found marker
+const negativeEntrySize = 64
+
+// NewCache creates an in-memory cache limited by item count only.
+// Not-found results are kept for negativeTTL, which should be much shorter
+// than ttl.
+func NewCache(maxSize int, ttl, negativeTTL time.Duration) Cache {
+	return NewSizedCache(maxSize, 0, ttl, negativeTTL)
 }
 
-func NewCache(maxSize int, ttl time.Duration) Cache {
-	return &LRUCache{
-		cache:   cache.New(ttl, ttl*2),
-		maxSize: maxSize,
-		stats:   CacheStats{},
+// NewSizedCache creates an in-memory cache limited by item count and by
+// the serialised size of cached cases. A maxBytes of 0 disables the byte limit.
+func NewSizedCache(maxSize int, maxBytes int64, ttl, negativeTTL time.Duration) *LRUCache {
+	if maxSize < 1 {
+		maxSize = 1
 	}
+
+	// Small caches use a single shard so eviction order is exact
+	shardCount := maxSize / 256
+	if shardCount < 1 {
+		shardCount = 1
+	}
+	if shardCount > 16 {
+		shardCount = 16
+	}
+
+	c := &LRUCache{
+		shards:      make([]*lruShard, shardCount),
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+	}
+	for i := range c.shards {
+		c.shards[i] = &lruShard{
+			items:    make(map[string]*list.Element),
+			order:    list.New(),
+			maxItems: (maxSize + shardCount - 1) / shardCount,
+			maxBytes: (maxBytes + int64(shardCount) - 1) / int64(shardCount),
+		}
+	}
+	return c
 }
 
 func (c *LRUCache) Get(key string) (*database.CaseInfo, bool) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	value, _, found := c.Lookup(key)
+	return value, found
+}
 
-	c.stats.LastAccess = time.Now()
+func (c *LRUCache) Lookup(key string) (*database.CaseInfo, time.Time, bool) {
+	c.lastAccess.Store(time.Now().UnixNano())
 
-	if data, found := c.cache.Get(key); found {
-		c.stats.Hits++
-		if caseInfo, ok := data.(*database.CaseInfo); ok {
-			return caseInfo, true
-		}
+	if entry, found := c.shard(key).get(key, false); found {
+		c.hits.Add(1)
+		return entry.value, entry.cachedAt, true
 	}
 
-	c.stats.Misses++
-	return nil, false
+	c.misses.Add(1)
+	return nil, time.Time{}, false
 }
 
 func (c *LRUCache) Set(key string, value *database.CaseInfo) error {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	return c.setAt(key, value, time.Now())
+}
 
-	if c.cache.ItemCount() >= c.maxSize {
-		c.removeOldest()
+// setAt stores a value that was originally cached at cachedAt, so entries
+// copied from another tier keep their age and expiry
+func (c *LRUCache) setAt(key string, value *database.CaseInfo, cachedAt time.Time) error {
+	data, err := SerializeCaseInfo(value)
+	if err != nil {
+		return err
 	}
 
-	c.cache.Set(key, value, cache.DefaultExpiration)
-	return nil
+	return c.add(&lruEntry{
+		key:       key,
+		value:     value,
+		size:      int64(len(key) + len(data)),
+		cachedAt:  cachedAt,
+		expiresAt: c.expiry(cachedAt.Add(c.ttl)),
+	})
+}
+
+// SetMaxAge bounds how long an entry is held after it is stored here,
+// whatever its original age. Call it before the cache is used.
+func (c *LRUCache) SetMaxAge(maxAge time.Duration) {
+	c.maxAge = maxAge
+}
+
+// expiry applies maxAge to an entry's natural expiry
+func (c *LRUCache) expiry(expiresAt time.Time) time.Time {
+	if c.maxAge <= 0 {
+		return expiresAt
+	}
+	if limit := time.Now().Add(c.maxAge); limit.Before(expiresAt) {
+		return limit
+	}
+	return expiresAt
+}
+
+func (c *LRUCache) IsNotFound(key string) bool {
+	if _, found := c.shard(key).get(key, true); found {
+		c.negativeHits.Add(1)
+		return true
+	}
+
+	c.negativeMisses.Add(1)
+	return false
+}
+
+func (c *LRUCache) SetNotFound(key string) error {
+	now := time.Now()
+	return c.add(&lruEntry{
+		key:       key,
+		notFound:  true,
+		size:      int64(len(key) + negativeEntrySize),
+		cachedAt:  now,
+		expiresAt: c.expiry(now.Add(c.negativeTTL)),
+	})
 }
 
 func (c *LRUCache) Delete(key string) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	shard := c.shard(key)
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
 
-	c.cache.Delete(key)
+	if elem, ok := shard.items[key]; ok {
+		shard.remove(elem)
+	}
 }
 
 func (c *LRUCache) Clear() {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	for _, shard := range c.shards {
+		shard.mu.Lock()
+		shard.items = make(map[string]*list.Element)
+		shard.order.Init()
+		shard.bytes = 0
+		shard.negative = 0
+		shard.mu.Unlock()
+	}
 
-	c.cache.Flush()
-	c.stats = CacheStats{}
+	c.hits.Store(0)
+	c.misses.Store(0)
+	c.negativeHits.Store(0)
+	c.negativeMisses.Store(0)
+	c.evictions.Store(0)
+	c.lastAccess.Store(0)
 }
 
 func (c *LRUCache) Stats() CacheStats {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	stats := CacheStats{
+		Hits:           c.hits.Load(),
+		Misses:         c.misses.Load(),
+		NegativeHits:   c.negativeHits.Load(),
+		NegativeMisses: c.negativeMisses.Load(),
+		Evictions:      c.evictions.Load(),
+	}
+	if lastAccess := c.lastAccess.Load(); lastAccess != 0 {
+		stats.LastAccess = time.Unix(0, lastAccess)
+	}
+
+	for _, shard := range c.shards {
+		shard.mu.Lock()
+		stats.Size += shard.order.Len() - shard.negative
+		stats.NegativeSize += shard.negative
+		stats.Bytes += shard.bytes
+		shard.mu.Unlock()
+	}
+
+	return stats
+}
+
+func (c *LRUCache) shard(key string) *lruShard {
+	if len(c.shards) == 1 {
+		return c.shards[0]
+	}
+
+	// FNV-1a
+	hash := uint32(2166136261)
+	for i := 0; i < len(key); i++ {
+		hash ^= uint32(key[i])
+		hash *= 16777619
+	}
+	return c.shards[hash%uint32(len(c.shards))]
+}
+
+func (c *LRUCache) add(entry *lruEntry) error {
+	shard := c.shard(entry.key)
+	if shard.maxBytes > 0 && entry.size > shard.maxBytes {
+		return fmt.Errorf("cache entry of %d bytes exceeds limit of %d", entry.size, shard.maxBytes)
+	}
 
-	c.stats.Size = c.cache.ItemCount()
-	return c.stats
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	if elem, ok := shard.items[entry.key]; ok {
+		shard.remove(elem)
+	}
+
+	shard.items[entry.key] = shard.order.PushFront(entry)
+	shard.bytes += entry.size
+	if entry.notFound {
+		shard.negative++
+	}
+
+	evicted := shard.evict()
+	c.evictions.Add(evicted)
+	return nil
 }
 
-func (c *LRUCache) removeOldest() {
-	items := c.cache.Items()
-	if len(items) == 0 {
-		return
+// get returns a live entry of the requested kind and marks it most recently used
+func (s *lruShard) get(key string, notFound bool) (*lruEntry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elem, ok := s.items[key]
+	if !ok {
+		return nil, false
 	}
 
-	var oldestKey string
-	var oldestTime time.Time
+	entry := elem.Value.(*lruEntry)
+	if !entry.expiresAt.After(time.Now()) {
+		s.remove(elem)
+		return nil, false
+	}
+	if entry.notFound != notFound {
+		return nil, false
+	}
+
+	s.order.MoveToFront(elem)
+	return entry, true
+}
 
-	for key, item := range items {
-		if oldestTime.IsZero() || item.Expiration < oldestTime.Unix() {
-			oldestKey = key
-			oldestTime = time.Unix(item.Expiration, 0)
+// evict drops least recently used entries until the shard is within its
+// limits and returns how many were removed. Must be called with mu held.
+func (s *lruShard) evict() int64 {
+	var evicted int64
+	for s.order.Len() > s.maxItems || (s.maxBytes > 0 && s.bytes > s.maxBytes) {
+		oldest := s.order.Back()
+		if oldest == nil {
+			break
 		}
+		s.remove(oldest)
+		evicted++
 	}
+	return evicted
+}
 
-	if oldestKey != "" {
-		c.cache.Delete(oldestKey)
+// remove unlinks an entry. Must be called with mu held.
+func (s *lruShard) remove(elem *list.Element) {
+	entry := s.order.Remove(elem).(*lruEntry)
+	delete(s.items, entry.key)
+	s.bytes -= entry.size
+	if entry.notFound {
+		s.negative--
 	}
 }
 
+// CaseKey is the cache key for a case number, shared by every notation of
+// its type, so "W.P.(C)" and "WP(C)" hit the same entry
+func CaseKey(id caseid.CaseNumber) string {
+	return GenerateCacheKey(id.CompactType(), id.Number, id.Year)
+}
+
 func GenerateCacheKey(caseType, caseNumber, filingYear string) string {
 	return fmt.Sprintf("case:%s:%s:%s", caseType, caseNumber, filingYear)
 }
@@ -127,4 +353,4 @@ func DeserializeCaseInfo(data []byte) (*database.CaseInfo, error) {
 		return nil, err
 	}
 	return &info, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+// Group coalesces concurrent calls that share a key so only one of them
+// does the work and the rest wait for its result
+type Group struct {
+	mu        sync.Mutex
+	calls     map[string]*call
+	coalesced int64
+}
+
+type call struct {
+	wg  sync.WaitGroup
+	val interface{}
+	err error
+}
+
+// PanicError is returned to every caller of a call whose fn panicked, so
+// waiters don't mistake the missing result for success
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("coalesced call panicked: %v", e.Value)
+}
+
+// NewGroup creates an empty call group
+func NewGroup() *Group {
+	return &Group{calls: make(map[string]*call)}
+}
+
+// Do runs fn once for all concurrent callers of key. shared reports whether
+// the result was produced by another caller's invocation.
+func (g *Group) Do(key string, fn func() (interface{}, error)) (v interface{}, err error, shared bool) {
+	g.mu.Lock()
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		atomic.AddInt64(&g.coalesced, 1)
+		c.wg.Wait()
+		return c.val, c.err, true
+	}
+
+	c := &call{}
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		c.wg.Done()
+	}()
+
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				c.val, c.err = nil, &PanicError{Value: r, Stack: debug.Stack()}
+			}
+		}()
+		c.val, c.err = fn()
+	}()
+	return c.val, c.err, false
+}
+
+// Coalesced returns how many callers have been served by another caller's work
+func (g *Group) Coalesced() int64 {
+	return atomic.LoadInt64(&g.coalesced)
+}
@@ -0,0 +1,211 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/JustJay7/court-data-fetcher/internal/database"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	redisKeyPrefix = "court-data-fetcher:"
+	redisNotFound  = "\x00not_found"
+	redisTimeout   = 2 * time.Second
+)
+
+// RedisCache stores serialised cases in Redis so they survive restarts and
+// are shared between replicas
+type RedisCache struct {
+	client      *redis.Client
+	ttl         time.Duration
+	negativeTTL time.Duration
+	mu          sync.Mutex
+	stats       CacheStats
+}
+
+// NewRedisCache connects to the Redis server at redisURL, e.g.
+// redis://localhost:6379/0
+func NewRedisCache(redisURL string, ttl, negativeTTL time.Duration) (*RedisCache, error) {
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, err
+	}
+
+	client := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return &RedisCache{
+		client:      client,
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+	}, nil
+}
+
+func (c *RedisCache) Get(key string) (*database.CaseInfo, bool) {
+	value, _, found := c.Lookup(key)
+	return value, found
+}
+
+// Lookup derives the caching time from the key's remaining TTL
+func (c *RedisCache) Lookup(key string) (*database.CaseInfo, time.Time, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	pipe := c.client.Pipeline()
+	get := pipe.Get(ctx, redisKeyPrefix+key)
+	ttl := pipe.PTTL(ctx, redisKeyPrefix+key)
+	pipe.Exec(ctx)
+
+	data, err := get.Bytes()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	c.stats.LastAccess = now
+
+	if err == nil && string(data) != redisNotFound {
+		if caseInfo, err := DeserializeCaseInfo(data); err == nil {
+			c.stats.Hits++
+			cachedAt := now
+			if remaining, err := ttl.Result(); err == nil && remaining > 0 {
+				cachedAt = now.Add(remaining - c.ttl)
+			}
+			return caseInfo, cachedAt, true
+		}
+	}
+
+	c.stats.Misses++
+	return nil, time.Time{}, false
+}
+
+func (c *RedisCache) Set(key string, value *database.CaseInfo) error {
+	data, err := SerializeCaseInfo(value)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	return c.client.Set(ctx, redisKeyPrefix+key, data, c.ttl).Err()
+}
+
+func (c *RedisCache) IsNotFound(key string) bool {
+	data, err := c.get(key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err == nil && string(data) == redisNotFound {
+		c.stats.NegativeHits++
+		return true
+	}
+
+	c.stats.NegativeMisses++
+	return false
+}
+
+func (c *RedisCache) SetNotFound(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	return c.client.Set(ctx, redisKeyPrefix+key, redisNotFound, c.negativeTTL).Err()
+}
+
+func (c *RedisCache) Delete(key string) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	c.client.Del(ctx, redisKeyPrefix+key)
+}
+
+func (c *RedisCache) Clear() {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	c.scan(ctx, func(key string) {
+		c.client.Del(ctx, key)
+	})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats = CacheStats{}
+}
+
+func (c *RedisCache) Stats() CacheStats {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	// Entries are told apart by length, one pipelined round trip for each
+	// page of keys, as negative entries hold only the not-found marker
+	var size, negativeSize int
+	var bytes int64
+	var cursor uint64
+	for {
+		keys, next, err := c.client.Scan(ctx, cursor, redisKeyPrefix+"*", 100).Result()
+		if err != nil {
+			break
+		}
+		if len(keys) > 0 {
+			pipe := c.client.Pipeline()
+			lengths := make([]*redis.IntCmd, len(keys))
+			for i, key := range keys {
+				lengths[i] = pipe.StrLen(ctx, key)
+			}
+			pipe.Exec(ctx)
+			for _, length := range lengths {
+				n, err := length.Result()
+				switch {
+				case err != nil || n == 0:
+					// Expired between the scan and the pipeline
+				case n == int64(len(redisNotFound)):
+					negativeSize++
+				default:
+					size++
+					bytes += n
+				}
+			}
+		}
+		if cursor = next; cursor == 0 {
+			break
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Size = size
+	stats.NegativeSize = negativeSize
+	stats.Bytes = bytes
+	return stats
+}
+
+// Close releases the Redis connection pool
+func (c *RedisCache) Close() error {
+	return c.client.Close()
+}
+
+func (c *RedisCache) get(key string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	return c.client.Get(ctx, redisKeyPrefix+key).Bytes()
+}
+
+// scan visits every key owned by this cache
+func (c *RedisCache) scan(ctx context.Context, fn func(key string)) {
+	iter := c.client.Scan(ctx, 0, redisKeyPrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		fn(iter.Val())
+	}
+}
@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"sync"
+	"time"
+
+	"github.com/JustJay7/court-data-fetcher/internal/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SQLiteCache stores serialised cases in the cache_entries table so they
+// survive restarts
+type SQLiteCache struct {
+	db          *gorm.DB
+	ttl         time.Duration
+	negativeTTL time.Duration
+	mu          sync.Mutex
+	stats       CacheStats
+}
+
+// NewSQLiteCache creates a cache backed by the application database
+func NewSQLiteCache(db *gorm.DB, ttl, negativeTTL time.Duration) *SQLiteCache {
+	return &SQLiteCache{
+		db:          db,
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+	}
+}
+
+func (c *SQLiteCache) Get(key string) (*database.CaseInfo, bool) {
+	value, _, found := c.Lookup(key)
+	return value, found
+}
+
+func (c *SQLiteCache) Lookup(key string) (*database.CaseInfo, time.Time, bool) {
+	entry, found := c.lookup(key, false)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats.LastAccess = time.Now()
+
+	if found {
+		if caseInfo, err := DeserializeCaseInfo(entry.Value); err == nil {
+			c.stats.Hits++
+			return caseInfo, entry.CreatedAt, true
+		}
+	}
+
+	c.stats.Misses++
+	return nil, time.Time{}, false
+}
+
+func (c *SQLiteCache) Set(key string, value *database.CaseInfo) error {
+	data, err := SerializeCaseInfo(value)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	return c.store(&database.CacheEntry{
+		Key:       key,
+		Value:     data,
+		ExpiresAt: now.Add(c.ttl),
+		CreatedAt: now,
+	})
+}
+
+func (c *SQLiteCache) IsNotFound(key string) bool {
+	_, found := c.lookup(key, true)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if found {
+		c.stats.NegativeHits++
+		return true
+	}
+
+	c.stats.NegativeMisses++
+	return false
+}
+
+func (c *SQLiteCache) SetNotFound(key string) error {
+	now := time.Now()
+	return c.store(&database.CacheEntry{
+		Key:       key,
+		NotFound:  true,
+		ExpiresAt: now.Add(c.negativeTTL),
+		CreatedAt: now,
+	})
+}
+
+func (c *SQLiteCache) Delete(key string) {
+	c.db.Where("key = ?", key).Delete(&database.CacheEntry{})
+}
+
+func (c *SQLiteCache) Clear() {
+	c.db.Where("1 = 1").Delete(&database.CacheEntry{})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats = CacheStats{}
+}
+
+func (c *SQLiteCache) Stats() CacheStats {
+	var size, negativeSize int64
+	now := time.Now()
+	c.db.Model(&database.CacheEntry{}).Where("not_found = ? AND expires_at > ?", false, now).Count(&size)
+	c.db.Model(&database.CacheEntry{}).Where("not_found = ? AND expires_at > ?", true, now).Count(&negativeSize)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Size = int(size)
+	stats.NegativeSize = int(negativeSize)
+	return stats
+}
+
+// DeleteExpired removes every expired entry from the table
+func (c *SQLiteCache) DeleteExpired() error {
+	return c.db.Where("expires_at <= ?", time.Now()).Delete(&database.CacheEntry{}).Error
+}
+
+// lookup returns the live entry of the requested kind, dropping it if expired
+func (c *SQLiteCache) lookup(key string, notFound bool) (*database.CacheEntry, bool) {
+	var entry database.CacheEntry
+	result := c.db.Where("key = ?", key).Limit(1).Find(&entry)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return nil, false
+	}
+
+	if !entry.ExpiresAt.After(time.Now()) {
+		c.db.Where("key = ? AND expires_at <= ?", key, time.Now()).Delete(&database.CacheEntry{})
+		return nil, false
+	}
+
+	if entry.NotFound != notFound {
+		return nil, false
+	}
+
+	return &entry, true
+}
+
+// store upserts an entry, replacing any value or negative marker for the key
+func (c *SQLiteCache) store(entry *database.CacheEntry) error {
+	return c.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "not_found", "expires_at", "created_at"}),
+	}).Create(entry).Error
+}
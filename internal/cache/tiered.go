@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/JustJay7/court-data-fetcher/internal/config"
+	"github.com/JustJay7/court-data-fetcher/internal/database"
+	"gorm.io/gorm"
+)
+
+// TieredCache checks a fast local cache before a shared persistent one and
+// backfills the local layer on remote hits. Delete and Clear only reach this
+// process's local tier, so other replicas keep serving an invalidated entry
+// until their local copy expires; NewFromConfig caps that at CacheLocalTTL.
+type TieredCache struct {
+	local  Cache
+	remote Cache
+	mu     sync.Mutex
+	stats  CacheStats
+}
+
+// NewTieredCache layers local in front of remote
+func NewTieredCache(local, remote Cache) *TieredCache {
+	return &TieredCache{local: local, remote: remote}
+}
+
+func (c *TieredCache) Get(key string) (*database.CaseInfo, bool) {
+	value, _, found := c.Lookup(key)
+	return value, found
+}
+
+func (c *TieredCache) Lookup(key string) (*database.CaseInfo, time.Time, bool) {
+	value, cachedAt, found := c.local.Lookup(key)
+	if !found {
+		if value, cachedAt, found = c.remote.Lookup(key); found {
+			c.backfill(key, value, cachedAt)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats.LastAccess = time.Now()
+	if found {
+		c.stats.Hits++
+	} else {
+		c.stats.Misses++
+	}
+
+	return value, cachedAt, found
+}
+
+// backfill copies a remote hit into the local tier, keeping its age when
+// the local cache supports it
+func (c *TieredCache) backfill(key string, value *database.CaseInfo, cachedAt time.Time) {
+	if lru, ok := c.local.(*LRUCache); ok {
+		lru.setAt(key, value, cachedAt)
+		return
+	}
+	c.local.Set(key, value)
+}
+
+func (c *TieredCache) Set(key string, value *database.CaseInfo) error {
+	if err := c.remote.Set(key, value); err != nil {
+		return err
+	}
+	return c.local.Set(key, value)
+}
+
+func (c *TieredCache) IsNotFound(key string) bool {
+	found := c.local.IsNotFound(key)
+	if !found {
+		if found = c.remote.IsNotFound(key); found {
+			c.local.SetNotFound(key)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if found {
+		c.stats.NegativeHits++
+	} else {
+		c.stats.NegativeMisses++
+	}
+
+	return found
+}
+
+func (c *TieredCache) SetNotFound(key string) error {
+	if err := c.remote.SetNotFound(key); err != nil {
+		return err
+	}
+	return c.local.SetNotFound(key)
+}
+
+func (c *TieredCache) Delete(key string) {
+	c.remote.Delete(key)
+	c.local.Delete(key)
+}
+
+func (c *TieredCache) Clear() {
+	c.remote.Clear()
+	c.local.Clear()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats = CacheStats{}
+}
+
+func (c *TieredCache) Stats() CacheStats {
+	local := c.local.Stats()
+	remote := c.remote.Stats()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Size = remote.Size
+	stats.NegativeSize = remote.NegativeSize
+	stats.Tiers = []CacheStats{local, remote}
+	return stats
+}
+
+// NewFromConfig builds the cache selected by CACHE_BACKEND. Persistent
+// backends are fronted by the in-memory cache, which holds entries for at
+// most CacheLocalTTL so invalidations on one replica reach the others.
+func NewFromConfig(cfg *config.Config, db *gorm.DB) (Cache, error) {
+	local := NewSizedCache(cfg.CacheSize, cfg.CacheMaxBytes, cfg.CacheTTL, cfg.NegativeCacheTTL)
+
+	switch cfg.CacheBackend {
+	case "", "memory":
+		return local, nil
+	}
+
+	local.SetMaxAge(cfg.CacheLocalTTL)
+	switch cfg.CacheBackend {
+	case "sqlite":
+		return NewTieredCache(local, NewSQLiteCache(db, cfg.CacheTTL, cfg.NegativeCacheTTL)), nil
+	case "redis":
+		remote, err := NewRedisCache(cfg.RedisURL, cfg.CacheTTL, cfg.NegativeCacheTTL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to redis: %w", err)
+		}
+		return NewTieredCache(local, remote), nil
+	default:
+		return nil, fmt.Errorf("unknown cache backend: %s", cfg.CacheBackend)
+	}
+}
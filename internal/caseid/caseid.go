@@ -0,0 +1,223 @@
+// Package caseid parses and validates the two ways a case is identified:
+// Delhi-style case numbers such as "CS(OS) 100/2023" and the 16-character
+// eCourts CNR number such as "DLCT010012342023"
+package caseid
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidCaseNumber is wrapped by every case number validation error
+	ErrInvalidCaseNumber = errors.New("invalid case number")
+	// ErrInvalidCNR is wrapped by every CNR validation error
+	ErrInvalidCNR = errors.New("invalid CNR number")
+)
+
+// ValidationError explains which part of an identifier is invalid
+type ValidationError struct {
+	Field  string
+	Value  string
+	Reason string
+	kind   error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%v: %s %q %s", e.kind, e.Field, e.Value, e.Reason)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.kind
+}
+
+func caseNumberError(field, value, reason string) error {
+	return &ValidationError{Field: field, Value: value, Reason: reason, kind: ErrInvalidCaseNumber}
+}
+
+// firstYear is the earliest filing year accepted
+const firstYear = 1950
+
+var (
+	// "CS(OS) 100/2023", "W.P.(C)-1234-2023", "CS (OS) No. 100 of 2023",
+	// "FAO(OS) (COMM) 12/2023", "BAIL APPLN. 12/2024" or "CS/100/2023",
+	// matched after uppercasing
+	caseNumberPattern = regexp.MustCompile(`^([A-Z][A-Z.&\s]*?(?:\s*\([A-Z.&\s]+\))*)\s*(?:NO\.?)?\s*[/\-\s]?\s*(\d{1,7})\s*(?:[/\-]|\s+OF\s+|\s+)\s*(\d{4})$`)
+	// Within free text the type must be a single uppercase word, optionally
+	// followed by bracketed qualifiers, so surrounding words aren't taken for it
+	caseNumberInText = regexp.MustCompile(`\b([A-Z][A-Z.&]*(?:\s?\([A-Z.&]+\))*)\s?(?:[/\-]|\s)\s?(\d{1,7})\s?[/\-]\s?(\d{4})\b`)
+
+	caseTypePattern = regexp.MustCompile(`^[A-Z][A-Z.&\s]*(?:\s*\([A-Z.&\s]+\))*$`)
+	spaceAroundMark = regexp.MustCompile(`\s*([().&])\s*`)
+)
+
+// CaseNumber is a normalised case number
+type CaseNumber struct {
+	// Type is the court's case type label, uppercased with its spacing
+	// normalised, e.g. "W.P.(C)" or "BAIL APPLN."
+	Type string `json:"case_type"`
+	// Number has no leading zeros
+	Number string `json:"case_number"`
+	Year   string `json:"filing_year"`
+}
+
+// New validates and normalises the three fields of the court's search form
+func New(caseType, number, year string) (CaseNumber, error) {
+	id := CaseNumber{
+		Type:   NormaliseType(caseType),
+		Number: strings.TrimLeft(strings.TrimSpace(number), "0"),
+		Year:   strings.TrimSpace(year),
+	}
+
+	switch {
+	case id.Type == "":
+		return CaseNumber{}, caseNumberError("case type", caseType, "is required")
+	case !caseTypePattern.MatchString(id.Type):
+		return CaseNumber{}, caseNumberError("case type", caseType, "must be letters, dots and bracketed qualifiers")
+	case strings.TrimSpace(number) == "":
+		return CaseNumber{}, caseNumberError("case number", number, "is required")
+	case !isDigits(strings.TrimSpace(number)) || len(strings.TrimSpace(number)) > 7:
+		return CaseNumber{}, caseNumberError("case number", number, "must be up to 7 digits")
+	case id.Number == "":
+		return CaseNumber{}, caseNumberError("case number", number, "must not be zero")
+	}
+	if err := validateYear(id.Year); err != nil {
+		return CaseNumber{}, caseNumberError("filing year", year, err.Error())
+	}
+
+	return id, nil
+}
+
+// Parse reads a whole case number such as "CS(OS) 100/2023" or "CS/100/2023"
+func Parse(s string) (CaseNumber, error) {
+	match := caseNumberPattern.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(s)))
+	if match == nil {
+		return CaseNumber{}, caseNumberError("case number", s, "is not of the form TYPE NUMBER/YEAR")
+	}
+	return New(match[1], match[2], match[3])
+}
+
+// FromInput accepts either the three form fields or, when only number is
+// given, a whole case number in it
+func FromInput(caseType, number, year string) (CaseNumber, error) {
+	if strings.TrimSpace(caseType) == "" && strings.TrimSpace(year) == "" && strings.TrimSpace(number) != "" {
+		return Parse(number)
+	}
+	return New(caseType, number, year)
+}
+
+// Find returns the first valid case number in free text
+func Find(text string) (CaseNumber, bool) {
+	for _, match := range caseNumberInText.FindAllStringSubmatch(text, -1) {
+		if id, err := New(match[1], match[2], match[3]); err == nil {
+			return id, true
+		}
+	}
+	return CaseNumber{}, false
+}
+
+// NormaliseType uppercases a case type and tidies its spacing, so "cs (os)"
+// becomes "CS(OS)" and "W.P. (C)" becomes "W.P.(C)"
+func NormaliseType(caseType string) string {
+	caseType = strings.ToUpper(strings.Join(strings.Fields(caseType), " "))
+	caseType = spaceAroundMark.ReplaceAllString(caseType, "$1")
+	// "BAIL APPLN." keeps its space, "W.P. (C)" doesn't
+	return strings.TrimSpace(caseType)
+}
+
+// CompactType drops dots and spaces so notations of the same type compare
+// equal: "W.P.(C)" and "WP(C)" both become "WP(C)"
+func (c CaseNumber) CompactType() string {
+	return CompactType(c.Type)
+}
+
+// CompactType normalises a case type and drops its dots and spaces
+func CompactType(caseType string) string {
+	return strings.NewReplacer(".", "", " ", "").Replace(NormaliseType(caseType))
+}
+
+// String formats the case number as the court displays it, e.g. "CS(OS)/100/2023"
+func (c CaseNumber) String() string {
+	return fmt.Sprintf("%s/%s/%s", c.Type, c.Number, c.Year)
+}
+
+// CNR is an eCourts Case Number Record, e.g. DLCT01 001234 2023
+type CNR struct {
+	// State and District are the two-letter codes, e.g. "DL" and "CT"
+	State    string `json:"state"`
+	District string `json:"district"`
+	// Establishment is the two-digit court establishment code
+	Establishment string `json:"establishment"`
+	Serial        string `json:"serial"`
+	Year          string `json:"year"`
+}
+
+var (
+	cnrPattern = regexp.MustCompile(`^([A-Z]{2})([A-Z]{2})(\d{2})(\d{6})(\d{4})$`)
+	cnrInText  = regexp.MustCompile(`\b[A-Z]{4}\d{12}\b`)
+)
+
+// ParseCNR validates a CNR, ignoring case, spaces and hyphens
+func ParseCNR(s string) (CNR, error) {
+	compact := strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(s)))
+
+	if len(compact) != 16 {
+		return CNR{}, &ValidationError{Field: "CNR", Value: s, Reason: "must be 16 characters", kind: ErrInvalidCNR}
+	}
+	match := cnrPattern.FindStringSubmatch(compact)
+	if match == nil {
+		return CNR{}, &ValidationError{Field: "CNR", Value: s, Reason: "must be 4 letters followed by 12 digits", kind: ErrInvalidCNR}
+	}
+	if err := validateYear(match[5]); err != nil {
+		return CNR{}, &ValidationError{Field: "CNR", Value: s, Reason: "year " + err.Error(), kind: ErrInvalidCNR}
+	}
+
+	return CNR{State: match[1], District: match[2], Establishment: match[3], Serial: match[4], Year: match[5]}, nil
+}
+
+// FindCNR returns the first valid CNR in free text
+func FindCNR(text string) (CNR, bool) {
+	for _, candidate := range cnrInText.FindAllString(text, -1) {
+		if cnr, err := ParseCNR(candidate); err == nil {
+			return cnr, true
+		}
+	}
+	return CNR{}, false
+}
+
+// String formats the CNR as its 16 characters
+func (c CNR) String() string {
+	return c.State + c.District + c.Establishment + c.Serial + c.Year
+}
+
+func validateYear(year string) error {
+	if len(year) != 4 || !isDigits(year) {
+		return errors.New("must be 4 digits")
+	}
+	n, _ := strconv.Atoi(year)
+	if n < firstYear || n > time.Now().Year()+1 {
+		return fmt.Errorf("must be between %d and %d", firstYear, time.Now().Year()+1)
+	}
+	return nil
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,190 @@
+// Package catalogue keeps the case types offered by the court's search form,
+// so searches can be checked against them before a browser is launched
+package catalogue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/JustJay7/court-data-fetcher/internal/caseid"
+	"github.com/JustJay7/court-data-fetcher/internal/database"
+	"github.com/JustJay7/court-data-fetcher/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// DefaultTypes are offered until the catalogue has been scraped once
+var DefaultTypes = []string{
+	"BAIL APPLN.", "CS", "CC", "CRL.M.C", "CRL.A", "CRL.REV.P",
+	"FAO", "RFA", "RSA", "CR", "EXEC",
+}
+
+var (
+	// ErrUnknownCaseType is returned for a case type the court doesn't offer
+	ErrUnknownCaseType = errors.New("unknown case type")
+	// ErrNoFetcher is returned by Refresh when there is no browser to scrape with
+	ErrNoFetcher = errors.New("no case type fetcher configured")
+	// ErrEmptyForm is returned when the form offered no case types, which
+	// usually means its layout changed. The stored types are kept.
+	ErrEmptyForm = errors.New("court form offered no case types")
+)
+
+// Fetcher reads the case type options from the court's search form
+type Fetcher interface {
+	FetchCaseTypes(ctx context.Context) ([]database.CaseType, error)
+}
+
+// Catalogue stores the scraped case types in the case_types table
+type Catalogue struct {
+	db      *gorm.DB
+	fetcher Fetcher
+	logger  *logger.Logger
+}
+
+// New creates a catalogue. fetcher may be nil, the stored types are then
+// used as they are.
+func New(db *gorm.DB, fetcher Fetcher, logger *logger.Logger) *Catalogue {
+	return &Catalogue{db: db, fetcher: fetcher, logger: logger}
+}
+
+// Refresh scrapes the form and stores its case types. Types no longer
+// offered are marked inactive. It returns the number of active types.
+func (c *Catalogue) Refresh(ctx context.Context) (int, error) {
+	if c.fetcher == nil {
+		return 0, ErrNoFetcher
+	}
+	scraped, err := c.fetcher.FetchCaseTypes(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch case types: %w", err)
+	}
+	if len(scraped) == 0 {
+		return 0, ErrEmptyForm
+	}
+
+	now := time.Now()
+	err = c.db.Transaction(func(tx *gorm.DB) error {
+		var stored []database.CaseType
+		if err := tx.Find(&stored).Error; err != nil {
+			return err
+		}
+		byLabel := make(map[string]*database.CaseType, len(stored))
+		for i := range stored {
+			byLabel[stored[i].Label] = &stored[i]
+		}
+
+		seen := make(map[string]bool, len(scraped))
+		for _, option := range scraped {
+			seen[option.Label] = true
+			existing, found := byLabel[option.Label]
+			if !found {
+				option.Active = true
+				option.LastSeenAt = now
+				if err := tx.Create(&option).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if err := tx.Model(existing).Updates(map[string]interface{}{
+				"value":        option.Value,
+				"active":       true,
+				"last_seen_at": now,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, existing := range stored {
+			if existing.Active && !seen[existing.Label] {
+				if err := tx.Model(&existing).Update("active", false).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to save case types: %w", err)
+	}
+	return len(scraped), nil
+}
+
+// Start refreshes the catalogue now and then every interval until ctx is
+// cancelled
+func (c *Catalogue) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		count, err := c.Refresh(ctx)
+		if err != nil {
+			c.logger.Error("Case type refresh failed", "error", err)
+		} else {
+			c.logger.Info("Case types refreshed", "count", count)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// List returns the active case types by label. scraped is false when the
+// form hasn't been read yet and the types are DefaultTypes.
+func (c *Catalogue) List() (types []database.CaseType, scraped bool, err error) {
+	if err := c.db.Where("active = ?", true).Order("label").Find(&types).Error; err != nil {
+		return nil, false, err
+	}
+	if len(types) > 0 {
+		return types, true, nil
+	}
+
+	types = make([]database.CaseType, len(DefaultTypes))
+	for i, label := range DefaultTypes {
+		types[i] = database.CaseType{Value: label, Label: label, Active: true}
+	}
+	return types, false, nil
+}
+
+// Labels returns the labels of the active case types for the search form
+func (c *Catalogue) Labels() ([]string, error) {
+	types, _, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+	labels := make([]string, len(types))
+	for i, caseType := range types {
+		labels[i] = caseType.Label
+	}
+	return labels, nil
+}
+
+// Resolve returns the court's label for a case type, matching notations
+// that differ only in dots, spaces and case. Before the form has been read
+// every type is accepted and returned as given.
+func (c *Catalogue) Resolve(caseType string) (string, error) {
+	var types []database.CaseType
+	if err := c.db.Where("active = ?", true).Find(&types).Error; err != nil {
+		return "", fmt.Errorf("failed to load case types: %w", err)
+	}
+	if len(types) == 0 {
+		return caseType, nil
+	}
+
+	normalised, compact := caseid.NormaliseType(caseType), caseid.CompactType(caseType)
+	match := ""
+	for _, candidate := range types {
+		if caseid.NormaliseType(candidate.Label) == normalised {
+			return candidate.Label, nil
+		}
+		if match == "" && caseid.CompactType(candidate.Label) == compact {
+			match = candidate.Label
+		}
+	}
+	if match == "" {
+		return "", fmt.Errorf("%w %q", ErrUnknownCaseType, caseType)
+	}
+	return match, nil
+}
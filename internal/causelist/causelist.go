@@ -0,0 +1,180 @@
+// Package causelist downloads the court's daily cause lists and matches
+// their items to stored cases
+package causelist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/JustJay7/court-data-fetcher/internal/config"
+	"github.com/JustJay7/court-data-fetcher/internal/courtdate"
+	"github.com/JustJay7/court-data-fetcher/internal/database"
+	"github.com/JustJay7/court-data-fetcher/internal/scraper"
+	"github.com/JustJay7/court-data-fetcher/internal/upstream"
+	"github.com/JustJay7/court-data-fetcher/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// Path is where the court publishes the list for ?date=DD-MM-YYYY
+const Path = "/app/cause-list"
+
+// DateLayout is how list dates are stored and accepted by the API
+const DateLayout = "2006-01-02"
+
+// maxPageBytes bounds the size of a downloaded list
+const maxPageBytes = 10 << 20
+
+// Service fetches, stores and looks up cause lists
+type Service struct {
+	db      *gorm.DB
+	client  *http.Client
+	baseURL string
+	parser  *scraper.Parser
+	logger  *logger.Logger
+}
+
+// NewService creates a service for the court at cfg.CourtBaseURL, fetching
+// through up
+func NewService(db *gorm.DB, up *upstream.Upstream, logger *logger.Logger, cfg *config.Config) *Service {
+	return &Service{
+		db:      db,
+		client:  up.Client(cfg.ScraperTimeout),
+		baseURL: cfg.CourtBaseURL,
+		parser:  scraper.NewParser(logger),
+		logger:  logger,
+	}
+}
+
+// Fetch downloads the list for day, replaces any stored copy and matches
+// its items to stored cases
+func (s *Service) Fetch(ctx context.Context, day time.Time) ([]database.CauseListEntry, error) {
+	listURL := fmt.Sprintf("%s%s?date=%s", s.baseURL, Path, day.Format("02-01-2006"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, listURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fetchError("download cause list", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fetchError("download cause list", fmt.Errorf("court returned %s", resp.Status))
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPageBytes))
+	if err != nil {
+		return nil, fetchError("read cause list", err)
+	}
+
+	entries, err := s.parser.ParseCauseListHTML(string(body))
+	if err != nil {
+		return nil, &scraper.ScrapeError{Kind: scraper.ErrLayoutChanged, Op: "parse cause list", Err: err}
+	}
+
+	listDate := day.Format(DateLayout)
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("list_date = ?", listDate).Delete(&database.CauseListEntry{}).Error; err != nil {
+			return err
+		}
+		for i := range entries {
+			entries[i].ListDate = listDate
+			caseInfoID, err := latestCaseID(tx, entries[i].CaseNumber)
+			if err != nil {
+				return err
+			}
+			entries[i].CaseInfoID = caseInfoID
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		return tx.Create(&entries).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save cause list: %w", err)
+	}
+
+	s.logger.Info("Cause list fetched", "date", listDate, "entries", len(entries))
+	return entries, nil
+}
+
+// Today returns the current day in India, which is the court's today
+// whatever the server's time zone
+func Today() time.Time {
+	return time.Now().In(courtdate.IST)
+}
+
+// fetchError classifies a failed download like a failed scrape
+func fetchError(op string, err error) error {
+	kind := scraper.ErrCourtUnavailable
+	if errors.Is(err, context.DeadlineExceeded) {
+		kind = scraper.ErrTimeout
+	}
+	return &scraper.ScrapeError{Kind: kind, Op: op, Err: err}
+}
+
+// latestCaseID returns the newest stored snapshot of a case, or nil
+func latestCaseID(tx *gorm.DB, caseNumber string) (*uint, error) {
+	var ids []uint
+	err := tx.Model(&database.CaseInfo{}).
+		Where("case_number = ?", caseNumber).
+		Order("id DESC").Limit(1).
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	return &ids[0], nil
+}
+
+// Entries returns the stored list for day in published order
+func (s *Service) Entries(day time.Time) ([]database.CauseListEntry, error) {
+	var entries []database.CauseListEntry
+	err := s.db.Where("list_date = ?", day.Format(DateLayout)).Order("id").Find(&entries).Error
+	return entries, err
+}
+
+// Listings returns the entries for day keyed by case number, for the given
+// case numbers
+func (s *Service) Listings(day time.Time, caseNumbers []string) (map[string]*database.CauseListEntry, error) {
+	listings := make(map[string]*database.CauseListEntry)
+	if len(caseNumbers) == 0 {
+		return listings, nil
+	}
+
+	var entries []database.CauseListEntry
+	err := s.db.Where("list_date = ? AND case_number IN ?", day.Format(DateLayout), caseNumbers).
+		Order("id").
+		Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		if _, seen := listings[entries[i].CaseNumber]; !seen {
+			listings[entries[i].CaseNumber] = &entries[i]
+		}
+	}
+	return listings, nil
+}
+
+// Start fetches today's list now and then every interval until ctx is
+// cancelled, so the listed-today flags stay current
+func (s *Service) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Fetch(ctx, Today()); err != nil {
+			s.logger.Error("Cause list fetch failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
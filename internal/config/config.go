@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -16,15 +17,28 @@ type Config struct {
 	Port string
 
 	// Database settings
-	DatabasePath string
+	DatabasePath      string
+	DatabaseURL       string // postgres:// URL, or empty to use SQLite at DatabasePath
+	DBMaxOpenConns    int
+	DBMaxIdleConns    int
+	DBConnMaxLifetime time.Duration
 
 	// Logging settings
 	LogLevel  string
 	LogFormat string
 
 	// Cache settings
-	CacheSize int
-	CacheTTL  time.Duration
+	CacheSize        int
+	CacheMaxBytes    int64
+	CacheTTL         time.Duration
+	NegativeCacheTTL time.Duration
+	CacheStaleAfter  time.Duration // 0 disables stale-while-revalidate
+	CacheBackend     string        // memory, sqlite or redis
+	CacheLocalTTL    time.Duration // Longest the memory tier holds entries in front of a shared backend
+	RedisURL         string
+
+	// How long the analytics dashboard is kept before it is recomputed
+	AnalyticsCacheTTL time.Duration
 
 	// Court settings
 	CourtBaseURL string
@@ -35,6 +49,32 @@ type Config struct {
 	HeadlessMode   bool
 	UserAgent      string
 	BrowserPath    string
+	DebugCapture   bool // save a screenshot, network log, console and DOM for failed scrapes
+
+	// Upstream proxies (http, https or socks5 URLs) requests to the court are
+	// rotated through, connecting directly when empty. A proxy that fails is
+	// rested for ProxyCooldown, and all are checked every ProxyCheckInterval.
+	Proxies            []string
+	ProxyCooldown      time.Duration
+	ProxyCheckInterval time.Duration // 0 disables the health checks
+
+	// Minimum gap between requests to one host, plus up to HostRequestJitter,
+	// shared by the browser and HTTP fetches
+	HostRequestInterval time.Duration
+	HostRequestJitter   time.Duration
+
+	// JSON file of order classification rules, the built-in rules when empty
+	OrderRulesFile string
+
+	// How often the case types are read from the court form, 0 disables it
+	CaseTypesRefreshInterval time.Duration
+	// How often today's cause list is fetched, 0 disables it
+	CauseListInterval time.Duration
+
+	// Layout drift detection, alerts when the average parse confidence of the
+	// last ParseConfidenceWindow scrapes falls below ParseConfidenceThreshold
+	ParseConfidenceThreshold float64
+	ParseConfidenceWindow    int
 
 	// Concurrency settings
 	MaxConcurrentScrapes int
@@ -43,6 +83,13 @@ type Config struct {
 	// API settings
 	APIRateLimit  int
 	APIRateWindow time.Duration
+
+	// Retention settings, a zero duration keeps data forever
+	RawHTMLRetention       time.Duration
+	IPRetention            time.Duration
+	PDFRetention           time.Duration
+	DebugArtifactRetention time.Duration
+	RetentionInterval      time.Duration // 0 disables the background purge
 }
 
 // Load reads configuration from environment variables
@@ -59,12 +106,15 @@ func Load() (*Config, error) {
 		Host:         getEnv("HOST", "0.0.0.0"),
 		Port:         getEnv("PORT", "8080"),
 		DatabasePath: getEnv("DATABASE_PATH", "./data/court_cases.db"),
+		DatabaseURL:  getEnv("DATABASE_URL", ""),
 		LogLevel:     getEnv("LOG_LEVEL", "info"),
 		LogFormat:    getEnv("LOG_FORMAT", "json"),
 		CourtBaseURL: getEnv("COURT_BASE_URL", "https://delhihighcourt.nic.in"),
 		CourtName:    getEnv("COURT_NAME", "Delhi District Courts"),
 		UserAgent:    getEnv("USER_AGENT", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36"),
 		BrowserPath:  getEnv("ROD_BROWSER_PATH", ""),
+		CacheBackend: getEnv("CACHE_BACKEND", "memory"),
+		RedisURL:     getEnv("REDIS_URL", "redis://localhost:6379/0"),
 	}
 
 	// Parse integer values
@@ -74,12 +124,58 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid CACHE_SIZE: %w", err)
 	}
 
+	cacheMaxMB, err := strconv.Atoi(getEnv("CACHE_MAX_MB", "64"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CACHE_MAX_MB: %w", err)
+	}
+	cfg.CacheMaxBytes = int64(cacheMaxMB) << 20
+
+	cfg.DBMaxOpenConns, err = strconv.Atoi(getEnv("DB_MAX_OPEN_CONNS", "10"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DB_MAX_OPEN_CONNS: %w", err)
+	}
+
+	cfg.DBMaxIdleConns, err = strconv.Atoi(getEnv("DB_MAX_IDLE_CONNS", "5"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DB_MAX_IDLE_CONNS: %w", err)
+	}
+
+	dbConnMaxLifetime, err := strconv.Atoi(getEnv("DB_CONN_MAX_LIFETIME", "30"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DB_CONN_MAX_LIFETIME: %w", err)
+	}
+	cfg.DBConnMaxLifetime = time.Duration(dbConnMaxLifetime) * time.Minute
+
 	cacheTTL, err := strconv.Atoi(getEnv("CACHE_TTL", "30"))
 	if err != nil {
 		return nil, fmt.Errorf("invalid CACHE_TTL: %w", err)
 	}
 	cfg.CacheTTL = time.Duration(cacheTTL) * time.Minute
 
+	cacheStaleAfter, err := strconv.Atoi(getEnv("CACHE_STALE_AFTER", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CACHE_STALE_AFTER: %w", err)
+	}
+	cfg.CacheStaleAfter = time.Duration(cacheStaleAfter) * time.Minute
+
+	cacheLocalTTL, err := strconv.Atoi(getEnv("CACHE_LOCAL_TTL", "30"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CACHE_LOCAL_TTL: %w", err)
+	}
+	cfg.CacheLocalTTL = time.Duration(cacheLocalTTL) * time.Second
+
+	analyticsCacheTTL, err := strconv.Atoi(getEnv("ANALYTICS_CACHE_TTL", "15"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ANALYTICS_CACHE_TTL: %w", err)
+	}
+	cfg.AnalyticsCacheTTL = time.Duration(analyticsCacheTTL) * time.Minute
+
+	negativeCacheTTL, err := strconv.Atoi(getEnv("NEGATIVE_CACHE_TTL", "5"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid NEGATIVE_CACHE_TTL: %w", err)
+	}
+	cfg.NegativeCacheTTL = time.Duration(negativeCacheTTL) * time.Minute
+
 	scraperTimeout, err := strconv.Atoi(getEnv("SCRAPER_TIMEOUT", "30"))
 	if err != nil {
 		return nil, fmt.Errorf("invalid SCRAPER_TIMEOUT: %w", err)
@@ -87,6 +183,58 @@ func Load() (*Config, error) {
 	cfg.ScraperTimeout = time.Duration(scraperTimeout) * time.Second
 
 	cfg.HeadlessMode = getEnv("HEADLESS_MODE", "true") == "true"
+	cfg.DebugCapture = getEnv("DEBUG_CAPTURE", "true") == "true"
+	cfg.OrderRulesFile = getEnv("ORDER_RULES_FILE", "")
+
+	if proxies := getEnv("PROXIES", ""); proxies != "" {
+		cfg.Proxies = strings.Split(proxies, ",")
+	}
+
+	proxyCooldown, err := strconv.Atoi(getEnv("PROXY_COOLDOWN", "5"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PROXY_COOLDOWN: %w", err)
+	}
+	cfg.ProxyCooldown = time.Duration(proxyCooldown) * time.Minute
+
+	proxyCheckInterval, err := strconv.Atoi(getEnv("PROXY_CHECK_INTERVAL", "5"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PROXY_CHECK_INTERVAL: %w", err)
+	}
+	cfg.ProxyCheckInterval = time.Duration(proxyCheckInterval) * time.Minute
+
+	hostRequestInterval, err := strconv.Atoi(getEnv("HOST_REQUEST_INTERVAL", "2000"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid HOST_REQUEST_INTERVAL: %w", err)
+	}
+	cfg.HostRequestInterval = time.Duration(hostRequestInterval) * time.Millisecond
+
+	hostRequestJitter, err := strconv.Atoi(getEnv("HOST_REQUEST_JITTER", "1000"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid HOST_REQUEST_JITTER: %w", err)
+	}
+	cfg.HostRequestJitter = time.Duration(hostRequestJitter) * time.Millisecond
+
+	caseTypesRefresh, err := strconv.Atoi(getEnv("CASE_TYPES_REFRESH_INTERVAL", "24"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CASE_TYPES_REFRESH_INTERVAL: %w", err)
+	}
+	cfg.CaseTypesRefreshInterval = time.Duration(caseTypesRefresh) * time.Hour
+
+	causeListInterval, err := strconv.Atoi(getEnv("CAUSE_LIST_INTERVAL", "6"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CAUSE_LIST_INTERVAL: %w", err)
+	}
+	cfg.CauseListInterval = time.Duration(causeListInterval) * time.Hour
+
+	cfg.ParseConfidenceThreshold, err = strconv.ParseFloat(getEnv("PARSE_CONFIDENCE_THRESHOLD", "0.6"), 64)
+	if err != nil || cfg.ParseConfidenceThreshold < 0 || cfg.ParseConfidenceThreshold > 1 {
+		return nil, fmt.Errorf("invalid PARSE_CONFIDENCE_THRESHOLD: must be between 0 and 1")
+	}
+
+	cfg.ParseConfidenceWindow, err = strconv.Atoi(getEnv("PARSE_CONFIDENCE_WINDOW", "20"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PARSE_CONFIDENCE_WINDOW: %w", err)
+	}
 
 	cfg.MaxConcurrentScrapes, err = strconv.Atoi(getEnv("MAX_CONCURRENT_SCRAPES", "5"))
 	if err != nil {
@@ -109,6 +257,36 @@ func Load() (*Config, error) {
 	}
 	cfg.APIRateWindow = time.Duration(apiRateWindow) * time.Second
 
+	rawHTMLRetention, err := strconv.Atoi(getEnv("RAW_HTML_RETENTION_DAYS", "0"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RAW_HTML_RETENTION_DAYS: %w", err)
+	}
+	cfg.RawHTMLRetention = time.Duration(rawHTMLRetention) * 24 * time.Hour
+
+	ipRetention, err := strconv.Atoi(getEnv("IP_RETENTION_DAYS", "30"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid IP_RETENTION_DAYS: %w", err)
+	}
+	cfg.IPRetention = time.Duration(ipRetention) * 24 * time.Hour
+
+	pdfRetention, err := strconv.Atoi(getEnv("PDF_RETENTION_DAYS", "90"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PDF_RETENTION_DAYS: %w", err)
+	}
+	cfg.PDFRetention = time.Duration(pdfRetention) * 24 * time.Hour
+
+	debugArtifactRetention, err := strconv.Atoi(getEnv("DEBUG_ARTIFACT_RETENTION_DAYS", "14"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DEBUG_ARTIFACT_RETENTION_DAYS: %w", err)
+	}
+	cfg.DebugArtifactRetention = time.Duration(debugArtifactRetention) * 24 * time.Hour
+
+	retentionInterval, err := strconv.Atoi(getEnv("RETENTION_INTERVAL", "24"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RETENTION_INTERVAL: %w", err)
+	}
+	cfg.RetentionInterval = time.Duration(retentionInterval) * time.Hour
+
 	return cfg, nil
 }
 
@@ -118,4 +296,4 @@ func getEnv(key, defaultValue string) string {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
@@ -0,0 +1,195 @@
+// Package conflicts checks prospective parties against every litigant and
+// advocate seen in stored cases
+package conflicts
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/JustJay7/court-data-fetcher/internal/database"
+	"github.com/JustJay7/court-data-fetcher/internal/names"
+	"gorm.io/gorm"
+)
+
+// DefaultMinScore is lower than names.MatchThreshold because a missed
+// conflict costs more than a false alarm
+const DefaultMinScore = 0.8
+
+// MaxNames bounds the names in one check
+const MaxNames = 50
+
+var (
+	// ErrNoNames is returned when a check has nothing to look for
+	ErrNoNames = errors.New("no names to check")
+	// ErrTooManyNames is returned for checks of more than MaxNames names
+	ErrTooManyNames = fmt.Errorf("at most %d names can be checked at once", MaxNames)
+)
+
+// Request is one conflict check
+type Request struct {
+	Names       []string `json:"names"`
+	RequestedBy string   `json:"requested_by"`
+	Reference   string   `json:"reference"`
+	MinScore    float64  `json:"min_score"`
+	IPAddress   string   `json:"-"`
+}
+
+// Checker matches names against stored parties and records each check
+type Checker struct {
+	db *gorm.DB
+}
+
+// NewChecker creates a checker on top of db
+func NewChecker(db *gorm.DB) *Checker {
+	return &Checker{db: db}
+}
+
+// entity is a person or advocate loaded for matching
+type entity struct {
+	ID            uint
+	Name          string
+	CanonicalName string
+}
+
+// Check matches every name in req and saves the audit record, which it returns
+func (c *Checker) Check(req Request) (*database.ConflictCheck, error) {
+	var checked []string
+	for _, name := range req.Names {
+		if name = strings.TrimSpace(name); name != "" {
+			checked = append(checked, name)
+		}
+	}
+	if len(checked) == 0 {
+		return nil, ErrNoNames
+	}
+	if len(checked) > MaxNames {
+		return nil, ErrTooManyNames
+	}
+	minScore := req.MinScore
+	if minScore <= 0 || minScore > 1 {
+		minScore = DefaultMinScore
+	}
+
+	var persons, advocates []entity
+	if err := c.db.Model(&database.Person{}).Select("id", "name", "canonical_name").Find(&persons).Error; err != nil {
+		return nil, fmt.Errorf("failed to load parties: %w", err)
+	}
+	if err := c.db.Model(&database.Advocate{}).Select("id", "name", "canonical_name").Where("canonical_name != ?", "").Find(&advocates).Error; err != nil {
+		return nil, fmt.Errorf("failed to load advocates: %w", err)
+	}
+
+	record := &database.ConflictCheck{
+		Names:       checked,
+		RequestedBy: req.RequestedBy,
+		Reference:   req.Reference,
+		IPAddress:   req.IPAddress,
+		MinScore:    minScore,
+		Results:     make([]database.ConflictResult, 0, len(checked)),
+	}
+	for _, name := range checked {
+		canonical := names.Canonical(name)
+		result := database.ConflictResult{Name: name, Matches: []database.ConflictMatch{}}
+		if canonical != "" {
+			result.Matches = append(result.Matches, matchEntities("party", canonical, persons, minScore)...)
+			result.Matches = append(result.Matches, matchEntities("advocate", canonical, advocates, minScore)...)
+		}
+		sort.SliceStable(result.Matches, func(i, j int) bool {
+			return result.Matches[i].Score > result.Matches[j].Score
+		})
+
+		for i := range result.Matches {
+			cases, err := c.findCases(&result.Matches[i])
+			if err != nil {
+				return nil, err
+			}
+			result.Matches[i].Cases = cases
+		}
+		record.MatchCount += len(result.Matches)
+		record.Results = append(record.Results, result)
+	}
+
+	if err := c.db.Create(record).Error; err != nil {
+		return nil, fmt.Errorf("failed to save conflict check: %w", err)
+	}
+	return record, nil
+}
+
+// matchEntities scores canonical against every entity of one kind
+func matchEntities(kind, canonical string, entities []entity, minScore float64) []database.ConflictMatch {
+	var matches []database.ConflictMatch
+	for _, e := range entities {
+		score := names.Match(canonical, e.CanonicalName)
+		if score < minScore {
+			continue
+		}
+		matches = append(matches, database.ConflictMatch{
+			Kind:        kind,
+			EntityID:    e.ID,
+			MatchedName: e.Name,
+			Score:       math.Round(score*100) / 100,
+		})
+	}
+	return matches
+}
+
+// findCases lists the cases a match appears in, once per case and role,
+// taking the latest snapshot of each case
+func (c *Checker) findCases(match *database.ConflictMatch) ([]database.ConflictCase, error) {
+	column := "parties.person_id"
+	if match.Kind == "advocate" {
+		column = "parties.advocate_id"
+	}
+
+	var rows []struct {
+		CaseInfoID uint
+		CaseNumber string
+		Status     string
+		Type       string
+		Name       string
+	}
+	err := c.db.Table("parties").
+		Select("case_infos.id AS case_info_id, case_infos.case_number, case_infos.status, parties.type, parties.name").
+		Joins("JOIN case_infos ON case_infos.id = parties.case_info_id AND case_infos.deleted_at IS NULL").
+		Where(column+" = ? AND parties.deleted_at IS NULL", match.EntityID).
+		Order("case_infos.id DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cases for %s %d: %w", match.Kind, match.EntityID, err)
+	}
+
+	cases := []database.ConflictCase{}
+	seen := make(map[string]bool)
+	for _, row := range rows {
+		role := row.Type
+		if match.Kind == "advocate" {
+			role = "Advocate for " + row.Type
+		}
+		key := row.CaseNumber + "\x00" + role
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		cases = append(cases, database.ConflictCase{
+			CaseInfoID: row.CaseInfoID,
+			CaseNumber: row.CaseNumber,
+			Status:     row.Status,
+			Role:       role,
+			PartyName:  row.Name,
+		})
+	}
+	return cases, nil
+}
+
+// List returns audit records, newest first
+func (c *Checker) List(offset, limit int) ([]database.ConflictCheck, int64, error) {
+	var total int64
+	if err := c.db.Model(&database.ConflictCheck{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var checks []database.ConflictCheck
+	err := c.db.Order("id DESC").Offset(offset).Limit(limit).Find(&checks).Error
+	return checks, total, err
+}
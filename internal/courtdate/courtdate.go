@@ -0,0 +1,232 @@
+// Package courtdate reads the dates Indian courts print, such as "12.11.2024",
+// "3rd March, 2025", "Nov. 12, 2024", "5-2-24", "१२ मार्च २०२४" and ranges
+// such as "12-14 March 2024", as midnight in Indian Standard Time
+package courtdate
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IST is Indian Standard Time, which has no daylight saving, so a fixed
+// zone avoids depending on the system's time zone database
+var IST = time.FixedZone("IST", 5*60*60+30*60)
+
+// ErrInvalidDate is wrapped by every error Parse returns
+var ErrInvalidDate = errors.New("invalid date")
+
+// Date is a parsed date or range of dates
+type Date struct {
+	// Time is the date, or the first day of a range, at midnight IST
+	Time time.Time
+	// End is the last day of a range and zero for single dates
+	End time.Time
+	// Ambiguous explains a guess made reading the value, such as reading
+	// "04/13/2024" month first, and is empty when there was none
+	Ambiguous string
+}
+
+// IsRange reports whether the value was a range of dates
+func (d Date) IsRange() bool {
+	return !d.End.IsZero()
+}
+
+var (
+	spaces        = regexp.MustCompile(`\s+`)
+	dayNames      = regexp.MustCompile(`(?i)\b(?:mon|tues|wednes|thurs|fri|satur|sun)day\b,?|(?:सोमवार|मंगलवार|बुधवार|गुरुवार|शुक्रवार|शनिवार|रविवार),?`)
+	ordinals      = regexp.MustCompile(`(?i)(\d)(?:st|nd|rd|th)\b`)
+	numericDate   = regexp.MustCompile(`^(\d{1,4})[./-](\d{1,2})[./-](\d{2,4})$`)
+	dayRange      = regexp.MustCompile(`^(\d{1,2})\s*(?:-|–|to|&|and)\s*(\d{1,2})\s+(\S.*)$`)
+	rangeSplitter = regexp.MustCompile(`(?i)\s+(?:to|till|until|-|–)\s+|\s*–\s*`)
+)
+
+// hindiMonths maps Hindi month names, in their common spellings, to English
+var hindiMonths = map[string]string{
+	"जनवरी": "Jan", "फरवरी": "Feb", "फ़रवरी": "Feb", "मार्च": "Mar",
+	"अप्रैल": "Apr", "अप्रेल": "Apr", "मई": "May", "जून": "Jun",
+	"जुलाई": "Jul", "अगस्त": "Aug", "सितंबर": "Sep", "सितम्बर": "Sep",
+	"अक्टूबर": "Oct", "अक्तूबर": "Oct", "नवंबर": "Nov", "नवम्बर": "Nov",
+	"दिसंबर": "Dec", "दिसम्बर": "Dec",
+}
+
+var devanagariDigits = strings.NewReplacer(
+	"०", "0", "१", "1", "२", "2", "३", "3", "४", "4",
+	"५", "5", "६", "6", "७", "7", "८", "8", "९", "9",
+)
+
+// Parse reads value as a date or a range of dates. Numeric dates are read
+// day first, as courts print them, and two digit years as the nearest year
+// no more than ten years ahead.
+func Parse(value string) (Date, error) {
+	cleaned := normalise(value)
+	if cleaned == "" {
+		return Date{}, fmt.Errorf("%w: empty value", ErrInvalidDate)
+	}
+
+	if date, err := parseSingle(cleaned); err == nil {
+		return date, nil
+	}
+
+	// "12-14 March 2024" shares the month and year between both days
+	if m := dayRange.FindStringSubmatch(cleaned); m != nil {
+		start, err := parseSingle(m[1] + " " + m[3])
+		if err == nil {
+			end, err := parseSingle(m[2] + " " + m[3])
+			if err == nil {
+				return newRange(value, start, end)
+			}
+		}
+	}
+
+	// "12.03.2024 to 14.03.2024"
+	if parts := rangeSplitter.Split(cleaned, -1); len(parts) == 2 {
+		start, err := parseSingle(parts[0])
+		if err == nil {
+			end, err := parseSingle(parts[1])
+			if err == nil {
+				return newRange(value, start, end)
+			}
+		}
+	}
+
+	return Date{}, fmt.Errorf("%w: %q", ErrInvalidDate, value)
+}
+
+// normalise trims day names, ordinal suffixes and extra spaces and rewrites
+// Hindi digits and month names in English
+func normalise(value string) string {
+	value = devanagariDigits.Replace(value)
+	for hindi, english := range hindiMonths {
+		value = strings.ReplaceAll(value, hindi, english)
+	}
+	value = dayNames.ReplaceAllString(value, "")
+	value = ordinals.ReplaceAllString(value, "$1")
+	return strings.TrimSpace(spaces.ReplaceAllString(value, " "))
+}
+
+func newRange(value string, start, end Date) (Date, error) {
+	if end.Time.Before(start.Time) {
+		return Date{}, fmt.Errorf("%w: range %q ends before it starts", ErrInvalidDate, value)
+	}
+	ambiguous := start.Ambiguous
+	if ambiguous == "" {
+		ambiguous = end.Ambiguous
+	}
+	return Date{Time: start.Time, End: end.Time, Ambiguous: ambiguous}, nil
+}
+
+// parseSingle reads one numeric or written date
+func parseSingle(value string) (Date, error) {
+	if m := numericDate.FindStringSubmatch(value); m != nil {
+		return parseNumeric(value, m[1], m[2], m[3])
+	}
+
+	fields := strings.Fields(strings.NewReplacer(",", " ", ".", " ", "-", " ", "/", " ").Replace(value))
+	if len(fields) != 3 {
+		return Date{}, fmt.Errorf("%w: %q", ErrInvalidDate, value)
+	}
+	dayField, monthField := fields[0], fields[1]
+	if _, err := strconv.Atoi(dayField); err != nil {
+		dayField, monthField = monthField, dayField
+	}
+	month, ok := monthNumber(monthField)
+	if !ok {
+		return Date{}, fmt.Errorf("%w: unknown month in %q", ErrInvalidDate, value)
+	}
+	day, err := strconv.Atoi(dayField)
+	if err != nil {
+		return Date{}, fmt.Errorf("%w: %q", ErrInvalidDate, value)
+	}
+	year, guessed, err := parseYear(fields[2])
+	if err != nil {
+		return Date{}, fmt.Errorf("%w: %q", ErrInvalidDate, value)
+	}
+	return newDate(value, year, month, day, guessed)
+}
+
+// parseNumeric reads d-m-y dates, and y-m-d when the first part has four
+// digits. A month over 12 means the date was written month first.
+func parseNumeric(value, first, second, third string) (Date, error) {
+	a, _ := strconv.Atoi(first)
+	b, _ := strconv.Atoi(second)
+	if len(first) == 4 {
+		if len(third) > 2 {
+			return Date{}, fmt.Errorf("%w: %q", ErrInvalidDate, value)
+		}
+		c, _ := strconv.Atoi(third)
+		return newDate(value, a, time.Month(b), c, "")
+	}
+	if len(first) > 2 {
+		return Date{}, fmt.Errorf("%w: %q", ErrInvalidDate, value)
+	}
+
+	year, guessed, err := parseYear(third)
+	if err != nil {
+		return Date{}, fmt.Errorf("%w: %q", ErrInvalidDate, value)
+	}
+	if b > 12 && a <= 12 {
+		date, err := newDate(value, year, time.Month(a), b, guessed)
+		if err == nil && date.Ambiguous == "" {
+			date.Ambiguous = "read month first"
+		}
+		return date, err
+	}
+	return newDate(value, year, time.Month(b), a, guessed)
+}
+
+// parseYear reads a two or four digit year, returning a note when a two
+// digit year had to be placed in a century
+func parseYear(value string) (int, string, error) {
+	year, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, "", err
+	}
+	switch len(value) {
+	case 4:
+		return year, "", nil
+	case 2:
+		year += 2000
+		if year > time.Now().In(IST).Year()+10 {
+			year -= 100
+		}
+		if year < 2000 {
+			return year, fmt.Sprintf("two digit year read as %d", year), nil
+		}
+		return year, "", nil
+	}
+	return 0, "", fmt.Errorf("year %q is not two or four digits", value)
+}
+
+// monthNumber reads English month names and abbreviations such as "Sept."
+func monthNumber(name string) (time.Month, bool) {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	if len(name) < 3 {
+		return 0, false
+	}
+	if name == "sept" {
+		return time.September, true
+	}
+	for month := time.January; month <= time.December; month++ {
+		if strings.HasPrefix(strings.ToLower(month.String()), name) {
+			return month, true
+		}
+	}
+	return 0, false
+}
+
+// newDate builds midnight IST on the given day, rejecting days the month
+// doesn't have rather than letting time.Date roll them over
+func newDate(value string, year int, month time.Month, day int, ambiguous string) (Date, error) {
+	if month < time.January || month > time.December || day < 1 {
+		return Date{}, fmt.Errorf("%w: %q", ErrInvalidDate, value)
+	}
+	t := time.Date(year, month, day, 0, 0, 0, 0, IST)
+	if t.Day() != day {
+		return Date{}, fmt.Errorf("%w: %q has no day %d", ErrInvalidDate, value, day)
+	}
+	return Date{Time: t, Ambiguous: ambiguous}, nil
+}
@@ -0,0 +1,34 @@
+package database
+
+import (
+	"github.com/JustJay7/court-data-fetcher/internal/caseid"
+	"gorm.io/gorm"
+)
+
+type legacyCaseNumber struct {
+	ID         uint
+	CaseNumber string
+}
+
+// backfillCNRs fills in the CNR of snapshots saved before it had a column.
+// The parser used to store a CNR as the case number when the page showed no
+// other, so those are the ones that can be recovered.
+func backfillCNRs(tx *gorm.DB) error {
+	var rows []legacyCaseNumber
+	return tx.Table("case_infos").
+		Select("id", "case_number").
+		Where("cnr IS NULL OR cnr = ?", "").
+		FindInBatches(&rows, 200, func(batch *gorm.DB, _ int) error {
+			session := tx.Session(&gorm.Session{NewDB: true})
+			for _, row := range rows {
+				cnr, err := caseid.ParseCNR(row.CaseNumber)
+				if err != nil {
+					continue
+				}
+				if err := session.Table("case_infos").Where("id = ?", row.ID).Update("cnr", cnr.String()).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
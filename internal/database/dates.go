@@ -0,0 +1,29 @@
+package database
+
+import (
+	"time"
+
+	"github.com/JustJay7/court-data-fetcher/internal/courtdate"
+	"gorm.io/gorm"
+)
+
+// AfterFind reads the case's dates in IST, as PostgreSQL returns them in the
+// session's time zone and they would otherwise print as the day before
+func (c *CaseInfo) AfterFind(tx *gorm.DB) error {
+	c.FilingDate = inIST(c.FilingDate)
+	c.NextHearing = inIST(c.NextHearing)
+	return nil
+}
+
+// AfterFind reads the order date in IST, like CaseInfo.AfterFind
+func (o *Order) AfterFind(tx *gorm.DB) error {
+	o.OrderDate = inIST(o.OrderDate)
+	return nil
+}
+
+func inIST(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return t.In(courtdate.IST)
+}
@@ -4,37 +4,90 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/JustJay7/court-data-fetcher/internal/config"
+	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
-func Initialize(dbPath string) (*gorm.DB, error) {
-	dir := filepath.Dir(dbPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create database directory: %w", err)
+// Initialize opens the database and applies any pending migrations
+func Initialize(cfg *config.Config) (*gorm.DB, error) {
+	db, err := Open(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := Migrate(db); err != nil {
+		return nil, fmt.Errorf("failed to run migrations: %w", err)
 	}
 
-	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+	return db, nil
+}
+
+// Open connects to the configured database without touching its schema.
+// DATABASE_URL selects PostgreSQL; otherwise SQLite is used at DatabasePath.
+func Open(cfg *config.Config) (*gorm.DB, error) {
+	dialector, err := newDialector(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Silent),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	if err := Migrate(db); err != nil {
-		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database handle: %w", err)
+	}
+	if cfg.DBMaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(cfg.DBMaxOpenConns)
+	}
+	if cfg.DBMaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(cfg.DBMaxIdleConns)
+	}
+	if cfg.DBConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(cfg.DBConnMaxLifetime)
 	}
 
 	return db, nil
 }
 
+// newDialector picks the driver for the configured database
+func newDialector(cfg *config.Config) (gorm.Dialector, error) {
+	url := cfg.DatabaseURL
+	switch {
+	case strings.HasPrefix(url, "postgres://"), strings.HasPrefix(url, "postgresql://"):
+		return postgres.Open(url), nil
+	case url == "":
+		return openSQLite(cfg.DatabasePath)
+	case strings.HasPrefix(url, "sqlite://"):
+		return openSQLite(strings.TrimPrefix(url, "sqlite://"))
+	default:
+		return nil, fmt.Errorf("unsupported DATABASE_URL scheme: %s", url)
+	}
+}
+
+func openSQLite(dbPath string) (gorm.Dialector, error) {
+	dir := filepath.Dir(dbPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create database directory: %w", err)
+	}
+	return sqlite.Open(dbPath), nil
+}
+
+// Migrate brings the schema up to the latest embedded migration. It refuses
+// to touch a database that was migrated by a newer build.
 func Migrate(db *gorm.DB) error {
-	return db.AutoMigrate(
-		&QueryLog{},
-		&CaseInfo{},
-		&Order{},
-		&Party{},
-	)
-}
\ No newline at end of file
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	return migrator.Up()
+}
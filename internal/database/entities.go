@@ -0,0 +1,149 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/JustJay7/court-data-fetcher/internal/names"
+	"gorm.io/gorm"
+)
+
+// linkParties resolves each party's name and advocate to Person and Advocate
+// entities, creating those not seen before
+func linkParties(tx *gorm.DB, parties []Party) error {
+	for i := range parties {
+		personID, err := resolvePerson(tx, parties[i].Name)
+		if err != nil {
+			return err
+		}
+		advocateID, err := resolveAdvocate(tx, parties[i].AdvocateName, parties[i].AdvocateCode)
+		if err != nil {
+			return err
+		}
+		parties[i].PersonID = personID
+		parties[i].AdvocateID = advocateID
+	}
+	return nil
+}
+
+// resolvePerson returns the ID of the person name refers to, or nil for a
+// name with nothing to match on
+func resolvePerson(tx *gorm.DB, name string) (*uint, error) {
+	canonical := names.Canonical(name)
+	if canonical == "" {
+		return nil, nil
+	}
+
+	var candidates []Person
+	if err := tx.Where("block_key = ?", names.BlockKey(canonical)).Find(&candidates).Error; err != nil {
+		return nil, fmt.Errorf("failed to look up person: %w", err)
+	}
+	best, bestScore := -1, 0.0
+	for i, candidate := range candidates {
+		if !names.SamePerson(canonical, candidate.CanonicalName) {
+			continue
+		}
+		if score := names.Similarity(canonical, candidate.CanonicalName); score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	if best >= 0 {
+		return &candidates[best].ID, nil
+	}
+
+	person := Person{Name: name, CanonicalName: canonical, BlockKey: names.BlockKey(canonical)}
+	if err := tx.Create(&person).Error; err != nil {
+		return nil, fmt.Errorf("failed to create person: %w", err)
+	}
+	return &person.ID, nil
+}
+
+// resolveAdvocate returns the ID of the advocate with code, or failing that
+// the one whose name matches and whose code doesn't contradict it
+func resolveAdvocate(tx *gorm.DB, name, code string) (*uint, error) {
+	code = names.AdvocateCode(code)
+	canonical := names.Canonical(name)
+	if code == "" && canonical == "" {
+		return nil, nil
+	}
+
+	if code != "" {
+		var advocate Advocate
+		err := tx.Where("code = ?", code).Limit(1).Find(&advocate).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up advocate: %w", err)
+		}
+		if advocate.ID != 0 {
+			return &advocate.ID, nil
+		}
+	}
+
+	if canonical != "" {
+		var candidates []Advocate
+		if err := tx.Where("block_key = ?", names.BlockKey(canonical)).Find(&candidates).Error; err != nil {
+			return nil, fmt.Errorf("failed to look up advocate: %w", err)
+		}
+		best, bestScore := -1, 0.0
+		for i, candidate := range candidates {
+			if code != "" && candidate.Code != "" {
+				continue
+			}
+			if !names.SamePerson(canonical, candidate.CanonicalName) {
+				continue
+			}
+			if score := names.Similarity(canonical, candidate.CanonicalName); score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		if best >= 0 {
+			advocate := &candidates[best]
+			// First time the court shows this advocate's enrolment number
+			if code != "" {
+				if err := tx.Model(advocate).Update("code", code).Error; err != nil {
+					return nil, fmt.Errorf("failed to update advocate: %w", err)
+				}
+			}
+			return &advocate.ID, nil
+		}
+	}
+
+	advocate := Advocate{Code: code, Name: name, CanonicalName: canonical}
+	if canonical != "" {
+		advocate.BlockKey = names.BlockKey(canonical)
+	}
+	if err := tx.Create(&advocate).Error; err != nil {
+		return nil, fmt.Errorf("failed to create advocate: %w", err)
+	}
+	return &advocate.ID, nil
+}
+
+type legacyParty struct {
+	ID           uint
+	Name         string
+	AdvocateName string
+	AdvocateCode string
+}
+
+// linkExistingParties resolves the parties saved before entities existed
+func linkExistingParties(tx *gorm.DB) error {
+	var rows []legacyParty
+	return tx.Table("parties").
+		Select("id", "name", "advocate_name", "advocate_code").
+		FindInBatches(&rows, 200, func(batch *gorm.DB, _ int) error {
+			session := tx.Session(&gorm.Session{NewDB: true})
+			for _, row := range rows {
+				personID, err := resolvePerson(session, row.Name)
+				if err != nil {
+					return err
+				}
+				advocateID, err := resolveAdvocate(session, row.AdvocateName, row.AdvocateCode)
+				if err != nil {
+					return err
+				}
+				if err := session.Table("parties").Where("id = ?", row.ID).
+					Updates(map[string]interface{}{"person_id": personID, "advocate_id": advocateID}).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
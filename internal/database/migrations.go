@@ -1,45 +1,373 @@
 package database
 
 import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
 	"gorm.io/gorm"
 )
 
-// RunMigrations executes all database migrations
-func RunMigrations(db *gorm.DB) error {
-	// Create indexes for better performance
-	if err := createIndexes(db); err != nil {
-		return fmt.Errorf("failed to create indexes: %w", err)
+//go:embed migrations
+var migrationFiles embed.FS
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+var (
+	// ErrSchemaTooNew is returned when the database was migrated by a newer build
+	ErrSchemaTooNew = errors.New("database schema is newer than this build")
+	// ErrChecksumMismatch is returned when an applied migration was edited afterwards
+	ErrChecksumMismatch = errors.New("applied migration does not match its source")
+	// ErrUnknownVersion is returned when migrating to a version that doesn't exist
+	ErrUnknownVersion = errors.New("unknown migration version")
+)
+
+// dataMigration moves existing rows where SQL alone can't. Before runs ahead
+// of the version's SQL, Up after it and Down before its rollback, inside the
+// same transaction.
+type dataMigration struct {
+	Before func(tx *gorm.DB) error
+	Up     func(tx *gorm.DB) error
+	Down   func(tx *gorm.DB) error
+}
+
+// dataMigrations are keyed by the migration version they belong to
+var dataMigrations = map[int]dataMigration{
+	1:  {Before: addLegacyQueryLogColumns},
+	4:  {Up: moveRawResponses, Down: restoreRawResponses},
+	8:  {Up: linkExistingParties},
+	10: {Up: backfillCNRs},
+	13: {Up: classifyExistingOrders},
+}
+
+// Migration is one numbered schema change with its rollback
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Checksum identifies the up script so later edits to it can be detected
+func (m Migration) Checksum() string {
+	sum := sha256.Sum256([]byte(m.Up))
+	return hex.EncodeToString(sum[:])
+}
+
+// SchemaMigration records an applied migration
+type SchemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null"`
+	Checksum  string    `gorm:"not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// MigrationStatus describes a known migration against the database
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+	// Modified is set when the migration was applied from a different script
+	Modified bool
+}
+
+// Migrator applies and rolls back the embedded SQL migrations
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator loads the migrations for db's dialect
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	migrations, err := LoadMigrations(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// LoadMigrations reads the embedded migrations for a dialect, ordered by version
+func LoadMigrations(dialect string) ([]Migration, error) {
+	dir := path.Join("migrations", dialect)
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for %s: %w", dialect, err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		data, err := migrationFiles.ReadFile(path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, m.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			m.Up = string(data)
+		} else {
+			m.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both up and down scripts", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Latest returns the highest known migration version
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Version returns the highest applied migration version
+func (m *Migrator) Version() (int, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return 0, err
+	}
+
+	version := 0
+	for v := range applied {
+		if v > version {
+			version = v
+		}
+	}
+	return version, nil
+}
+
+// Verify refuses schemas written by a newer build and applied migrations
+// whose scripts have changed since
+func (m *Migrator) Verify() error {
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+
+	known := make(map[int]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+
+	for version, record := range applied {
+		migration, ok := known[version]
+		if !ok {
+			if version > m.Latest() {
+				return fmt.Errorf("%w: database is at version %d, latest known is %d", ErrSchemaTooNew, version, m.Latest())
+			}
+			return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+		}
+		if record.Checksum != migration.Checksum() {
+			return fmt.Errorf("%w: %d_%s", ErrChecksumMismatch, version, migration.Name)
+		}
 	}
 
 	return nil
 }
 
-// createIndexes creates database indexes
-func createIndexes(db *gorm.DB) error {
-	// Index for case searches
-	if err := db.Exec(`
-		CREATE INDEX IF NOT EXISTS idx_case_info_search 
-		ON case_infos(case_type, case_number, filing_year)
-	`).Error; err != nil {
+// Up applies every pending migration
+func (m *Migrator) Up() error {
+	return m.To(m.Latest())
+}
+
+// Down rolls back the most recently applied migration
+func (m *Migrator) Down() error {
+	version, err := m.Version()
+	if err != nil {
 		return err
 	}
+	if version == 0 {
+		return nil
+	}
 
-	// Index for query logs
-	if err := db.Exec(`
-		CREATE INDEX IF NOT EXISTS idx_query_logs_time 
-		ON query_logs(query_time)
-	`).Error; err != nil {
+	target := 0
+	for _, migration := range m.migrations {
+		if migration.Version < version {
+			target = migration.Version
+		}
+	}
+	return m.To(target)
+}
+
+// To migrates up or down until version is the latest applied migration.
+// Version 0 rolls back every migration.
+func (m *Migrator) To(version int) error {
+	if version != 0 && !m.known(version) {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+	if err := m.Verify(); err != nil {
 		return err
 	}
 
-	// Index for orders by date
-	if err := db.Exec(`
-		CREATE INDEX IF NOT EXISTS idx_orders_date 
-		ON orders(order_date)
-	`).Error; err != nil {
+	applied, err := m.applied()
+	if err != nil {
 		return err
 	}
 
+	// Roll back newest first, then apply oldest first
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; ok && migration.Version > version {
+			if err := m.rollback(migration); err != nil {
+				return err
+			}
+		}
+	}
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok && migration.Version <= version {
+			if err := m.apply(migration); err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
-}
\ No newline at end of file
+}
+
+// Status lists every known migration with whether it has been applied
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Migration: migration}
+		if record, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = record.AppliedAt
+			status.Modified = record.Checksum != migration.Checksum()
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func (m *Migrator) apply(migration Migration) error {
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		if data, ok := dataMigrations[migration.Version]; ok && data.Before != nil {
+			if err := data.Before(tx); err != nil {
+				return err
+			}
+		}
+		if err := tx.Exec(migration.Up).Error; err != nil {
+			return err
+		}
+		if data, ok := dataMigrations[migration.Version]; ok && data.Up != nil {
+			if err := data.Up(tx); err != nil {
+				return err
+			}
+		}
+		return tx.Create(&SchemaMigration{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			Checksum:  migration.Checksum(),
+			AppliedAt: time.Now(),
+		}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+func (m *Migrator) rollback(migration Migration) error {
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		if data, ok := dataMigrations[migration.Version]; ok && data.Down != nil {
+			if err := data.Down(tx); err != nil {
+				return err
+			}
+		}
+		if err := tx.Exec(migration.Down).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&SchemaMigration{}, migration.Version).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to roll back migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+	return nil
+}
+
+// applied returns the recorded migrations, creating the tracking table if needed
+func (m *Migrator) applied() (map[int]SchemaMigration, error) {
+	if err := m.db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	var records []SchemaMigration
+	if err := m.db.Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+
+	applied := make(map[int]SchemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+func (m *Migrator) known(version int) bool {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// addLegacyQueryLogColumns adds the query_logs columns that AutoMigrate never
+// created, so the baseline script can index them. CREATE TABLE IF NOT EXISTS
+// leaves an existing table as it was, and SQLite has no ADD COLUMN IF NOT
+// EXISTS to do this from SQL.
+func addLegacyQueryLogColumns(tx *gorm.DB) error {
+	if !tx.Migrator().HasTable("query_logs") {
+		return nil
+	}
+	for _, column := range []string{"error_code", "source"} {
+		if tx.Migrator().HasColumn("query_logs", column) {
+			continue
+		}
+		if err := tx.Exec("ALTER TABLE query_logs ADD COLUMN " + column + " text").Error; err != nil {
+			return fmt.Errorf("failed to add query_logs.%s: %w", column, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS cache_entries;
+DROP TABLE IF EXISTS parties;
+DROP TABLE IF EXISTS orders;
+DROP TABLE IF EXISTS case_infos;
+DROP TABLE IF EXISTS query_logs;
@@ -0,0 +1,79 @@
+-- Baseline schema. Tables are created only if missing so databases that were
+-- previously set up by AutoMigrate can adopt versioned migrations in place.
+
+CREATE TABLE IF NOT EXISTS query_logs (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    case_type text,
+    case_number text,
+    filing_year text,
+    raw_response text,
+    success boolean,
+    error_message text,
+    error_code text,
+    query_time timestamptz,
+    ip_address text,
+    source text
+);
+CREATE INDEX IF NOT EXISTS idx_query_logs_error_code ON query_logs (error_code);
+CREATE INDEX IF NOT EXISTS idx_query_logs_deleted_at ON query_logs (deleted_at);
+
+CREATE TABLE IF NOT EXISTS case_infos (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    query_log_id bigint,
+    case_number text,
+    case_type text,
+    filing_year text,
+    filing_date timestamptz,
+    next_hearing timestamptz,
+    status text,
+    judge text,
+    court_complex text
+);
+CREATE INDEX IF NOT EXISTS idx_case_infos_case_number ON case_infos (case_number);
+CREATE INDEX IF NOT EXISTS idx_case_infos_deleted_at ON case_infos (deleted_at);
+
+CREATE TABLE IF NOT EXISTS orders (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    case_info_id bigint,
+    order_date timestamptz,
+    description text,
+    pdf_link text,
+    order_type text,
+    judge_name text,
+    downloaded boolean,
+    local_path text,
+    CONSTRAINT fk_case_infos_orders FOREIGN KEY (case_info_id) REFERENCES case_infos (id)
+);
+CREATE INDEX IF NOT EXISTS idx_orders_deleted_at ON orders (deleted_at);
+
+CREATE TABLE IF NOT EXISTS parties (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    case_info_id bigint,
+    name text,
+    type text,
+    advocate_name text,
+    advocate_code text,
+    CONSTRAINT fk_case_infos_parties FOREIGN KEY (case_info_id) REFERENCES case_infos (id)
+);
+CREATE INDEX IF NOT EXISTS idx_parties_deleted_at ON parties (deleted_at);
+
+CREATE TABLE IF NOT EXISTS cache_entries (
+    key text PRIMARY KEY,
+    value bytea,
+    not_found boolean,
+    expires_at timestamptz,
+    created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_cache_entries_expires_at ON cache_entries (expires_at);
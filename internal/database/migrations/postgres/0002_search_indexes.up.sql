@@ -0,0 +1,8 @@
+-- Index for case searches
+CREATE INDEX IF NOT EXISTS idx_case_info_search ON case_infos (case_type, case_number, filing_year);
+
+-- Index for query logs
+CREATE INDEX IF NOT EXISTS idx_query_logs_time ON query_logs (query_time);
+
+-- Index for orders by date
+CREATE INDEX IF NOT EXISTS idx_orders_date ON orders (order_date);
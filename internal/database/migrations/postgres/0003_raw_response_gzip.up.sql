@@ -0,0 +1,2 @@
+-- Raw HTML older than the retention period is kept gzip-compressed here
+ALTER TABLE query_logs ADD COLUMN IF NOT EXISTS raw_response_gzip bytea;
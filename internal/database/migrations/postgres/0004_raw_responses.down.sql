@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_query_logs_raw_response_hash;
+ALTER TABLE query_logs DROP COLUMN IF EXISTS raw_response_hash;
+DROP TABLE IF EXISTS raw_responses;
@@ -0,0 +1,11 @@
+-- Raw pages are stored once per distinct content, gzip-compressed. Existing
+-- pages on query_logs are moved here by the Go data migration for version 4.
+CREATE TABLE IF NOT EXISTS raw_responses (
+    hash text PRIMARY KEY,
+    data bytea NOT NULL,
+    size bigint,
+    created_at timestamptz
+);
+
+ALTER TABLE query_logs ADD COLUMN IF NOT EXISTS raw_response_hash text;
+CREATE INDEX IF NOT EXISTS idx_query_logs_raw_response_hash ON query_logs (raw_response_hash);
@@ -0,0 +1,2 @@
+ALTER TABLE query_logs ADD COLUMN IF NOT EXISTS raw_response text;
+ALTER TABLE query_logs ADD COLUMN IF NOT EXISTS raw_response_gzip bytea;
@@ -0,0 +1,3 @@
+-- Pages now live in raw_responses
+ALTER TABLE query_logs DROP COLUMN IF EXISTS raw_response;
+ALTER TABLE query_logs DROP COLUMN IF EXISTS raw_response_gzip;
@@ -0,0 +1,11 @@
+-- Screenshots, network logs, console output and DOM captured for failed scrapes
+CREATE TABLE IF NOT EXISTS debug_artifacts (
+    id bigserial PRIMARY KEY,
+    query_log_id bigint,
+    kind text,
+    content_type text,
+    data bytea,
+    size bigint,
+    created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_debug_artifacts_query_log_id ON debug_artifacts (query_log_id);
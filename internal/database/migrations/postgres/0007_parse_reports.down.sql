@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_query_logs_parse_confidence;
+ALTER TABLE query_logs DROP COLUMN IF EXISTS parse_confidence;
+ALTER TABLE query_logs DROP COLUMN IF EXISTS parse_report;
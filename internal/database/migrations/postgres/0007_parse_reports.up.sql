@@ -0,0 +1,4 @@
+-- How the parser read each successfully scraped page, see database.ParseReport
+ALTER TABLE query_logs ADD COLUMN IF NOT EXISTS parse_report text;
+ALTER TABLE query_logs ADD COLUMN IF NOT EXISTS parse_confidence double precision;
+CREATE INDEX IF NOT EXISTS idx_query_logs_parse_confidence ON query_logs (parse_confidence);
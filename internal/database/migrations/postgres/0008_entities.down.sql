@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_parties_advocate_id;
+DROP INDEX IF EXISTS idx_parties_person_id;
+ALTER TABLE parties DROP COLUMN IF EXISTS advocate_id;
+ALTER TABLE parties DROP COLUMN IF EXISTS person_id;
+DROP TABLE IF EXISTS advocates;
+DROP TABLE IF EXISTS persons;
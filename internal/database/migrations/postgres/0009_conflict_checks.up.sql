@@ -0,0 +1,13 @@
+-- Audit trail of conflict-of-interest checks
+CREATE TABLE IF NOT EXISTS conflict_checks (
+    id bigserial PRIMARY KEY,
+    names text,
+    requested_by text,
+    reference text,
+    ip_address text,
+    min_score double precision,
+    match_count bigint,
+    results text,
+    created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_conflict_checks_created_at ON conflict_checks (created_at);
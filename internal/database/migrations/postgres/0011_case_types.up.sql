@@ -0,0 +1,12 @@
+-- Case types offered by the court's search form, see catalogue.Catalogue
+CREATE TABLE IF NOT EXISTS case_types (
+    id bigserial PRIMARY KEY,
+    value text,
+    label text,
+    active boolean,
+    last_seen_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_case_types_label ON case_types (label);
+CREATE INDEX IF NOT EXISTS idx_case_types_active ON case_types (active);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS cause_list_entries;
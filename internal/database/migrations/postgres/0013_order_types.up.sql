@@ -0,0 +1,3 @@
+-- Order type assigned by ordertype.Classifier and how sure it is
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS order_type_confidence double precision;
+CREATE INDEX IF NOT EXISTS idx_orders_order_type ON orders (order_type);
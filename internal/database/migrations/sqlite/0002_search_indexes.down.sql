@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS `idx_orders_date`;
+DROP INDEX IF EXISTS `idx_query_logs_time`;
+DROP INDEX IF EXISTS `idx_case_info_search`;
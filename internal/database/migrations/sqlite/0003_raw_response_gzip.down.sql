@@ -0,0 +1 @@
+ALTER TABLE `query_logs` DROP COLUMN `raw_response_gzip`;
@@ -0,0 +1,2 @@
+-- Raw HTML older than the retention period is kept gzip-compressed here
+ALTER TABLE `query_logs` ADD COLUMN `raw_response_gzip` blob;
@@ -0,0 +1,2 @@
+ALTER TABLE `query_logs` ADD COLUMN `raw_response` text;
+ALTER TABLE `query_logs` ADD COLUMN `raw_response_gzip` blob;
@@ -0,0 +1,11 @@
+-- Screenshots, network logs, console output and DOM captured for failed scrapes
+CREATE TABLE IF NOT EXISTS `debug_artifacts` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `query_log_id` integer,
+    `kind` text,
+    `content_type` text,
+    `data` blob,
+    `size` integer,
+    `created_at` datetime
+);
+CREATE INDEX IF NOT EXISTS `idx_debug_artifacts_query_log_id` ON `debug_artifacts`(`query_log_id`);
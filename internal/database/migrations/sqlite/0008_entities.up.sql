@@ -0,0 +1,33 @@
+-- Litigants and advocates resolved across cases, linked from parties
+CREATE TABLE IF NOT EXISTS `persons` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `name` text,
+    `canonical_name` text,
+    `block_key` text
+);
+CREATE INDEX IF NOT EXISTS `idx_persons_deleted_at` ON `persons`(`deleted_at`);
+CREATE INDEX IF NOT EXISTS `idx_persons_canonical_name` ON `persons`(`canonical_name`);
+CREATE INDEX IF NOT EXISTS `idx_persons_block_key` ON `persons`(`block_key`);
+
+CREATE TABLE IF NOT EXISTS `advocates` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `code` text,
+    `name` text,
+    `canonical_name` text,
+    `block_key` text
+);
+CREATE INDEX IF NOT EXISTS `idx_advocates_deleted_at` ON `advocates`(`deleted_at`);
+CREATE INDEX IF NOT EXISTS `idx_advocates_code` ON `advocates`(`code`);
+CREATE INDEX IF NOT EXISTS `idx_advocates_canonical_name` ON `advocates`(`canonical_name`);
+CREATE INDEX IF NOT EXISTS `idx_advocates_block_key` ON `advocates`(`block_key`);
+
+ALTER TABLE `parties` ADD COLUMN `person_id` integer;
+ALTER TABLE `parties` ADD COLUMN `advocate_id` integer;
+CREATE INDEX IF NOT EXISTS `idx_parties_person_id` ON `parties`(`person_id`);
+CREATE INDEX IF NOT EXISTS `idx_parties_advocate_id` ON `parties`(`advocate_id`);
@@ -0,0 +1,13 @@
+-- Audit trail of conflict-of-interest checks
+CREATE TABLE IF NOT EXISTS `conflict_checks` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `names` text,
+    `requested_by` text,
+    `reference` text,
+    `ip_address` text,
+    `min_score` real,
+    `match_count` integer,
+    `results` text,
+    `created_at` datetime
+);
+CREATE INDEX IF NOT EXISTS `idx_conflict_checks_created_at` ON `conflict_checks`(`created_at`);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS `idx_case_infos_cnr`;
+ALTER TABLE `case_infos` DROP COLUMN `cnr`;
@@ -0,0 +1,3 @@
+-- eCourts CNR number of each case snapshot, see caseid.CNR
+ALTER TABLE `case_infos` ADD COLUMN `cnr` text;
+CREATE INDEX IF NOT EXISTS `idx_case_infos_cnr` ON `case_infos`(`cnr`);
@@ -0,0 +1,12 @@
+-- Case types offered by the court's search form, see catalogue.Catalogue
+CREATE TABLE IF NOT EXISTS `case_types` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `value` text,
+    `label` text,
+    `active` numeric,
+    `last_seen_at` datetime,
+    `created_at` datetime,
+    `updated_at` datetime
+);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_case_types_label` ON `case_types`(`label`);
+CREATE INDEX IF NOT EXISTS `idx_case_types_active` ON `case_types`(`active`);
@@ -0,0 +1,16 @@
+-- Daily cause list items, see causelist.Service
+CREATE TABLE IF NOT EXISTS `cause_list_entries` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `list_date` text,
+    `court_room` text,
+    `bench` text,
+    `item_number` text,
+    `case_number` text,
+    `parties` text,
+    `advocate` text,
+    `case_info_id` integer,
+    `created_at` datetime
+);
+CREATE INDEX IF NOT EXISTS `idx_cause_list_entries_list_date` ON `cause_list_entries`(`list_date`);
+CREATE INDEX IF NOT EXISTS `idx_cause_list_entries_case_number` ON `cause_list_entries`(`case_number`);
+CREATE INDEX IF NOT EXISTS `idx_cause_list_entries_case_info_id` ON `cause_list_entries`(`case_info_id`);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS `idx_orders_order_type`;
+ALTER TABLE `orders` DROP COLUMN `order_type_confidence`;
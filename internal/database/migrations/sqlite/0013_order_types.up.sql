@@ -0,0 +1,3 @@
+-- Order type assigned by ordertype.Classifier and how sure it is
+ALTER TABLE `orders` ADD COLUMN `order_type_confidence` real;
+CREATE INDEX IF NOT EXISTS `idx_orders_order_type` ON `orders`(`order_type`);
@@ -0,0 +1,2 @@
+ALTER TABLE `case_infos` DROP COLUMN `next_hearing_note`;
+ALTER TABLE `case_infos` DROP COLUMN `next_hearing_source`;
@@ -0,0 +1,3 @@
+-- Where each snapshot's next hearing date was read, see CaseInfo.NextHearingSource
+ALTER TABLE `case_infos` ADD COLUMN `next_hearing_source` text;
+ALTER TABLE `case_infos` ADD COLUMN `next_hearing_note` text;
@@ -11,26 +11,74 @@ type QueryLog struct {
 	CaseType     string    `json:"case_type"`
 	CaseNumber   string    `json:"case_number"`
 	FilingYear   string    `json:"filing_year"`
-	RawResponse  string    `json:"raw_response" gorm:"type:text"`
+	// RawResponse is only set while saving a page, which is then stored
+	// compressed in raw_responses and referenced by RawResponseHash
+	RawResponse     string `json:"raw_response,omitempty" gorm:"-"`
+	RawResponseHash string `json:"raw_response_hash" gorm:"index"`
 	Success      bool      `json:"success"`
 	ErrorMessage string    `json:"error_message"`
+	ErrorCode    string    `json:"error_code" gorm:"index"`
 	QueryTime    time.Time `json:"query_time"`
 	IPAddress    string    `json:"ip_address"`
+	Source       string    `json:"source"`
+	// ParseReport and ParseConfidence are set for successful scrapes
+	ParseReport     *ParseReport `json:"parse_report,omitempty" gorm:"serializer:json"`
+	ParseConfidence *float64     `json:"parse_confidence,omitempty" gorm:"index"`
 }
 
+// ParseReport describes how the parser read a case page
+type ParseReport struct {
+	// Fields maps each field that was found to the strategy that read it
+	Fields map[string]string `json:"fields"`
+	// Missing lists expected fields the page didn't yield
+	Missing []string `json:"missing"`
+	// Confidence is 1 when every expected field came from a structured
+	// strategy, lower as fields go missing or come from looser ones
+	Confidence float64 `json:"confidence"`
+	// DateIssues lists dates that couldn't be read or were read with a guess
+	DateIssues []DateIssue `json:"date_issues,omitempty"`
+}
+
+// DateIssue is a date the parser couldn't read as printed
+type DateIssue struct {
+	Field   string `json:"field"`
+	Value   string `json:"value"`
+	Problem string `json:"problem"`
+	// Detail explains the guess made for ambiguous dates
+	Detail string `json:"detail,omitempty"`
+}
+
+// Problems recorded in a DateIssue
+const (
+	DateUnreadable = "unreadable"
+	DateAmbiguous  = "ambiguous"
+	// DateRange is a range given for a single date, of which the first day is used
+	DateRange = "range"
+)
+
 type CaseInfo struct {
 	gorm.Model
 	QueryLogID    uint      `json:"query_log_id"`
 	CaseNumber    string    `json:"case_number" gorm:"index"`
+	CNR           string    `json:"cnr,omitempty" gorm:"index"`
 	CaseType      string    `json:"case_type"`
 	FilingYear    string    `json:"filing_year"`
 	FilingDate    time.Time `json:"filing_date"`
 	NextHearing   time.Time `json:"next_hearing"`
+	// NextHearingSource says where NextHearing was read, one of the
+	// NextHearingFrom constants, and NextHearingNote quotes the order
+	// direction it came from
+	NextHearingSource string `json:"next_hearing_source,omitempty"`
+	NextHearingNote   string `json:"next_hearing_note,omitempty"`
 	Status        string    `json:"status"`
 	Judge         string    `json:"judge"`
 	CourtComplex  string    `json:"court_complex"`
 	Parties       []Party   `json:"parties" gorm:"foreignKey:CaseInfoID"`
 	Orders        []Order   `json:"orders" gorm:"foreignKey:CaseInfoID"`
+	// ParseReport is set by the parser and stored on the query log
+	ParseReport *ParseReport `json:"parse_report,omitempty" gorm:"-"`
+	// ListedToday is the case's entry on today's cause list, if any
+	ListedToday *CauseListEntry `json:"listed_today,omitempty" gorm:"-"`
 }
 
 type Party struct {
@@ -40,6 +88,28 @@ type Party struct {
 	Type          string `json:"type"`
 	AdvocateName  string `json:"advocate_name"`
 	AdvocateCode  string `json:"advocate_code"`
+	// PersonID and AdvocateID link the party to the entities its names
+	// resolve to, see names.Canonical
+	PersonID   *uint `json:"person_id,omitempty" gorm:"index"`
+	AdvocateID *uint `json:"advocate_id,omitempty" gorm:"index"`
+}
+
+// Person is a litigant appearing across cases under one canonical name
+type Person struct {
+	gorm.Model
+	Name          string `json:"name"`
+	CanonicalName string `json:"canonical_name" gorm:"index"`
+	BlockKey      string `json:"-" gorm:"index"`
+}
+
+// Advocate is a lawyer appearing across cases, identified by bar enrolment
+// code where the court shows one and by canonical name otherwise
+type Advocate struct {
+	gorm.Model
+	Code          string `json:"code" gorm:"index"`
+	Name          string `json:"name"`
+	CanonicalName string `json:"canonical_name" gorm:"index"`
+	BlockKey      string `json:"-" gorm:"index"`
 }
 
 type Order struct {
@@ -48,12 +118,116 @@ type Order struct {
 	OrderDate    time.Time `json:"order_date"`
 	Description  string    `json:"description"`
 	PDFLink      string    `json:"pdf_link"`
-	OrderType    string    `json:"order_type"`
+	// OrderType and OrderTypeConfidence are set by ordertype.Classifier
+	OrderType           string  `json:"order_type" gorm:"index"`
+	OrderTypeConfidence float64 `json:"order_type_confidence"`
 	JudgeName    string    `json:"judge_name"`
 	Downloaded   bool      `json:"downloaded"`
 	LocalPath    string    `json:"local_path"`
 }
 
+// ConflictCheck is the audit record of one conflict-of-interest check,
+// keeping what was asked and what was reported
+type ConflictCheck struct {
+	ID          uint             `json:"id" gorm:"primaryKey"`
+	Names       []string         `json:"names" gorm:"serializer:json"`
+	RequestedBy string           `json:"requested_by"`
+	Reference   string           `json:"reference"`
+	IPAddress   string           `json:"ip_address"`
+	MinScore    float64          `json:"min_score"`
+	MatchCount  int              `json:"match_count"`
+	Results     []ConflictResult `json:"results" gorm:"serializer:json"`
+	CreatedAt   time.Time        `json:"created_at" gorm:"index"`
+}
+
+// ConflictResult lists the stored parties and advocates matching one name
+type ConflictResult struct {
+	Name    string          `json:"name"`
+	Matches []ConflictMatch `json:"matches"`
+}
+
+// ConflictMatch is a person or advocate resembling a checked name, with the
+// cases they appear in
+type ConflictMatch struct {
+	Kind        string         `json:"kind"` // "party" or "advocate"
+	EntityID    uint           `json:"entity_id"`
+	MatchedName string         `json:"matched_name"`
+	Score       float64        `json:"score"`
+	Cases       []ConflictCase `json:"cases"`
+}
+
+// ConflictCase is one case a match appears in and the role they had
+type ConflictCase struct {
+	CaseInfoID uint   `json:"case_info_id"`
+	CaseNumber string `json:"case_number"`
+	Status     string `json:"status"`
+	Role       string `json:"role"`
+	PartyName  string `json:"party_name"`
+}
+
+// CaseType is one option of the case type dropdown on the court's search
+// form. Types the form stops offering are kept but marked inactive.
+type CaseType struct {
+	ID         uint      `json:"-" gorm:"primaryKey"`
+	Value      string    `json:"value"`
+	Label      string    `json:"label" gorm:"uniqueIndex"`
+	Active     bool      `json:"active" gorm:"index"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// CauseListEntry is one item of a court's daily cause list, matched to the
+// stored case with the same case number. ListDate is kept as YYYY-MM-DD so
+// a day compares equal whatever the database's time zone.
+type CauseListEntry struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	ListDate   string    `json:"list_date" gorm:"index"`
+	CourtRoom  string    `json:"court_room"`
+	Bench      string    `json:"bench"`
+	ItemNumber string    `json:"item_number"`
+	CaseNumber string    `json:"case_number" gorm:"index"`
+	Parties    string    `json:"parties"`
+	Advocate   string    `json:"advocate"`
+	CaseInfoID *uint     `json:"case_info_id,omitempty" gorm:"index"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// CacheEntry is a serialised cache value stored by the SQLite cache backend
+type CacheEntry struct {
+	Key       string    `json:"key" gorm:"primaryKey"`
+	Value     []byte    `json:"-"`
+	NotFound  bool      `json:"not_found"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"index"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Sources of CaseInfo.NextHearing
+const (
+	NextHearingFromPage     = "details_page"
+	NextHearingFromOrder    = "order"
+	NextHearingFromOrderPDF = "order_pdf"
+)
+
+// Debug artifact kinds
+const (
+	ArtifactScreenshot = "screenshot"
+	ArtifactHAR        = "har"
+	ArtifactConsole    = "console"
+	ArtifactDOM        = "dom"
+)
+
+// DebugArtifact is one file of the debug bundle captured for a failed scrape
+type DebugArtifact struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	QueryLogID  uint      `json:"query_log_id" gorm:"index"`
+	Kind        string    `json:"kind"`
+	ContentType string    `json:"content_type"`
+	Data        []byte    `json:"-"`
+	Size        int       `json:"size"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
 func (QueryLog) TableName() string {
 	return "query_logs"
 }
@@ -66,6 +240,34 @@ func (Party) TableName() string {
 	return "parties"
 }
 
+func (Person) TableName() string {
+	return "persons"
+}
+
+func (Advocate) TableName() string {
+	return "advocates"
+}
+
 func (Order) TableName() string {
 	return "orders"
-}
\ No newline at end of file
+}
+
+func (ConflictCheck) TableName() string {
+	return "conflict_checks"
+}
+
+func (CaseType) TableName() string {
+	return "case_types"
+}
+
+func (CauseListEntry) TableName() string {
+	return "cause_list_entries"
+}
+
+func (CacheEntry) TableName() string {
+	return "cache_entries"
+}
+
+func (DebugArtifact) TableName() string {
+	return "debug_artifacts"
+}
@@ -0,0 +1,35 @@
+package database
+
+import (
+	"github.com/JustJay7/court-data-fetcher/internal/ordertype"
+	"gorm.io/gorm"
+)
+
+type unclassifiedOrder struct {
+	ID          uint
+	Description string
+}
+
+// classifyExistingOrders labels orders saved before they were classified,
+// from their description and the default rules. PDF text and configured
+// rules are applied by the reclassify command.
+func classifyExistingOrders(tx *gorm.DB) error {
+	classifier := ordertype.Default()
+	var rows []unclassifiedOrder
+	return tx.Table("orders").
+		Select("id", "description").
+		Where("order_type IS NULL OR order_type = ?", "").
+		FindInBatches(&rows, 200, func(batch *gorm.DB, _ int) error {
+			session := tx.Session(&gorm.Session{NewDB: true})
+			for _, row := range rows {
+				result := classifier.Classify(row.Description, "")
+				if err := session.Table("orders").Where("id = ?", row.ID).Updates(map[string]interface{}{
+					"order_type":            result.Type,
+					"order_type_confidence": result.Confidence,
+				}).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
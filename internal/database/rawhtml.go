@@ -0,0 +1,173 @@
+package database
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RawResponseBlob is a gzip-compressed court page, stored once per distinct
+// page and referenced from QueryLog.RawResponseHash
+type RawResponseBlob struct {
+	Hash      string    `json:"hash" gorm:"primaryKey"`
+	Data      []byte    `json:"-" gorm:"not null"`
+	Size      int       `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (RawResponseBlob) TableName() string {
+	return "raw_responses"
+}
+
+// HashRawResponse returns the content address of a page
+func HashRawResponse(html string) string {
+	sum := sha256.Sum256([]byte(html))
+	return hex.EncodeToString(sum[:])
+}
+
+// BeforeSave moves a page set on RawResponse into raw_responses, so every
+// write path stores it compressed and only once
+func (q *QueryLog) BeforeSave(tx *gorm.DB) error {
+	if q.RawResponse == "" {
+		return nil
+	}
+
+	hash := HashRawResponse(q.RawResponse)
+	if err := storeRawResponse(tx.Session(&gorm.Session{NewDB: true}), hash, q.RawResponse); err != nil {
+		return err
+	}
+	q.RawResponseHash = hash
+	return nil
+}
+
+// RawResponse returns the page stored for a query log, or "" if none was kept
+func (r *Repository) RawResponse(queryLog *QueryLog) (string, error) {
+	if queryLog.RawResponse != "" {
+		return queryLog.RawResponse, nil
+	}
+	if queryLog.RawResponseHash == "" {
+		return "", nil
+	}
+	return loadRawResponse(r.db, queryLog.RawResponseHash)
+}
+
+func storeRawResponse(db *gorm.DB, hash, html string) error {
+	// Identical pages are common, skip compressing one that is already stored
+	var existing int64
+	if err := db.Model(&RawResponseBlob{}).Where("hash = ?", hash).Count(&existing).Error; err != nil {
+		return fmt.Errorf("failed to look up raw response: %w", err)
+	}
+	if existing > 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := io.WriteString(w, html); err != nil {
+		return fmt.Errorf("failed to compress raw response: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to compress raw response: %w", err)
+	}
+
+	err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&RawResponseBlob{
+		Hash: hash,
+		Data: buf.Bytes(),
+		Size: len(html),
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to store raw response: %w", err)
+	}
+	return nil
+}
+
+func loadRawResponse(db *gorm.DB, hash string) (string, error) {
+	var blob RawResponseBlob
+	result := db.Where("hash = ?", hash).Limit(1).Find(&blob)
+	if result.Error != nil {
+		return "", fmt.Errorf("failed to load raw response: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return "", nil
+	}
+	return decompress(blob.Data)
+}
+
+func decompress(data []byte) (string, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to decompress raw response: %w", err)
+	}
+	defer r.Close()
+
+	html, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to decompress raw response: %w", err)
+	}
+	return string(html), nil
+}
+
+// legacyRawResponse is a query log row from before pages moved to raw_responses
+type legacyRawResponse struct {
+	ID              uint
+	RawResponse     string
+	RawResponseGzip []byte
+	RawResponseHash string
+}
+
+// moveRawResponses copies pages stored inline on query_logs into raw_responses
+func moveRawResponses(tx *gorm.DB) error {
+	var rows []legacyRawResponse
+	return tx.Table("query_logs").
+		Select("id", "raw_response", "raw_response_gzip").
+		Where("raw_response != ? OR raw_response_gzip IS NOT NULL", "").
+		FindInBatches(&rows, 200, func(batch *gorm.DB, _ int) error {
+			for _, row := range rows {
+				html := row.RawResponse
+				if len(row.RawResponseGzip) > 0 {
+					var err error
+					if html, err = decompress(row.RawResponseGzip); err != nil {
+						return fmt.Errorf("query log %d: %w", row.ID, err)
+					}
+				}
+
+				hash := HashRawResponse(html)
+				if err := storeRawResponse(tx.Session(&gorm.Session{NewDB: true}), hash, html); err != nil {
+					return err
+				}
+				if err := tx.Session(&gorm.Session{NewDB: true}).Table("query_logs").Where("id = ?", row.ID).
+					Updates(map[string]interface{}{"raw_response_hash": hash, "raw_response": "", "raw_response_gzip": nil}).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
+
+// restoreRawResponses writes pages back onto query_logs before raw_responses is dropped
+func restoreRawResponses(tx *gorm.DB) error {
+	var rows []legacyRawResponse
+	return tx.Table("query_logs").
+		Select("id", "raw_response_hash").
+		Where("raw_response_hash IS NOT NULL AND raw_response_hash != ?", "").
+		FindInBatches(&rows, 200, func(batch *gorm.DB, _ int) error {
+			for _, row := range rows {
+				html, err := loadRawResponse(tx.Session(&gorm.Session{NewDB: true}), row.RawResponseHash)
+				if err != nil {
+					return err
+				}
+				if err := tx.Session(&gorm.Session{NewDB: true}).Table("query_logs").Where("id = ?", row.ID).
+					Update("raw_response", html).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
@@ -0,0 +1,304 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/JustJay7/court-data-fetcher/internal/names"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrSnapshotExists is returned when a case that already has an ID is saved
+// again as a new snapshot
+var ErrSnapshotExists = errors.New("case snapshot already saved")
+
+// Repository wraps the multi-table reads and writes around scraped cases
+type Repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a repository on top of db
+func NewRepository(db *gorm.DB) *Repository {
+	return &Repository{db: db}
+}
+
+// CreateQueryLog inserts a new query log entry
+func (r *Repository) CreateQueryLog(queryLog *QueryLog) error {
+	if err := r.db.Create(queryLog).Error; err != nil {
+		return fmt.Errorf("failed to create query log: %w", err)
+	}
+	return nil
+}
+
+// UpdateQueryLog saves the outcome fields of an existing query log
+func (r *Repository) UpdateQueryLog(queryLog *QueryLog) error {
+	if err := r.db.Save(queryLog).Error; err != nil {
+		return fmt.Errorf("failed to update query log: %w", err)
+	}
+	return nil
+}
+
+// SaveCaseSnapshot stores a scraped case with its parties and orders and
+// marks queryLog successful with the case's parse report, all in a single
+// transaction. Associations are
+// inserted explicitly so each party and order is written exactly once.
+func (r *Repository) SaveCaseSnapshot(queryLog *QueryLog, caseInfo *CaseInfo) error {
+	if caseInfo.ID != 0 {
+		return ErrSnapshotExists
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		queryLog.Success = true
+		queryLog.ErrorMessage = ""
+		queryLog.ErrorCode = ""
+		if report := caseInfo.ParseReport; report != nil {
+			confidence := report.Confidence
+			queryLog.ParseReport = report
+			queryLog.ParseConfidence = &confidence
+		}
+		if err := tx.Save(queryLog).Error; err != nil {
+			return fmt.Errorf("failed to update query log: %w", err)
+		}
+
+		caseInfo.QueryLogID = queryLog.ID
+		if err := tx.Omit(clause.Associations).Create(caseInfo).Error; err != nil {
+			return fmt.Errorf("failed to save case info: %w", err)
+		}
+
+		for i := range caseInfo.Parties {
+			caseInfo.Parties[i].ID = 0
+			caseInfo.Parties[i].CaseInfoID = caseInfo.ID
+		}
+		if err := linkParties(tx, caseInfo.Parties); err != nil {
+			return err
+		}
+		if len(caseInfo.Parties) > 0 {
+			if err := tx.Create(&caseInfo.Parties).Error; err != nil {
+				return fmt.Errorf("failed to save parties: %w", err)
+			}
+		}
+
+		for i := range caseInfo.Orders {
+			caseInfo.Orders[i].ID = 0
+			caseInfo.Orders[i].CaseInfoID = caseInfo.ID
+		}
+		if len(caseInfo.Orders) > 0 {
+			if err := tx.Create(&caseInfo.Orders).Error; err != nil {
+				return fmt.Errorf("failed to save orders: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		// Nothing was written, so don't leave IDs from the rolled back inserts behind
+		caseInfo.ID = 0
+		caseInfo.QueryLogID = 0
+		for i := range caseInfo.Parties {
+			caseInfo.Parties[i].ID = 0
+			caseInfo.Parties[i].PersonID = nil
+			caseInfo.Parties[i].AdvocateID = nil
+		}
+		for i := range caseInfo.Orders {
+			caseInfo.Orders[i].ID = 0
+		}
+		queryLog.Success = false
+	}
+
+	return err
+}
+
+// FindQueryLog loads a query log by ID
+func (r *Repository) FindQueryLog(id uint) (*QueryLog, error) {
+	var queryLog QueryLog
+	if err := r.db.First(&queryLog, id).Error; err != nil {
+		return nil, err
+	}
+	return &queryLog, nil
+}
+
+// FindCaseSnapshot loads a case with its parties and orders
+func (r *Repository) FindCaseSnapshot(id uint) (*CaseInfo, error) {
+	var caseInfo CaseInfo
+	if err := r.db.Preload("Parties").Preload("Orders").First(&caseInfo, id).Error; err != nil {
+		return nil, err
+	}
+	return &caseInfo, nil
+}
+
+// FindCaseSnapshotByQueryLog loads the case saved for a query log
+func (r *Repository) FindCaseSnapshotByQueryLog(queryLogID uint) (*CaseInfo, error) {
+	var caseInfo CaseInfo
+	if err := r.db.Where("query_log_id = ?", queryLogID).
+		Preload("Parties").
+		Preload("Orders").
+		First(&caseInfo).Error; err != nil {
+		return nil, err
+	}
+	return &caseInfo, nil
+}
+
+// FindLatestCaseByCNR loads the newest snapshot of the case with a CNR
+func (r *Repository) FindLatestCaseByCNR(cnr string) (*CaseInfo, error) {
+	var caseInfo CaseInfo
+	if err := r.db.Where("cnr = ?", cnr).
+		Preload("Parties").
+		Preload("Orders").
+		Order("id DESC").
+		First(&caseInfo).Error; err != nil {
+		return nil, err
+	}
+	return &caseInfo, nil
+}
+
+// FindPerson loads a person by ID
+func (r *Repository) FindPerson(id uint) (*Person, error) {
+	var person Person
+	if err := r.db.First(&person, id).Error; err != nil {
+		return nil, err
+	}
+	return &person, nil
+}
+
+// FindAdvocateByCode loads the advocate with a bar enrolment code
+func (r *Repository) FindAdvocateByCode(code string) (*Advocate, error) {
+	var advocate Advocate
+	if err := r.db.Where("code = ?", names.AdvocateCode(code)).First(&advocate).Error; err != nil {
+		return nil, err
+	}
+	return &advocate, nil
+}
+
+// FindAdvocate loads an advocate by ID
+func (r *Repository) FindAdvocate(id uint) (*Advocate, error) {
+	var advocate Advocate
+	if err := r.db.First(&advocate, id).Error; err != nil {
+		return nil, err
+	}
+	return &advocate, nil
+}
+
+// FindCasesForPerson lists the cases a person is a party to, newest first
+func (r *Repository) FindCasesForPerson(personID uint) ([]CaseInfo, error) {
+	return r.findCasesForParties("parties.person_id = ?", personID)
+}
+
+// FindCasesForAdvocate lists the cases an advocate appears in, newest first
+func (r *Repository) FindCasesForAdvocate(advocateID uint) ([]CaseInfo, error) {
+	return r.findCasesForParties("parties.advocate_id = ?", advocateID)
+}
+
+// findCasesForParties returns the latest snapshot of each case with a party
+// matching the condition, since every scrape of a case saves a new one
+func (r *Repository) findCasesForParties(condition string, args ...interface{}) ([]CaseInfo, error) {
+	latest := r.db.Model(&CaseInfo{}).
+		Select("MAX(case_infos.id)").
+		Joins("JOIN parties ON parties.case_info_id = case_infos.id AND parties.deleted_at IS NULL").
+		Where(condition, args...).
+		Group("case_infos.case_number")
+
+	var cases []CaseInfo
+	err := r.db.Preload("Parties").
+		Where("id IN (?)", latest).
+		Order("id DESC").
+		Find(&cases).Error
+	return cases, err
+}
+
+// OrderFilter selects orders for FindOrders, empty fields match any order
+type OrderFilter struct {
+	Type          string
+	CaseNumber    string
+	MinConfidence float64
+	Limit         int
+	Offset        int
+}
+
+// OrderListing is an order with the number of its case
+type OrderListing struct {
+	Order
+	CaseNumber string `json:"case_number"`
+}
+
+// FindOrders lists the orders of the latest snapshot of each case, newest
+// first, with the number of orders matching the filter
+func (r *Repository) FindOrders(filter OrderFilter) ([]OrderListing, int64, error) {
+	latest := r.db.Model(&CaseInfo{}).Select("MAX(id)").Group("case_number")
+	query := r.db.Model(&Order{}).
+		Joins("JOIN case_infos ON case_infos.id = orders.case_info_id").
+		Where("orders.case_info_id IN (?)", latest)
+	if filter.Type != "" {
+		query = query.Where("orders.order_type = ?", filter.Type)
+	}
+	if filter.CaseNumber != "" {
+		query = query.Where("case_infos.case_number = ?", filter.CaseNumber)
+	}
+	if filter.MinConfidence > 0 {
+		query = query.Where("orders.order_type_confidence >= ?", filter.MinConfidence)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var orders []OrderListing
+	err := query.Select("orders.*, case_infos.case_number").
+		Order("orders.order_date DESC, orders.id DESC").
+		Limit(filter.Limit).Offset(filter.Offset).
+		Scan(&orders).Error
+	return orders, total, err
+}
+
+// SaveDebugArtifacts stores the debug bundle files for a failed query
+func (r *Repository) SaveDebugArtifacts(queryLogID uint, artifacts []DebugArtifact) error {
+	if len(artifacts) == 0 {
+		return nil
+	}
+	for i := range artifacts {
+		artifacts[i].QueryLogID = queryLogID
+		artifacts[i].Size = len(artifacts[i].Data)
+	}
+	if err := r.db.Create(&artifacts).Error; err != nil {
+		return fmt.Errorf("failed to save debug artifacts: %w", err)
+	}
+	return nil
+}
+
+// FindDebugArtifacts lists the artifacts for a query log without their data
+func (r *Repository) FindDebugArtifacts(queryLogID uint) ([]DebugArtifact, error) {
+	var artifacts []DebugArtifact
+	err := r.db.Omit("data").
+		Where("query_log_id = ?", queryLogID).
+		Order("id").
+		Find(&artifacts).Error
+	return artifacts, err
+}
+
+// FindDebugArtifact loads one artifact of a query log including its data
+func (r *Repository) FindDebugArtifact(queryLogID, id uint) (*DebugArtifact, error) {
+	var artifact DebugArtifact
+	if err := r.db.Where("query_log_id = ?", queryLogID).First(&artifact, id).Error; err != nil {
+		return nil, err
+	}
+	return &artifact, nil
+}
+
+// FindDebugArtifactsFor lists the artifacts, without data, of each given query log
+func (r *Repository) FindDebugArtifactsFor(queryLogIDs []uint) (map[uint][]DebugArtifact, error) {
+	var artifacts []DebugArtifact
+	err := r.db.Omit("data").
+		Where("query_log_id IN ?", queryLogIDs).
+		Order("id").
+		Find(&artifacts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byQueryLog := make(map[uint][]DebugArtifact)
+	for _, artifact := range artifacts {
+		byQueryLog[artifact.QueryLogID] = append(byQueryLog[artifact.QueryLogID], artifact)
+	}
+	return byQueryLog, nil
+}
@@ -0,0 +1,147 @@
+package mockcourt
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+)
+
+// CaseTypes are the options offered in the case type dropdown
+var CaseTypes = []string{
+	"ARB.P.",
+	"CRL.A.",
+	"CRL.M.C.",
+	"CS(COMM)",
+	"CS(OS)",
+	"FAO",
+	"LPA",
+	"MAT.APP.",
+	"RFA",
+	"W.P.(C)",
+	"W.P.(CRL)",
+}
+
+// Case is a case the mock court can find
+type Case struct {
+	Type        string
+	Number      string
+	Year        string
+	CNR         string
+	FilingDate  time.Time
+	NextHearing time.Time
+	Status      string
+	Judge       string
+	Court       string
+	Petitioners []string
+	Respondents []string
+	// Advocates for the first petitioner and respondent
+	PetitionerAdvocate string
+	RespondentAdvocate string
+	Orders             []Order
+}
+
+// Order is an order listed for a case, served as a PDF
+type Order struct {
+	Date        time.Time
+	Description string
+	Judge       string
+}
+
+// CaseNumber is the number as the court prints it, e.g. W.P.(C)/1234/2023
+func (c Case) CaseNumber() string {
+	return fmt.Sprintf("%s/%s/%s", c.Type, c.Number, c.Year)
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// SampleCases are the cases every new Server knows
+func SampleCases() []Case {
+	return []Case{
+		{
+			Type:               "W.P.(C)",
+			Number:             "1234",
+			Year:               "2023",
+			CNR:                "DLHC010012342023",
+			FilingDate:         date(2023, time.February, 6),
+			NextHearing:        date(2024, time.March, 18),
+			Status:             "Pending",
+			Judge:              "Hon'ble Mr. Justice A. K. Mehra",
+			Court:              "High Court of Delhi",
+			Petitioners:        []string{"Sunita Sharma", "Rakesh Sharma"},
+			Respondents:        []string{"Union of India", "Delhi Development Authority"},
+			PetitionerAdvocate: "Mr. Arvind Nair",
+			RespondentAdvocate: "Ms. Kavita Rao, CGSC",
+			Orders: []Order{
+				{Date: date(2023, time.February, 10), Description: "Notice issued. Reply within four weeks.", Judge: "A. K. Mehra, J."},
+				{Date: date(2023, time.July, 21), Description: "Counter affidavit filed. Rejoinder within two weeks.", Judge: "A. K. Mehra, J."},
+				{Date: date(2023, time.November, 29), Description: "Part heard. List for final arguments.", Judge: "A. K. Mehra, J."},
+			},
+		},
+		{
+			Type:               "CRL.A.",
+			Number:             "245",
+			Year:               "2022",
+			CNR:                "DLHC020002452022",
+			FilingDate:         date(2022, time.September, 5),
+			NextHearing:        date(2024, time.January, 9),
+			Status:             "Pending",
+			Judge:              "Hon'ble Ms. Justice N. Bansal",
+			Court:              "High Court of Delhi",
+			Petitioners:        []string{"Ramesh Chand"},
+			Respondents:        []string{"State (NCT of Delhi)"},
+			PetitionerAdvocate: "Mr. Vikram Singh",
+			RespondentAdvocate: "Mr. P. Kumar, APP",
+			Orders: []Order{
+				{Date: date(2023, time.November, 2), Description: "Arguments heard in part.", Judge: "N. Bansal, J."},
+			},
+		},
+		{
+			Type:        "CS(COMM)",
+			Number:      "88",
+			Year:        "2021",
+			CNR:         "DLHC010000882021",
+			FilingDate:  date(2021, time.January, 14),
+			Status:      "Disposed",
+			Judge:       "Hon'ble Mr. Justice R. Gupta",
+			Court:       "High Court of Delhi",
+			Petitioners: []string{"Sharma Traders Pvt. Ltd."},
+			Respondents: []string{"Gupta Sons"},
+			Orders: []Order{
+				{Date: date(2021, time.March, 3), Description: "Summons issued.", Judge: "R. Gupta, J."},
+				{Date: date(2022, time.August, 17), Description: "Suit decreed in terms of settlement.", Judge: "R. Gupta, J."},
+			},
+		},
+	}
+}
+
+// orderPDF builds a one page PDF naming the order
+func orderPDF(c Case, order Order) []byte {
+	text := fmt.Sprintf("%s - order dated %s", c.CaseNumber(), order.Date.Format("02-01-2006"))
+	content := fmt.Sprintf("BT /F1 12 Tf 72 720 Td (%s) Tj ET", text)
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
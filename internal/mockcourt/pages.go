@@ -0,0 +1,194 @@
+package mockcourt
+
+import (
+	"html/template"
+	"strings"
+	"time"
+)
+
+type formPage struct {
+	CaseTypes    []string
+	Years        []int
+	Token        string
+	Code         string
+	CaptchaImage bool
+	Error        string
+}
+
+type resultsPage struct {
+	ID   int
+	Case Case
+}
+
+type detailsPage struct {
+	ID   int
+	Case Case
+}
+
+type causeListPage struct {
+	Date    time.Time
+	Benches []causeListBench
+}
+
+type causeListBench struct {
+	Room  string
+	Judge string
+	Cases []Case
+}
+
+var pageFuncs = template.FuncMap{
+	"date": func(layout string, t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(layout)
+	},
+	"inc":  func(i int) int { return i + 1 },
+	"join": strings.Join,
+}
+
+func page(name, body string) *template.Template {
+	return template.Must(template.New(name).Funcs(pageFuncs).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Case Status | High Court of Delhi</title>
+</head>
+<body>
+<header><h1>High Court of Delhi</h1></header>
+` + body + `
+</body>
+</html>
+`))
+}
+
+var formTemplate = page("form", `
+<h2>Case Status</h2>
+{{if .Error}}<div class="alert alert-danger">{{.Error}}</div>{{end}}
+<form method="post" action="/app/get-case-type-status">
+  <label for="case_type">Case Type</label>
+  <select id="case_type" name="case_type">
+    <option value="">Select</option>
+    {{range .CaseTypes}}<option value="{{.}}">{{.}}</option>
+    {{end}}
+  </select>
+
+  <label for="case_number">Case Number</label>
+  <input type="text" id="case_number" name="case_number">
+
+  <label for="case_year">Year</label>
+  <select id="case_year" name="case_year">
+    <option value="">Select</option>
+    {{range .Years}}<option value="{{.}}">{{.}}</option>
+    {{end}}
+  </select>
+
+  <input type="hidden" name="captcha_token" value="{{.Token}}">
+  {{if .CaptchaImage}}<img id="captcha_image" src="/app/captcha-image?token={{.Token}}" alt="Security code">
+  {{else}}<span id="captcha-code">{{.Code}}</span>
+  {{end}}<input type="text" id="captchaInput" name="captchaInput" autocomplete="off">
+
+  <button type="submit" id="search">Submit</button>
+</form>
+`)
+
+var resultsTemplate = page("results", `
+<h2>Search Results</h2>
+<table class="table">
+  <tr><th>S.No.</th><th>Diary No. / Case No.</th><th>Parties</th><th>Listing Date / Court No.</th><th></th></tr>
+  <tr>
+    <td>1</td>
+    <td>{{.Case.CaseNumber}}<br>[{{.Case.Status}}]</td>
+    <td>{{join .Case.Petitioners ", "}} Vs. {{join .Case.Respondents ", "}}</td>
+    <td>{{date "02-01-2006" .Case.NextHearing}}</td>
+    <td><a href="/app/view-case-details?id={{.ID}}">View</a></td>
+  </tr>
+</table>
+`)
+
+var detailsTableTemplate = page("details-table", `
+<nav><a href="/app/get-case-type-status">New Search</a> | <a href="/app/case-orders?id={{.ID}}">Orders</a></nav>
+<div class="container">
+  <h2>Case Details</h2>
+  <table class="case-details">
+    <tr><th>Case Number</th><td>{{.Case.CaseNumber}}</td></tr>
+    <tr><th>CNR Number</th><td>{{.Case.CNR}}</td></tr>
+    <tr><th>Case Type</th><td>{{.Case.Type}}</td></tr>
+    <tr><th>Date of Filing</th><td>{{date "02-01-2006" .Case.FilingDate}}</td></tr>
+    <tr><th>Next Hearing Date</th><td>{{date "02-01-2006" .Case.NextHearing}}</td></tr>
+    <tr><th>Case Status</th><td>{{.Case.Status}}</td></tr>
+    <tr><th>Coram</th><td>{{.Case.Judge}}</td></tr>
+    <tr><th>Court</th><td>{{.Case.Court}}</td></tr>
+  </table>
+</div>
+<table id="party_table">
+  <tr><th>Party</th><th>Name</th><th>Advocate</th></tr>
+  {{range $i, $name := .Case.Petitioners}}<tr><td>Petitioner</td><td>{{$name}}</td><td>{{if eq $i 0}}{{$.Case.PetitionerAdvocate}}{{end}}</td></tr>
+  {{end}}{{range $i, $name := .Case.Respondents}}<tr><td>Respondent</td><td>{{$name}}</td><td>{{if eq $i 0}}{{$.Case.RespondentAdvocate}}{{end}}</td></tr>
+  {{end}}
+</table>
+`)
+
+var detailsDivsTemplate = page("details-divs", `
+<nav><a href="/app/get-case-type-status">New Search</a> | <a href="/app/case-orders?id={{.ID}}">Orders</a></nav>
+<div id="case_details">
+  <div class="row"><span class="label">Case No:</span><span class="value">{{.Case.CaseNumber}}</span></div>
+  <div class="row"><span class="label">CNR:</span><span class="value">{{.Case.CNR}}</span></div>
+  <div class="row"><span class="label">Case Type:</span><span class="value">{{.Case.Type}}</span></div>
+  <div class="row"><span class="label">Year:</span><span class="value">{{.Case.Year}}</span></div>
+  <div class="row"><span class="label">Filing Date:</span><span class="value">{{date "02 Jan 2006" .Case.FilingDate}}</span></div>
+  <div class="row"><span class="label">Next Date:</span><span class="value">{{date "02 Jan 2006" .Case.NextHearing}}</span></div>
+  <div class="row"><span class="label">Status:</span><span class="value">{{.Case.Status}}</span></div>
+  <div class="row"><span class="label">Judge:</span><span class="value">{{.Case.Judge}}</span></div>
+</div>
+<div id="petitioner_respondent">
+  <div class="petitioner">{{join .Case.Petitioners " and "}}</div>
+  <div class="respondent">{{join .Case.Respondents " and "}}</div>
+</div>
+`)
+
+var detailsTextTemplate = page("details-text", `
+<nav><a href="/app/get-case-type-status">New Search</a> | <a href="/app/case-orders?id={{.ID}}">Orders</a></nav>
+<div class="case-info">
+  <p>Case No. {{.Case.CaseNumber}}</p>
+  <p>CNR Number: {{.Case.CNR}}</p>
+  <p>Filing Year: {{.Case.Year}}</p>
+  <p>Filing Date: {{date "02-01-2006" .Case.FilingDate}}</p>
+  <p>Next Date of Hearing: {{date "02-01-2006" .Case.NextHearing}}</p>
+  <p>Status: {{.Case.Status}}</p>
+  <p>Petitioner: {{join .Case.Petitioners " and "}}<br>
+  Respondent: {{join .Case.Respondents " and "}}</p>
+</div>
+`)
+
+var ordersTemplate = page("orders", `
+<nav><a href="/app/view-case-details?id={{.ID}}">Case Details</a></nav>
+<h2>Orders / Judgments in {{.Case.CaseNumber}}</h2>
+<table id="order_table">
+  <tr><th>Order Date</th><th>Details</th><th>Coram</th><th>Order</th></tr>
+  {{range $i, $order := .Case.Orders}}<tr>
+    <td>{{date "02-01-2006" $order.Date}}</td>
+    <td>{{$order.Description}}</td>
+    <td>{{$order.Judge}}</td>
+    <td><a href="/app/orders/{{$.ID}}/{{inc $i}}.pdf">View PDF</a></td>
+  </tr>
+  {{end}}
+</table>
+`)
+
+var causeListTemplate = page("cause-list", `
+<h2>Cause List for {{date "02-01-2006" .Date}}</h2>
+{{range .Benches}}<table class="cause-list">
+  <tr><th colspan="4">COURT NO. {{.Room}}</th></tr>
+  <tr><th colspan="4">{{.Judge}}</th></tr>
+  <tr><th>Item No.</th><th>Case No.</th><th>Parties</th><th>Advocate</th></tr>
+  {{range $i, $c := .Cases}}<tr>
+    <td>{{inc $i}}.</td>
+    <td>{{$c.Type}} {{$c.Number}}/{{$c.Year}}</td>
+    <td>{{join $c.Petitioners ", "}} Vs. {{join $c.Respondents ", "}}</td>
+    <td>{{$c.PetitionerAdvocate}}</td>
+  </tr>
+  {{end}}
+</table>
+{{else}}<p>No cause list has been published for this date.</p>
+{{end}}`)
@@ -0,0 +1,347 @@
+// Package mockcourt is a stand-in for the court website that serves the same
+// case status flow the scraper drives, so it can run without the network
+package mockcourt
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Scenario decides how a search is answered
+type Scenario string
+
+const (
+	// ScenarioNormal finds every case the server knows about
+	ScenarioNormal Scenario = "normal"
+	// ScenarioNotFound answers every search with "No record found"
+	ScenarioNotFound Scenario = "not_found"
+	// ScenarioCaptchaRejected rejects every CAPTCHA, right or wrong
+	ScenarioCaptchaRejected Scenario = "captcha_rejected"
+)
+
+// Layout picks the markup of the case details page, one per parser strategy
+type Layout string
+
+const (
+	LayoutTable Layout = "table"
+	LayoutDivs  Layout = "divs"
+	LayoutText  Layout = "text"
+)
+
+// Paths served by the mock, relative to the base URL
+const (
+	StatusPath    = "/app/get-case-type-status"
+	CauseListPath = "/app/cause-list"
+	captchaPath   = "/app/captcha-image"
+	detailsPath   = "/app/view-case-details"
+	ordersPath    = "/app/case-orders"
+	pdfPath       = "/app/orders/"
+)
+
+// maxPendingCaptchas bounds the codes kept for forms not yet submitted
+const maxPendingCaptchas = 1000
+
+// Config controls the behaviour of a Server. It can be changed between
+// requests with SetConfig.
+type Config struct {
+	Scenario Scenario
+	Layout   Layout
+	// Delay is added before every response to simulate a slow court
+	Delay time.Duration
+	// CaptchaImage shows the CAPTCHA only as an image, as most courts do,
+	// instead of printing the code next to the input
+	CaptchaImage bool
+}
+
+// Server is an http.Handler that imitates the court's case status pages
+type Server struct {
+	mu       sync.RWMutex
+	cfg      Config
+	cases    []Case
+	captchas map[string]string
+	searches int
+	mux      *http.ServeMux
+}
+
+// New creates a mock court that knows the sample cases
+func New(cfg Config) *Server {
+	s := &Server{
+		cases:    SampleCases(),
+		captchas: make(map[string]string),
+		mux:      http.NewServeMux(),
+	}
+	s.SetConfig(cfg)
+
+	s.mux.HandleFunc(StatusPath, s.handleStatus)
+	s.mux.HandleFunc(captchaPath, s.handleCaptchaImage)
+	s.mux.HandleFunc(detailsPath, s.handleDetails)
+	s.mux.HandleFunc(ordersPath, s.handleOrders)
+	s.mux.HandleFunc(pdfPath, s.handlePDF)
+	s.mux.HandleFunc(CauseListPath, s.handleCauseList)
+	return s
+}
+
+// SetConfig replaces the scenario, layout and delay
+func (s *Server) SetConfig(cfg Config) {
+	if cfg.Scenario == "" {
+		cfg.Scenario = ScenarioNormal
+	}
+	if cfg.Layout == "" {
+		cfg.Layout = LayoutTable
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cfg = cfg
+}
+
+// Config returns the current configuration
+func (s *Server) Config() Config {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cfg
+}
+
+// AddCase makes another case searchable
+func (s *Server) AddCase(c Case) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cases = append(s.cases, c)
+}
+
+// Searches returns how many search forms have been submitted
+func (s *Server) Searches() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.searches
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if delay := s.Config().Delay; delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// handleStatus shows the search form and answers its submission
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.renderForm(w, "")
+	case http.MethodPost:
+		s.handleSearch(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.searches++
+	cfg := s.cfg
+	code, ok := s.captchas[r.PostFormValue("captcha_token")]
+	delete(s.captchas, r.PostFormValue("captcha_token"))
+	s.mu.Unlock()
+
+	answer := strings.TrimSpace(r.PostFormValue("captchaInput"))
+	if cfg.Scenario == ScenarioCaptchaRejected || !ok || answer != code {
+		s.renderForm(w, "Invalid Captcha, please try again")
+		return
+	}
+
+	id, found := s.findCase(r.PostFormValue("case_type"), r.PostFormValue("case_number"), r.PostFormValue("case_year"))
+	if cfg.Scenario == ScenarioNotFound || !found {
+		s.renderForm(w, "No record found for the given case details")
+		return
+	}
+
+	s.render(w, resultsTemplate, resultsPage{ID: id, Case: s.caseByID(id)})
+}
+
+func (s *Server) renderForm(w http.ResponseWriter, message string) {
+	token, code := s.newCaptcha()
+	cfg := s.Config()
+
+	years := make([]int, 0, 50)
+	for year := time.Now().Year(); year > time.Now().Year()-50; year-- {
+		years = append(years, year)
+	}
+
+	s.render(w, formTemplate, formPage{
+		CaseTypes:    CaseTypes,
+		Years:        years,
+		Token:        token,
+		Code:         code,
+		CaptchaImage: cfg.CaptchaImage,
+		Error:        message,
+	})
+}
+
+func (s *Server) handleCaptchaImage(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	code, ok := s.captchas[r.URL.Query().Get("token")]
+	s.mu.RUnlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := writeCaptchaPNG(w, code); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *Server) handleDetails(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.caseID(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	page := detailsPage{ID: id, Case: s.caseByID(id)}
+	switch s.Config().Layout {
+	case LayoutDivs:
+		s.render(w, detailsDivsTemplate, page)
+	case LayoutText:
+		s.render(w, detailsTextTemplate, page)
+	default:
+		s.render(w, detailsTableTemplate, page)
+	}
+}
+
+func (s *Server) handleOrders(w http.ResponseWriter, r *http.Request) {
+	id, ok := s.caseID(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	s.render(w, ordersTemplate, detailsPage{ID: id, Case: s.caseByID(id)})
+}
+
+// handlePDF serves /app/orders/<case id>/<order number>.pdf
+func (s *Server) handlePDF(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, pdfPath), "/")
+	if len(parts) != 2 || !strings.HasSuffix(parts[1], ".pdf") {
+		http.NotFound(w, r)
+		return
+	}
+	id, err1 := strconv.Atoi(parts[0])
+	n, err2 := strconv.Atoi(strings.TrimSuffix(parts[1], ".pdf"))
+	if err1 != nil || err2 != nil || !s.validID(id) {
+		http.NotFound(w, r)
+		return
+	}
+
+	c := s.caseByID(id)
+	if n < 1 || n > len(c.Orders) {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Write(orderPDF(c, c.Orders[n-1]))
+}
+
+// handleCauseList publishes the cases whose next hearing falls on
+// ?date=DD-MM-YYYY, one court room per judge in the order first seen
+func (s *Server) handleCauseList(w http.ResponseWriter, r *http.Request) {
+	day, err := time.Parse("02-01-2006", r.URL.Query().Get("date"))
+	if err != nil {
+		http.Error(w, "invalid date", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.RLock()
+	page := causeListPage{Date: day}
+	rooms := make(map[string]int)
+	for _, c := range s.cases {
+		if !c.NextHearing.Equal(day) {
+			continue
+		}
+		room, ok := rooms[c.Judge]
+		if !ok {
+			room = len(page.Benches)
+			rooms[c.Judge] = room
+			page.Benches = append(page.Benches, causeListBench{Room: fmt.Sprintf("%02d", room+1), Judge: c.Judge})
+		}
+		page.Benches[room].Cases = append(page.Benches[room].Cases, c)
+	}
+	s.mu.RUnlock()
+
+	s.render(w, causeListTemplate, page)
+}
+
+func (s *Server) render(w http.ResponseWriter, tmpl *template.Template, data interface{}) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := tmpl.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// newCaptcha issues a four digit code for one form submission
+func (s *Server) newCaptcha() (token, code string) {
+	buf := make([]byte, 10)
+	rand.Read(buf)
+	token = hex.EncodeToString(buf[:8])
+	code = fmt.Sprintf("%04d", (int(buf[8])<<8|int(buf[9]))%10000)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Forms that are never submitted leave their codes behind
+	if len(s.captchas) >= maxPendingCaptchas {
+		s.captchas = make(map[string]string)
+	}
+	s.captchas[token] = code
+	return token, code
+}
+
+func (s *Server) findCase(caseType, number, year string) (int, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for i, c := range s.cases {
+		if strings.EqualFold(c.Type, strings.TrimSpace(caseType)) &&
+			strings.TrimLeft(c.Number, "0") == strings.TrimLeft(strings.TrimSpace(number), "0") &&
+			c.Year == strings.TrimSpace(year) {
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
+
+func (s *Server) caseID(r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(r.URL.Query().Get("id"))
+	if err != nil || !s.validID(id) {
+		return 0, false
+	}
+	return id, true
+}
+
+func (s *Server) validID(id int) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return id >= 1 && id <= len(s.cases)
+}
+
+func (s *Server) caseByID(id int) Case {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cases[id-1]
+}
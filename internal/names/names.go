@@ -0,0 +1,254 @@
+// Package names canonicalises litigant and advocate names so the spellings
+// the court uses for one person across cases can be matched
+package names
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// MatchThreshold is the Similarity above which two names are taken to be
+// the same person
+const MatchThreshold = 0.88
+
+// minFuzzyWord is the length of the shortest word SamePerson lets two names
+// spell differently. One letter separates many distinct short given names,
+// such as "rajesh" and "ramesh" or "amit" and "amir".
+const minFuzzyWord = 7
+
+// honorifics are dropped from the start of a name, checked after
+// lowercasing and before punctuation is removed
+var honorific = regexp.MustCompile(`^(m/s\.?|messrs\.?|mr\.?|mrs\.?|ms\.?|miss|sh\.?|shri|sri|smt\.?|kum\.?|km\.?|kumari|dr\.?|adv\.?|advocate|the)(\s+|$)`)
+
+// trailers such as "& Ors." or "through its Secretary" don't identify the party
+var trailer = regexp.MustCompile(`\s+(&|and)?\s*(ors|others|anr|another|etc)\.?$|\s+(through|thr\.?|represented by|rep\. by)\s.*$`)
+
+var nonAlphanumeric = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+
+// wordAliases spell out abbreviations word by word
+var wordAliases = map[string]string{
+	"govt":  "government",
+	"gnct":  "government of nct",
+	"pvt":   "private",
+	"ltd":   "limited",
+	"corpn": "corporation",
+	"corp":  "corporation",
+	"deptt": "department",
+	"dept":  "department",
+	"mohd":  "mohammad",
+	"md":    "mohammad",
+}
+
+// nameAliases replace whole canonical names. In the Delhi courts "State" is
+// the prosecution, State (NCT of Delhi).
+var nameAliases = map[string]string{
+	"uoi":                              "union of india",
+	"union of india uoi":               "union of india",
+	"gnctd":                            "government of nct of delhi",
+	"state":                            "state nct of delhi",
+	"state of nct of delhi":            "state nct of delhi",
+	"state government of nct of delhi": "state nct of delhi",
+	"state delhi":                      "state nct of delhi",
+	"state of delhi":                   "state nct of delhi",
+}
+
+// Canonical lowercases name and strips honorifics, designations after a comma,
+// trailers like "& Ors." and punctuation, so "M/s. Sharma Pvt. Ltd. & Anr."
+// and "SHARMA PRIVATE LIMITED" compare equal. It returns "" for names with
+// nothing left to match on.
+func Canonical(name string) string {
+	name = strings.ToLower(strings.Join(strings.Fields(name), " "))
+
+	// "Ms. Kavita Rao, CGSC" - what follows a comma is a designation or address
+	if i := strings.Index(name, ","); i >= 0 {
+		name = name[:i]
+	}
+	for {
+		trimmed := honorific.ReplaceAllString(name, "")
+		trimmed = trailer.ReplaceAllString(trimmed, "")
+		if trimmed == name {
+			break
+		}
+		name = trimmed
+	}
+
+	words := joinInitials(strings.Fields(nonAlphanumeric.ReplaceAllString(name, " ")))
+	for i, word := range words {
+		if alias, ok := wordAliases[word]; ok {
+			words[i] = alias
+		}
+	}
+	name = strings.Join(words, " ")
+
+	if alias, ok := nameAliases[name]; ok {
+		name = alias
+	}
+	return name
+}
+
+// BlockKey groups canonical names that could be fuzzy matches, so only
+// names sharing a key need comparing. It is the sorted initials of the
+// words, which survive typos and reordering but not missing words.
+func BlockKey(canonical string) string {
+	words := strings.Fields(canonical)
+	initials := make([]rune, 0, len(words))
+	for _, word := range words {
+		initials = append(initials, []rune(word)[0])
+	}
+	sort.Slice(initials, func(i, j int) bool { return initials[i] < initials[j] })
+	return string(initials)
+}
+
+// Similarity compares two canonical names from 0 to 1 by edit distance over
+// their sorted words, so "kumar rajesh" and "rajesh kumar" are identical
+func Similarity(a, b string) float64 {
+	a, b = sortWords(a), sortWords(b)
+	if a == b {
+		return 1
+	}
+
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// SamePerson reports whether two canonical names are taken to be the same
+// person: their Similarity must reach MatchThreshold, and words shorter than
+// minFuzzyWord letters must match exactly
+func SamePerson(a, b string) bool {
+	if Similarity(a, b) < MatchThreshold {
+		return false
+	}
+
+	wordsA, wordsB := strings.Fields(sortWords(a)), strings.Fields(sortWords(b))
+	if len(wordsA) != len(wordsB) {
+		return true
+	}
+	for i := range wordsA {
+		if wordsA[i] == wordsB[i] {
+			continue
+		}
+		longest := len([]rune(wordsA[i]))
+		if n := len([]rune(wordsB[i])); n > longest {
+			longest = n
+		}
+		if longest < minFuzzyWord {
+			return false
+		}
+	}
+	return true
+}
+
+// AdvocateCode normalises a bar enrolment number such as "d / 1234 / 2010"
+// to "D/1234/2010"
+func AdvocateCode(code string) string {
+	code = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsSpace(r):
+			return -1
+		case r == '-' || r == '\\':
+			return '/'
+		}
+		return unicode.ToUpper(r)
+	}, code)
+	return strings.Trim(code, "/")
+}
+
+// joinInitials merges runs of single letters, so "A.K. Jain" and "AK Jain"
+// both become "ak jain" and "U.O.I." becomes "uoi"
+func joinInitials(words []string) []string {
+	joined := make([]string, 0, len(words))
+	run := ""
+	for _, word := range words {
+		if len([]rune(word)) == 1 {
+			run += word
+			continue
+		}
+		if run != "" {
+			joined = append(joined, run)
+			run = ""
+		}
+		joined = append(joined, word)
+	}
+	if run != "" {
+		joined = append(joined, run)
+	}
+	return joined
+}
+
+func sortWords(s string) string {
+	words := strings.Fields(s)
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
+
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// spellingVariants folds letters Indian names are commonly romanised with
+// interchangeably
+var spellingVariants = strings.NewReplacer("ph", "f", "x", "ks", "ck", "k", "q", "k", "z", "j")
+
+// Phonetic reduces a canonical name to a consonant skeleton that tolerates
+// the spelling differences of transliterated names: "choudhary", "chaudhari"
+// and "chowdhury" all become "cdr", "lakshmi" and "laxmi" "lksm". Each word
+// keeps its first letter, drops vowels and h, w and y, and collapses repeats.
+func Phonetic(canonical string) string {
+	words := strings.Fields(spellingVariants.Replace(canonical))
+	for i, word := range words {
+		runes := []rune(word)
+		if runes[0] == 'w' {
+			runes[0] = 'v'
+		}
+		skeleton := []rune{runes[0]}
+		for _, r := range runes[1:] {
+			if strings.ContainsRune("aeiouhwy", r) || r == skeleton[len(skeleton)-1] {
+				continue
+			}
+			skeleton = append(skeleton, r)
+		}
+		words[i] = string(skeleton)
+	}
+	return strings.Join(words, " ")
+}
+
+// phoneticWeight discounts a phonetic match, which also pairs some distinct
+// names such as "ram" and "rima"
+const phoneticWeight = 0.95
+
+// Match scores two canonical names from 0 to 1 by the better of their spelling
+// and phonetic similarity, for lookups that would rather over-report than
+// miss a transliterated spelling
+func Match(a, b string) float64 {
+	score := Similarity(a, b)
+	if phonetic := phoneticWeight * Similarity(Phonetic(a), Phonetic(b)); phonetic > score {
+		score = phonetic
+	}
+	return score
+}
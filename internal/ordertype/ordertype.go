@@ -0,0 +1,197 @@
+// Package ordertype labels court orders as adjournments, notices, interim
+// orders, judgments and so on from their description and PDF text
+package ordertype
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// Order types assigned by the default rules
+const (
+	Adjournment  = "adjournment"
+	InterimOrder = "interim_order"
+	NoticeIssued = "notice_issued"
+	Judgment     = "judgment"
+	Disposal     = "disposal"
+	Dismissal    = "dismissal"
+	BailGranted  = "bail_granted"
+	BailRejected = "bail_rejected"
+	// Other is assigned when no rule matches
+	Other = "other"
+)
+
+// pdfTextFactor scales the confidence of a match found only in the PDF
+// text, which is longer and quotes earlier orders
+const pdfTextFactor = 0.8
+
+// ErrInvalidRule is returned for a rule without a type or patterns, or with
+// a pattern that doesn't compile
+var ErrInvalidRule = errors.New("invalid order rule")
+
+// Rule assigns Type to orders matching any of Patterns, case-insensitive
+// regular expressions. Confidence is how often a match is right, 0.8 when
+// unset.
+type Rule struct {
+	Type       string   `json:"type"`
+	Patterns   []string `json:"patterns"`
+	Confidence float64  `json:"confidence,omitempty"`
+}
+
+// DefaultRules are used when no rules file is configured. The first rule
+// that matches wins, so bail outcomes come before plain dismissals and
+// final orders before the interim steps they often mention.
+var DefaultRules = []Rule{
+	{Type: BailRejected, Confidence: 0.9, Patterns: []string{
+		`\bbail\b[^.]*\b(rejected|dismissed|declined|refused|denied)\b`,
+		`\b(rejects?|dismiss(es)?|declines?|refuses?|denies)\b[^.]*\bbail\b`,
+	}},
+	{Type: BailGranted, Confidence: 0.9, Patterns: []string{
+		`\bbail\b[^.]*\b(granted|allowed|admitted)\b`,
+		`\b(grants?|allows?)\b[^.]*\bbail\b`,
+		`\breleased on (regular |interim |anticipatory )?bail\b`,
+		`\b(admitted|enlarged) (to|on) bail\b`,
+	}},
+	{Type: Judgment, Confidence: 0.85, Patterns: []string{
+		`\bjudg(e)?ment\b[^.]*\b(pronounced|delivered)\b`,
+		`\bfinal judg(e)?ment\b`,
+		`\baward (is )?(passed|pronounced|made)\b`,
+		`\b(appeal|petition|suit|revision)\b[^.]*\b(allowed|decreed)\b`,
+		`\b(convicted|acquitted)\b`,
+	}},
+	{Type: Dismissal, Confidence: 0.85, Patterns: []string{
+		`\bdismiss(ed|al)\b`,
+	}},
+	{Type: Disposal, Confidence: 0.8, Patterns: []string{
+		`\bdisposed (of|off)\b`,
+		`\bstands? disposed\b`,
+		`\b(withdrawn|settled|compromised)\b`,
+		`\bfile be consigned\b`,
+	}},
+	{Type: InterimOrder, Confidence: 0.75, Patterns: []string{
+		`\bad[- ]?interim\b`,
+		`\binterim (order|relief|stay|protection|injunction)\b`,
+		`\b(stay|injunction|status quo)\b[^.]*\b(granted|continue|operate|maintained)\b`,
+		`\bno coercive\b`,
+	}},
+	{Type: NoticeIssued, Confidence: 0.85, Patterns: []string{
+		`\b(notice|summons)\b[^.]*\bissued?\b`,
+		`\bissue (notice|summons)\b`,
+	}},
+	{Type: Adjournment, Confidence: 0.8, Patterns: []string{
+		`\badjourn`,
+		`\bnot taken up\b`,
+		`\brenotif`,
+		`\bstands? over\b`,
+		`\bpassed over\b`,
+	}},
+}
+
+// Result is the type assigned to an order
+type Result struct {
+	Type       string  `json:"type"`
+	Confidence float64 `json:"confidence"`
+}
+
+// Classifier applies rules in order
+type Classifier struct {
+	rules []compiledRule
+}
+
+type compiledRule struct {
+	Rule
+	patterns []*regexp.Regexp
+}
+
+var defaultClassifier = MustNew(DefaultRules)
+
+// Default returns the classifier for DefaultRules
+func Default() *Classifier {
+	return defaultClassifier
+}
+
+// New compiles rules into a classifier
+func New(rules []Rule) (*Classifier, error) {
+	classifier := &Classifier{rules: make([]compiledRule, len(rules))}
+	for i, rule := range rules {
+		if rule.Type == "" || len(rule.Patterns) == 0 {
+			return nil, fmt.Errorf("%w %d: type and patterns are required", ErrInvalidRule, i+1)
+		}
+		if rule.Confidence == 0 {
+			rule.Confidence = 0.8
+		}
+		if rule.Confidence < 0 || rule.Confidence > 1 {
+			return nil, fmt.Errorf("%w %q: confidence must be between 0 and 1", ErrInvalidRule, rule.Type)
+		}
+		compiled := compiledRule{Rule: rule}
+		for _, pattern := range rule.Patterns {
+			re, err := regexp.Compile(`(?i)` + pattern)
+			if err != nil {
+				return nil, fmt.Errorf("%w %q: %v", ErrInvalidRule, rule.Type, err)
+			}
+			compiled.patterns = append(compiled.patterns, re)
+		}
+		classifier.rules[i] = compiled
+	}
+	return classifier, nil
+}
+
+// MustNew is like New but panics on invalid rules
+func MustNew(rules []Rule) *Classifier {
+	classifier, err := New(rules)
+	if err != nil {
+		panic(err)
+	}
+	return classifier
+}
+
+// LoadFile reads rules from a JSON array in the Rule format. An empty path
+// gives the default classifier.
+func LoadFile(path string) (*Classifier, error) {
+	if path == "" {
+		return Default(), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read order rules: %w", err)
+	}
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse order rules %s: %w", path, err)
+	}
+	return New(rules)
+}
+
+// Classify labels an order from its description, falling back to the text
+// of its PDF, which may be empty. Orders no rule matches are Other with no
+// confidence.
+func (c *Classifier) Classify(description, pdfText string) Result {
+	for _, text := range []string{description, pdfText} {
+		if text == "" {
+			continue
+		}
+		for _, rule := range c.rules {
+			if !rule.matches(text) {
+				continue
+			}
+			confidence := rule.Confidence
+			if text != description {
+				confidence *= pdfTextFactor
+			}
+			return Result{Type: rule.Type, Confidence: confidence}
+		}
+	}
+	return Result{Type: Other}
+}
+
+func (r compiledRule) matches(text string) bool {
+	for _, re := range r.patterns {
+		if re.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
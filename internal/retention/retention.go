@@ -0,0 +1,257 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/JustJay7/court-data-fetcher/internal/config"
+	"github.com/JustJay7/court-data-fetcher/internal/database"
+	"github.com/JustJay7/court-data-fetcher/internal/scraper"
+	"github.com/JustJay7/court-data-fetcher/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// batchSize bounds how many query logs are loaded at once
+const batchSize = 200
+
+// Report counts the rows and files a purge removed or changed. For a dry run
+// it counts what would be removed or changed.
+type Report struct {
+	DryRun                 bool  `json:"dry_run"`
+	RawHTMLDropped         int64 `json:"raw_html_dropped"`
+	IPsAnonymised          int64 `json:"ips_anonymised"`
+	PDFsRemoved            int64 `json:"pdfs_removed"`
+	DebugArtifactsPurged   int64 `json:"debug_artifacts_purged"`
+	OrphanedParties        int64 `json:"orphaned_parties"`
+	OrphanedOrders         int64 `json:"orphaned_orders"`
+	OrphanedRawResponses   int64 `json:"orphaned_raw_responses"`
+	OrphanedDebugArtifacts int64 `json:"orphaned_debug_artifacts"`
+	ExpiredCacheEntries    int64 `json:"expired_cache_entries"`
+}
+
+// Purger applies the configured retention policies
+type Purger struct {
+	db     *gorm.DB
+	pdfs   *scraper.PDFDownloader
+	logger *logger.Logger
+	cfg    *config.Config
+}
+
+// NewPurger creates a purger for the retention settings in cfg
+func NewPurger(db *gorm.DB, logger *logger.Logger, cfg *config.Config) *Purger {
+	return &Purger{
+		db:     db,
+		pdfs:   scraper.NewPDFDownloader(db, logger, cfg.DatabasePath),
+		logger: logger,
+		cfg:    cfg,
+	}
+}
+
+// Run applies every policy once
+func (p *Purger) Run(dryRun bool) (*Report, error) {
+	report := &Report{DryRun: dryRun}
+
+	steps := []struct {
+		name string
+		run  func(*Report, bool) error
+	}{
+		{"raw html", p.purgeRawHTML},
+		{"ip addresses", p.anonymiseIPs},
+		{"pdfs", p.purgePDFs},
+		{"debug artifacts", p.purgeDebugArtifacts},
+		{"orphaned rows", p.deleteOrphans},
+		{"cache entries", p.deleteExpiredCacheEntries},
+	}
+	for _, step := range steps {
+		if err := step.run(report, dryRun); err != nil {
+			return report, fmt.Errorf("failed to purge %s: %w", step.name, err)
+		}
+	}
+
+	return report, nil
+}
+
+// Start runs the purge every interval until ctx is cancelled
+func (p *Purger) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := p.Run(false)
+			if err != nil {
+				p.logger.Error("Retention purge failed", "error", err)
+				continue
+			}
+			p.logger.Info("Retention purge completed",
+				"raw_html_dropped", report.RawHTMLDropped,
+				"ips_anonymised", report.IPsAnonymised,
+				"pdfs_removed", report.PDFsRemoved,
+				"debug_artifacts_purged", report.DebugArtifactsPurged,
+				"orphaned_parties", report.OrphanedParties,
+				"orphaned_orders", report.OrphanedOrders,
+				"orphaned_raw_responses", report.OrphanedRawResponses,
+				"orphaned_debug_artifacts", report.OrphanedDebugArtifacts,
+				"expired_cache_entries", report.ExpiredCacheEntries,
+			)
+		}
+	}
+}
+
+// purgeRawHTML unlinks raw pages older than RawHTMLRetention. Unreferenced
+// pages are removed with the orphans.
+func (p *Purger) purgeRawHTML(report *Report, dryRun bool) error {
+	if p.cfg.RawHTMLRetention <= 0 {
+		return nil
+	}
+	cutoff := time.Now().Add(-p.cfg.RawHTMLRetention)
+
+	query := p.db.Model(&database.QueryLog{}).
+		Where("created_at < ? AND raw_response_hash != ?", cutoff, "")
+	if dryRun {
+		return query.Count(&report.RawHTMLDropped).Error
+	}
+
+	result := query.Update("raw_response_hash", "")
+	report.RawHTMLDropped = result.RowsAffected
+	return result.Error
+}
+
+// anonymiseIPs truncates client addresses older than IPRetention
+func (p *Purger) anonymiseIPs(report *Report, dryRun bool) error {
+	if p.cfg.IPRetention <= 0 {
+		return nil
+	}
+	cutoff := time.Now().Add(-p.cfg.IPRetention)
+
+	var logs []database.QueryLog
+	result := p.db.Model(&database.QueryLog{}).
+		Select("id", "ip_address").
+		Where("created_at < ? AND ip_address != ?", cutoff, "").
+		FindInBatches(&logs, batchSize, func(tx *gorm.DB, batch int) error {
+			for _, queryLog := range logs {
+				anonymised := AnonymiseIP(queryLog.IPAddress)
+				if anonymised == queryLog.IPAddress {
+					continue
+				}
+				report.IPsAnonymised++
+				if dryRun {
+					continue
+				}
+				if err := p.db.Model(&database.QueryLog{}).Where("id = ?", queryLog.ID).
+					Update("ip_address", anonymised).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	return result.Error
+}
+
+// purgePDFs deletes downloaded PDFs older than PDFRetention
+func (p *Purger) purgePDFs(report *Report, dryRun bool) error {
+	days := int(p.cfg.PDFRetention / (24 * time.Hour))
+	if days <= 0 {
+		return nil
+	}
+
+	removed, err := p.pdfs.CleanupOldPDFs(days, dryRun)
+	report.PDFsRemoved = int64(removed)
+	return err
+}
+
+// purgeDebugArtifacts deletes debug artifacts older than DebugArtifactRetention
+func (p *Purger) purgeDebugArtifacts(report *Report, dryRun bool) error {
+	if p.cfg.DebugArtifactRetention <= 0 {
+		return nil
+	}
+	cutoff := time.Now().Add(-p.cfg.DebugArtifactRetention)
+
+	query := p.db.Model(&database.DebugArtifact{}).Where("created_at < ?", cutoff)
+	if dryRun {
+		return query.Count(&report.DebugArtifactsPurged).Error
+	}
+
+	result := query.Delete(&database.DebugArtifact{})
+	report.DebugArtifactsPurged = result.RowsAffected
+	return result.Error
+}
+
+// deleteOrphans removes parties and orders whose case no longer exists, and
+// raw pages and debug artifacts no query log refers to
+func (p *Purger) deleteOrphans(report *Report, dryRun bool) error {
+	cases := p.db.Model(&database.CaseInfo{}).Select("id")
+	queryLogs := p.db.Unscoped().Model(&database.QueryLog{}).Select("id")
+	hashes := p.db.Unscoped().Model(&database.QueryLog{}).Select("raw_response_hash").Where("raw_response_hash != ?", "")
+
+	parties := p.db.Unscoped().Model(&database.Party{}).Where("case_info_id NOT IN (?)", cases)
+	orders := p.db.Unscoped().Model(&database.Order{}).Where("case_info_id NOT IN (?)", cases)
+	rawResponses := p.db.Model(&database.RawResponseBlob{}).Where("hash NOT IN (?)", hashes)
+	artifacts := p.db.Model(&database.DebugArtifact{}).Where("query_log_id NOT IN (?)", queryLogs)
+
+	if dryRun {
+		if err := parties.Count(&report.OrphanedParties).Error; err != nil {
+			return err
+		}
+		if err := orders.Count(&report.OrphanedOrders).Error; err != nil {
+			return err
+		}
+		if err := rawResponses.Count(&report.OrphanedRawResponses).Error; err != nil {
+			return err
+		}
+		return artifacts.Count(&report.OrphanedDebugArtifacts).Error
+	}
+
+	result := parties.Delete(&database.Party{})
+	if result.Error != nil {
+		return result.Error
+	}
+	report.OrphanedParties = result.RowsAffected
+
+	result = orders.Delete(&database.Order{})
+	if result.Error != nil {
+		return result.Error
+	}
+	report.OrphanedOrders = result.RowsAffected
+
+	result = rawResponses.Delete(&database.RawResponseBlob{})
+	if result.Error != nil {
+		return result.Error
+	}
+	report.OrphanedRawResponses = result.RowsAffected
+
+	result = artifacts.Delete(&database.DebugArtifact{})
+	report.OrphanedDebugArtifacts = result.RowsAffected
+	return result.Error
+}
+
+// deleteExpiredCacheEntries clears entries the persistent cache no longer serves
+func (p *Purger) deleteExpiredCacheEntries(report *Report, dryRun bool) error {
+	query := p.db.Model(&database.CacheEntry{}).Where("expires_at <= ?", time.Now())
+	if dryRun {
+		return query.Count(&report.ExpiredCacheEntries).Error
+	}
+
+	result := query.Delete(&database.CacheEntry{})
+	report.ExpiredCacheEntries = result.RowsAffected
+	return result.Error
+}
+
+// AnonymiseIP keeps only the network part of an address: the first three
+// octets of IPv4 and the first 48 bits of IPv6. Unparseable values are
+// dropped entirely.
+func AnonymiseIP(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ""
+	}
+	if v4 := parsed.To4(); v4 != nil {
+		return v4.Mask(net.CIDRMask(24, 32)).String()
+	}
+	return parsed.Mask(net.CIDRMask(48, 128)).String()
+}
@@ -14,6 +14,7 @@ import (
 
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/proto"
+	"github.com/JustJay7/court-data-fetcher/internal/upstream"
 )
 
 type CaptchaHandler struct {
@@ -94,7 +95,9 @@ func (s *Scraper) handleCaptcha(page *rod.Page) error {
 		return fmt.Errorf("CAPTCHA input field not found")
 	}
 
-	captchaInput.MustInput(captchaText)
+	if err := captchaInput.Input(captchaText); err != nil {
+		return fmt.Errorf("failed to enter CAPTCHA: %w", err)
+	}
 	s.logger.Debug("CAPTCHA text entered", "length", len(captchaText))
 
 	return nil
@@ -116,13 +119,19 @@ func (s *Scraper) getCaptchaImage(page *rod.Page, captchaImg *rod.Element) ([]by
 		if strings.HasPrefix(*src, "http") || strings.HasPrefix(*src, "/") {
 			imgURL := *src
 			if strings.HasPrefix(imgURL, "/") {
-				pageURL := page.MustInfo().URL
-				base := strings.Split(pageURL, "/")[:3]
+				info, err := page.Info()
+				if err != nil {
+					return nil, fmt.Errorf("failed to read page URL: %w", err)
+				}
+				base := strings.Split(info.URL, "/")[:3]
 				imgURL = strings.Join(base, "/") + imgURL
 			}
 
-			cookies := page.MustCookies()
-			return s.fetchImageWithCookies(imgURL, cookies)
+			cookies, err := page.Cookies(nil)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read cookies: %w", err)
+			}
+			return s.fetchImageWithCookies(imgURL, cookies, s.pageProxy(page))
 		}
 	}
 
@@ -134,8 +143,10 @@ func (s *Scraper) getCaptchaImage(page *rod.Page, captchaImg *rod.Element) ([]by
 	return screenshot, nil
 }
 
-func (s *Scraper) fetchImageWithCookies(imgURL string, cookies []*proto.NetworkCookie) ([]byte, error) {
-	client := &http.Client{Timeout: 10 * time.Second}
+// fetchImageWithCookies downloads an image with the page's cookies through
+// the page's proxy, so the court sees the same session
+func (s *Scraper) fetchImageWithCookies(imgURL string, cookies []*proto.NetworkCookie, proxy *upstream.Proxy) ([]byte, error) {
+	client := s.upstream.ClientVia(proxy, 10*time.Second)
 	req, err := http.NewRequest("GET", imgURL, nil)
 	if err != nil {
 		return nil, err
@@ -357,7 +368,9 @@ func (s *Scraper) retryCaptcha(page *rod.Page, maxRetries int) error {
 
 		refreshBtn, err := page.Element("img[onclick*='captcha'], a[onclick*='captcha'], button[onclick*='captcha']")
 		if err == nil && refreshBtn != nil {
-			refreshBtn.MustClick()
+			if err := refreshBtn.Click(proto.InputMouseButtonLeft, 1); err != nil {
+				s.logger.Warn("Failed to refresh CAPTCHA", "error", err)
+			}
 			time.Sleep(1 * time.Second)
 		}
 
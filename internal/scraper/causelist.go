@@ -0,0 +1,87 @@
+package scraper
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/JustJay7/court-data-fetcher/internal/caseid"
+	"github.com/JustJay7/court-data-fetcher/internal/database"
+	"github.com/PuerkitoBio/goquery"
+)
+
+var (
+	courtRoomPattern = regexp.MustCompile(`(?i)\bCOURT\s*(?:NO|ROOM)\.?\s*[:\-]?\s*([0-9A-Z\-]+)`)
+	itemPattern      = regexp.MustCompile(`^(\d+[A-Z]?(?:\.\d+)?)\.?$`)
+	versusPattern    = regexp.MustCompile(`(?i)\s(?:vs?\.?|versus)\s`)
+)
+
+// ParseCauseListHTML reads the items of a published cause list. Heading
+// rows name the court room and the judges sitting in it, and apply to the
+// items below them. A day without a list gives no entries and no error.
+func (p *Parser) ParseCauseListHTML(pageHTML string) ([]database.CauseListEntry, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(pageHTML))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse page: %w", err)
+	}
+
+	var (
+		entries  []database.CauseListEntry
+		room     string
+		judges   []string
+		newBench bool
+	)
+	doc.Find("h2, h3, h4, tr").Each(func(_ int, sel *goquery.Selection) {
+		cells := sel.Find("td")
+		if goquery.NodeName(sel) != "tr" || cells.Length() < 2 {
+			heading := innerText(sel)
+			switch {
+			case courtRoomPattern.MatchString(heading):
+				room = courtRoomPattern.FindStringSubmatch(heading)[1]
+				judges, newBench = nil, false
+			case isBenchHeading(heading):
+				// Judges listed after a block of items form a new bench
+				if newBench {
+					judges, newBench = nil, false
+				}
+				judges = append(judges, heading)
+			}
+			return
+		}
+
+		item := itemPattern.FindStringSubmatch(innerText(cells.Eq(0)))
+		if item == nil {
+			return
+		}
+		entry := database.CauseListEntry{CourtRoom: room, Bench: strings.Join(judges, ", "), ItemNumber: item[1]}
+		cells.Slice(1, cells.Length()).Each(func(_ int, cell *goquery.Selection) {
+			text := innerText(cell)
+			switch {
+			case entry.CaseNumber == "":
+				if id, err := caseid.Parse(text); err == nil {
+					entry.CaseNumber = id.String()
+				} else if id, ok := caseid.Find(strings.ToUpper(text)); ok {
+					entry.CaseNumber = id.String()
+				}
+			case entry.Parties == "" && versusPattern.MatchString(" "+text+" "):
+				entry.Parties = text
+			case entry.Advocate == "":
+				entry.Advocate = text
+			}
+		})
+		if entry.CaseNumber == "" {
+			return
+		}
+		newBench = true
+		entries = append(entries, entry)
+	})
+
+	return entries, nil
+}
+
+// isBenchHeading reports whether a heading names a judge or registrar
+func isBenchHeading(text string) bool {
+	upper := strings.ToUpper(text)
+	return strings.Contains(upper, "JUSTICE") || strings.Contains(upper, "HON'BLE") ||
+		strings.Contains(upper, "REGISTRAR") || strings.Contains(upper, "JUDGE")
+}
@@ -0,0 +1,223 @@
+package scraper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// DebugBundle is what the browser showed when a scrape failed
+type DebugBundle struct {
+	Screenshot []byte // full-page PNG
+	HAR        []byte // network log in HAR 1.2 format
+	Console    string // console messages and uncaught exceptions, one per line
+	DOM        string
+}
+
+// debugError carries a DebugBundle alongside a scrape error
+type debugError struct {
+	err    error
+	bundle *DebugBundle
+}
+
+func (e *debugError) Error() string { return e.err.Error() }
+func (e *debugError) Unwrap() error { return e.err }
+
+// WithDebugBundle attaches a bundle to err without changing its message or code
+func WithDebugBundle(err error, bundle *DebugBundle) error {
+	if err == nil || bundle == nil {
+		return err
+	}
+	return &debugError{err: err, bundle: bundle}
+}
+
+// DebugBundleOf returns the bundle captured for a failed scrape, if any
+func DebugBundleOf(err error) *DebugBundle {
+	var de *debugError
+	if errors.As(err, &de) {
+		return de.bundle
+	}
+	return nil
+}
+
+// shouldCapture reports whether a failure is worth a debug bundle. Bad input
+// and cases the court doesn't have are expected outcomes, not breakage.
+func shouldCapture(err error) bool {
+	return err != nil && !errors.Is(err, ErrInvalidInput) && !errors.Is(err, ErrCaseNotFound)
+}
+
+// sessionRecorder collects network and console events for one page
+type sessionRecorder struct {
+	mu       sync.Mutex
+	started  time.Time
+	requests map[proto.NetworkRequestID]*harEntry
+	order    []proto.NetworkRequestID
+	console  []string
+	stop     func()
+}
+
+// recordSession starts collecting events from page until stop is called
+func recordSession(page *rod.Page) *sessionRecorder {
+	r := &sessionRecorder{
+		started:  time.Now(),
+		requests: make(map[proto.NetworkRequestID]*harEntry),
+	}
+
+	p, cancel := page.WithCancel()
+	r.stop = cancel
+
+	wait := p.EachEvent(
+		func(e *proto.NetworkRequestWillBeSent) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			if _, ok := r.requests[e.RequestID]; !ok {
+				r.order = append(r.order, e.RequestID)
+			}
+			r.requests[e.RequestID] = &harEntry{
+				StartedDateTime: time.Now().Format(time.RFC3339Nano),
+				Request: harRequest{
+					Method:  e.Request.Method,
+					URL:     e.Request.URL,
+					Headers: harHeaders(e.Request.Headers),
+				},
+				started: time.Now(),
+			}
+		},
+		func(e *proto.NetworkResponseReceived) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			if entry, ok := r.requests[e.RequestID]; ok {
+				entry.Response = harResponse{
+					Status:     e.Response.Status,
+					StatusText: e.Response.StatusText,
+					Headers:    harHeaders(e.Response.Headers),
+					Content:    harContent{MimeType: e.Response.MIMEType},
+				}
+				entry.Time = float64(time.Since(entry.started).Milliseconds())
+			}
+		},
+		func(e *proto.NetworkLoadingFailed) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			if entry, ok := r.requests[e.RequestID]; ok {
+				entry.Response.StatusText = e.ErrorText
+				entry.Time = float64(time.Since(entry.started).Milliseconds())
+			}
+		},
+		func(e *proto.RuntimeConsoleAPICalled) {
+			args := make([]string, 0, len(e.Args))
+			for _, arg := range e.Args {
+				if !arg.Value.Nil() {
+					args = append(args, arg.Value.String())
+				} else {
+					args = append(args, arg.Description)
+				}
+			}
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.console = append(r.console, fmt.Sprintf("[%s] %s", e.Type, strings.Join(args, " ")))
+		},
+		func(e *proto.RuntimeExceptionThrown) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.console = append(r.console, fmt.Sprintf("[exception] %s", e.ExceptionDetails.Text))
+		},
+	)
+	go wait()
+
+	return r
+}
+
+// capture stops recording and snapshots the page
+func (r *sessionRecorder) capture(page *rod.Page) *DebugBundle {
+	r.stop()
+
+	bundle := &DebugBundle{}
+	if screenshot, err := page.Screenshot(true, &proto.PageCaptureScreenshot{
+		Format: proto.PageCaptureScreenshotFormatPng,
+	}); err == nil {
+		bundle.Screenshot = screenshot
+	}
+	if html, err := page.HTML(); err == nil {
+		bundle.DOM = html
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bundle.Console = strings.Join(r.console, "\n")
+
+	har := harFile{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "court-data-fetcher", Version: "1.0"},
+		Entries: make([]harEntry, 0, len(r.order)),
+	}}
+	for _, id := range r.order {
+		har.Log.Entries = append(har.Log.Entries, *r.requests[id])
+	}
+	if data, err := json.MarshalIndent(har, "", "  "); err == nil {
+		bundle.HAR = data
+	}
+
+	return bundle
+}
+
+// HAR 1.2 structures, limited to the fields the recorder fills in
+type harFile struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	started         time.Time
+}
+
+type harRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers []harHeader `json:"headers"`
+}
+
+type harResponse struct {
+	Status     int         `json:"status"`
+	StatusText string      `json:"statusText"`
+	Headers    []harHeader `json:"headers"`
+	Content    harContent  `json:"content"`
+}
+
+type harContent struct {
+	MimeType string `json:"mimeType"`
+}
+
+type harHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func harHeaders(headers proto.NetworkHeaders) []harHeader {
+	result := make([]harHeader, 0, len(headers))
+	for name, value := range headers {
+		result = append(result, harHeader{Name: name, Value: value.String()})
+	}
+	return result
+}
@@ -0,0 +1,80 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors describing why a scrape failed. Callers should match
+// them with errors.Is rather than inspecting error strings.
+var (
+	ErrCaseNotFound     = errors.New("case not found")
+	ErrCourtUnavailable = errors.New("court website unavailable")
+	ErrCaptchaFailed    = errors.New("captcha verification failed")
+	ErrLayoutChanged    = errors.New("court page layout changed")
+	ErrTimeout          = errors.New("scrape timed out")
+	ErrInvalidInput     = errors.New("invalid search input")
+)
+
+// Machine-readable error codes returned to API clients and stored in QueryLog
+const (
+	CodeCaseNotFound     = "case_not_found"
+	CodeCourtUnavailable = "court_unavailable"
+	CodeCaptchaFailed    = "captcha_failed"
+	CodeLayoutChanged    = "layout_changed"
+	CodeTimeout          = "timeout"
+	CodeInvalidInput     = "invalid_input"
+	CodeInternal         = "internal_error"
+)
+
+// ScrapeError records the step that failed, the category of the failure
+// and the underlying cause
+type ScrapeError struct {
+	Kind error  // One of the Err* sentinels
+	Op   string // Scraping step, e.g. "navigate" or "select case type"
+	Err  error  // Underlying cause, may be nil
+}
+
+func (e *ScrapeError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v: %v", e.Op, e.Kind, e.Err)
+	}
+	return fmt.Sprintf("%s: %v", e.Op, e.Kind)
+}
+
+// Unwrap exposes both the category and the cause to errors.Is and errors.As
+func (e *ScrapeError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// newScrapeError builds a ScrapeError, promoting context deadline errors
+// to ErrTimeout regardless of the requested kind
+func newScrapeError(kind error, op string, err error) *ScrapeError {
+	if errors.Is(err, context.DeadlineExceeded) {
+		kind = ErrTimeout
+	}
+	return &ScrapeError{Kind: kind, Op: op, Err: err}
+}
+
+// ErrorCode maps an error to its machine-readable code
+func ErrorCode(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrCaseNotFound):
+		return CodeCaseNotFound
+	case errors.Is(err, ErrInvalidInput):
+		return CodeInvalidInput
+	case errors.Is(err, ErrCaptchaFailed):
+		return CodeCaptchaFailed
+	case errors.Is(err, ErrLayoutChanged):
+		return CodeLayoutChanged
+	case errors.Is(err, ErrTimeout), errors.Is(err, context.DeadlineExceeded):
+		return CodeTimeout
+	case errors.Is(err, ErrCourtUnavailable):
+		return CodeCourtUnavailable
+	default:
+		return CodeInternal
+	}
+}
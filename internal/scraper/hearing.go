@@ -0,0 +1,94 @@
+package scraper
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/JustJay7/court-data-fetcher/internal/database"
+)
+
+// directionDate matches the dates orders list matters on: 12.11.2024,
+// 5-2-24, 3rd March, 2025 or Nov. 12, 2024
+const directionDate = `\d{1,2}[./-]\d{1,2}[./-](?:\d{4}|\d{2})\b` +
+	`|\d{1,2}(?:st|nd|rd|th)?\s+[A-Za-z]{3,9}\.?,?\s+\d{4}` +
+	`|[A-Za-z]{3,9}\.?\s+\d{1,2}(?:st|nd|rd|th)?,?\s+\d{4}`
+
+// listingDirectionPattern finds directions such as "List on 12.11.2024",
+// "renotify on 3rd March, 2025" or "adjourned to 05-02-2024", allowing a few
+// words between the verb and the date
+var listingDirectionPattern = regexp.MustCompile(`(?i)\b(?:re-?list(?:ed)?|list(?:ed)?|re-?notif(?:y|ied)|adjourned|put up|fixed|posted|stands? over)\b` +
+	`[^.;\n]{0,50}?\b(?:on|to|for|till)\s+(` + directionDate + `)`)
+
+// HearingDirection is a listing direction read from an order
+type HearingDirection struct {
+	Date time.Time
+	// Phrase is the direction as the order words it, e.g. "List on 12.11.2024"
+	Phrase string
+}
+
+// FindHearingDirection returns the last listing direction in an order's
+// text whose date can be read
+func (p *Parser) FindHearingDirection(text string) (HearingDirection, bool) {
+	matches := listingDirectionPattern.FindAllStringSubmatch(text, -1)
+	for i := len(matches) - 1; i >= 0; i-- {
+		date, err := p.ParseDate(matches[i][1])
+		if err != nil {
+			continue
+		}
+		return HearingDirection{Date: date, Phrase: strings.Join(strings.Fields(matches[i][0]), " ")}, true
+	}
+	return HearingDirection{}, false
+}
+
+// InferNextHearing fills in caseInfo.NextHearing from the listing direction
+// of its latest orders when the details page showed no date, or one no later
+// than the latest order. It reports whether the date changed.
+func (p *Parser) InferNextHearing(caseInfo *database.CaseInfo) bool {
+	latest := latestOrderDate(caseInfo.Orders)
+	var (
+		found    bool
+		best     HearingDirection
+		bestDate time.Time
+	)
+	for _, order := range caseInfo.Orders {
+		if order.OrderDate.Before(bestDate) {
+			continue
+		}
+		direction, ok := p.FindHearingDirection(order.Description)
+		if !ok || !direction.Date.After(latest) {
+			continue
+		}
+		found, best, bestDate = true, direction, order.OrderDate
+	}
+	if !found {
+		return false
+	}
+	return applyHearingDirection(caseInfo, latest, best, database.NextHearingFromOrder)
+}
+
+// applyHearingDirection sets the next hearing from a direction unless the
+// details page already gave a date after the latest order
+func applyHearingDirection(caseInfo *database.CaseInfo, latestOrder time.Time, direction HearingDirection, source string) bool {
+	if caseInfo.NextHearing.After(latestOrder) || caseInfo.NextHearing.Equal(direction.Date) {
+		return false
+	}
+	caseInfo.NextHearing = direction.Date
+	caseInfo.NextHearingSource = source
+	caseInfo.NextHearingNote = direction.Phrase
+	if report := caseInfo.ParseReport; report != nil {
+		report.Fields["next_hearing"] = StrategyOrders
+		scoreParseReport(report)
+	}
+	return true
+}
+
+func latestOrderDate(orders []database.Order) time.Time {
+	var latest time.Time
+	for _, order := range orders {
+		if order.OrderDate.After(latest) {
+			latest = order.OrderDate
+		}
+	}
+	return latest
+}
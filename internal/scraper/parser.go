@@ -6,46 +6,102 @@ import (
 	"strings"
 	"time"
 
-	"github.com/go-rod/rod"
+	"github.com/JustJay7/court-data-fetcher/internal/caseid"
+	"github.com/JustJay7/court-data-fetcher/internal/courtdate"
 	"github.com/JustJay7/court-data-fetcher/internal/database"
+	"github.com/JustJay7/court-data-fetcher/internal/ordertype"
 	"github.com/JustJay7/court-data-fetcher/pkg/logger"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/go-rod/rod"
+	"golang.org/x/net/html"
 )
 
-// Parser handles HTML parsing operations for Delhi District Courts
+// detailsContainerSelector matches the element holding a case's details
+const detailsContainerSelector = "div.container, div#case_details, div.case-info"
+
+// Parser handles HTML parsing operations for Delhi District Courts. It works
+// on the page's HTML rather than the live browser, so saved pages parse the
+// same way as fresh ones.
 type Parser struct {
-	logger *logger.Logger
+	logger     *logger.Logger
+	classifier *ordertype.Classifier
 }
 
-// NewParser creates a new parser instance
+// NewParser creates a new parser instance that labels orders with the
+// default rules
 func NewParser(logger *logger.Logger) *Parser {
-	return &Parser{logger: logger}
+	return &Parser{logger: logger, classifier: ordertype.Default()}
+}
+
+// SetClassifier replaces the rules orders are labelled with
+func (p *Parser) SetClassifier(classifier *ordertype.Classifier) {
+	p.classifier = classifier
 }
 
 // ParseCaseDetails parses case information from Delhi District Court results
 func (p *Parser) ParseCaseDetails(page *rod.Page) (*database.CaseInfo, error) {
+	// Wait for the details to render before reading the page
+	if _, err := page.Element(detailsContainerSelector); err != nil {
+		return nil, fmt.Errorf("case details container not found")
+	}
+	pageHTML, err := page.HTML()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page: %w", err)
+	}
+	return p.ParseCaseDetailsHTML(pageHTML)
+}
+
+// ParseCaseDetailsHTML parses case information from a results page's HTML
+func (p *Parser) ParseCaseDetailsHTML(pageHTML string) (*database.CaseInfo, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(pageHTML))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse page: %w", err)
+	}
+
 	caseInfo := &database.CaseInfo{}
+	report := &database.ParseReport{Fields: make(map[string]string)}
+	caseInfo.ParseReport = report
 
 	// Delhi District Courts typically shows case details in a specific format
 	// Look for the case details container
-	detailsContainer, err := page.Element("div.container, div#case_details, div.case-info")
-	if err != nil {
+	detailsContainer := doc.Find(detailsContainerSelector).First()
+	if detailsContainer.Length() == 0 {
 		return nil, fmt.Errorf("case details container not found")
 	}
 
 	// Method 1: Try to parse from table format (common in e-Courts)
-	if table, err := detailsContainer.Element("table"); err == nil {
-		p.parseCaseDetailsFromTable(table, caseInfo)
+	if table := detailsContainer.Find("table").First(); table.Length() > 0 {
+		trackStrategy(report, caseInfo, StrategyTable, func() {
+			p.parseCaseDetailsFromTable(table, caseInfo)
+		})
 	}
 
 	// Method 2: Try to parse from div/span structure
-	if caseInfo.CaseNumber == "" {
-		p.parseCaseDetailsFromDivs(detailsContainer, caseInfo)
+	if caseInfo.CaseNumber == "" && caseInfo.CNR == "" {
+		trackStrategy(report, caseInfo, StrategyDivs, func() {
+			p.parseCaseDetailsFromDivs(detailsContainer, caseInfo)
+		})
 	}
 
 	// Method 3: Parse from text patterns if structured parsing fails
-	if caseInfo.CaseNumber == "" {
-		bodyText, _ := detailsContainer.Text()
-		p.parseCaseDetailsFromText(bodyText, caseInfo)
+	if caseInfo.CaseNumber == "" && caseInfo.CNR == "" {
+		trackStrategy(report, caseInfo, StrategyText, func() {
+			p.parseCaseDetailsFromText(innerText(detailsContainer), caseInfo)
+		})
+	}
+
+	// Fields filled in from the case number or CNR are credited to the
+	// strategy that read it
+	source := report.Fields["case_number"]
+	if source == "" {
+		source = report.Fields["cnr"]
+	}
+	if source != "" {
+		trackStrategy(report, caseInfo, source, func() {
+			normaliseCaseIdentity(caseInfo)
+		})
+	} else {
+		normaliseCaseIdentity(caseInfo)
 	}
 
 	// Validate we got at least the case number
@@ -54,285 +110,316 @@ func (p *Parser) ParseCaseDetails(page *rod.Page) (*database.CaseInfo, error) {
 	}
 
 	// Parse parties information
-	parties, err := p.parseParties(page)
+	parties, strategy, err := p.parseParties(doc)
 	if err != nil {
 		p.logger.Warn("Failed to parse parties", "error", err)
 	} else {
 		caseInfo.Parties = parties
+		if len(parties) > 0 {
+			report.Fields["parties"] = strategy
+		}
 	}
 
 	// Parse case status history if available
-	p.parseCaseHistory(page, caseInfo)
+	trackStrategy(report, caseInfo, StrategyHistory, func() {
+		p.parseCaseHistory(doc, caseInfo)
+	})
+
+	if !caseInfo.NextHearing.IsZero() {
+		caseInfo.NextHearingSource = database.NextHearingFromPage
+	}
+
+	scoreParseReport(report)
 
 	return caseInfo, nil
 }
 
+// normaliseCaseIdentity rewrites the case number and CNR in canonical form,
+// filling in the case type and year from the number where the page left
+// them out. A CNR stands in for a case number the page doesn't show.
+func normaliseCaseIdentity(caseInfo *database.CaseInfo) {
+	if caseInfo.CNR != "" {
+		if cnr, err := caseid.ParseCNR(caseInfo.CNR); err == nil {
+			caseInfo.CNR = cnr.String()
+		} else {
+			caseInfo.CNR = ""
+		}
+	}
+
+	if id, err := caseid.Parse(caseInfo.CaseNumber); err == nil {
+		caseInfo.CaseNumber = id.String()
+		if caseInfo.CaseType == "" {
+			caseInfo.CaseType = id.Type
+		}
+		if caseInfo.FilingYear == "" {
+			caseInfo.FilingYear = id.Year
+		}
+	} else if cnr, err := caseid.ParseCNR(caseInfo.CaseNumber); err == nil {
+		caseInfo.CNR = cnr.String()
+	}
+
+	if caseInfo.CaseNumber == "" {
+		caseInfo.CaseNumber = caseInfo.CNR
+	}
+}
+
 // parseCaseDetailsFromTable extracts case info from table format
-func (p *Parser) parseCaseDetailsFromTable(table *rod.Element, caseInfo *database.CaseInfo) {
-	rows := table.MustElements("tr")
-	
-	for _, row := range rows {
-		cells := row.MustElements("td, th")
-		if len(cells) >= 2 {
-			label := strings.ToLower(strings.TrimSpace(cells[0].MustText()))
-			value := strings.TrimSpace(cells[1].MustText())
-			
-			// Map common e-Courts labels to our fields
-			switch {
-			case strings.Contains(label, "case number") || strings.Contains(label, "cnr"):
-				caseInfo.CaseNumber = value
-			case strings.Contains(label, "case type"):
-				caseInfo.CaseType = value
-			case strings.Contains(label, "filing number"):
-				// Extract year from filing number if not set
-				if matches := regexp.MustCompile(`\d{4}`).FindString(value); matches != "" {
-					caseInfo.FilingYear = matches
-				}
-			case strings.Contains(label, "filing date") || strings.Contains(label, "date of filing"):
-				caseInfo.FilingDate, _ = p.parseDate(value)
-			case strings.Contains(label, "registration date"):
-				if caseInfo.FilingDate.IsZero() {
-					caseInfo.FilingDate, _ = p.parseDate(value)
-				}
-			case strings.Contains(label, "next date") || strings.Contains(label, "next hearing"):
-				caseInfo.NextHearing, _ = p.parseDate(value)
-			case strings.Contains(label, "stage") || strings.Contains(label, "status"):
-				caseInfo.Status = value
-			case strings.Contains(label, "judge") || strings.Contains(label, "coram"):
-				caseInfo.Judge = value
-			case strings.Contains(label, "court") && !strings.Contains(label, "court number"):
-				caseInfo.CourtComplex = value
+func (p *Parser) parseCaseDetailsFromTable(table *goquery.Selection, caseInfo *database.CaseInfo) {
+	table.Find("tr").Each(func(_ int, row *goquery.Selection) {
+		cells := row.Find("td, th")
+		if cells.Length() < 2 {
+			return
+		}
+		label := strings.ToLower(innerText(cells.Eq(0)))
+		value := innerText(cells.Eq(1))
+
+		// Map common e-Courts labels to our fields
+		switch {
+		case strings.Contains(label, "cnr"):
+			caseInfo.CNR = value
+		case strings.Contains(label, "case number"):
+			caseInfo.CaseNumber = value
+		case strings.Contains(label, "case type"):
+			caseInfo.CaseType = value
+		case strings.Contains(label, "filing number"):
+			// Extract year from filing number if not set
+			if matches := regexp.MustCompile(`\d{4}`).FindString(value); matches != "" {
+				caseInfo.FilingYear = matches
+			}
+		case strings.Contains(label, "filing date") || strings.Contains(label, "date of filing"):
+			caseInfo.FilingDate = p.readDate(caseInfo.ParseReport, "filing_date", value)
+		case strings.Contains(label, "registration date"):
+			if caseInfo.FilingDate.IsZero() {
+				caseInfo.FilingDate = p.readDate(caseInfo.ParseReport, "filing_date", value)
 			}
+		case strings.Contains(label, "next date") || strings.Contains(label, "next hearing"):
+			caseInfo.NextHearing = p.readDate(caseInfo.ParseReport, "next_hearing", value)
+		case strings.Contains(label, "stage") || strings.Contains(label, "status"):
+			caseInfo.Status = value
+		case strings.Contains(label, "judge") || strings.Contains(label, "coram"):
+			caseInfo.Judge = value
+		case strings.Contains(label, "court") && !strings.Contains(label, "court number"):
+			caseInfo.CourtComplex = value
 		}
-	}
+	})
 }
 
 // parseCaseDetailsFromDivs extracts case info from div/span structure
-func (p *Parser) parseCaseDetailsFromDivs(container *rod.Element, caseInfo *database.CaseInfo) {
+func (p *Parser) parseCaseDetailsFromDivs(container *goquery.Selection, caseInfo *database.CaseInfo) {
 	// Look for labeled spans or divs
-	elements := container.MustElements("div, span")
-	
-	for i, elem := range elements {
-		text := strings.TrimSpace(elem.MustText())
+	elements := container.Find("div, span")
+
+	for i := 0; i < elements.Length(); i++ {
+		text := innerText(elements.Eq(i))
 		lowerText := strings.ToLower(text)
-		
-		// Check if this element is a label
-		if strings.HasSuffix(text, ":") {
-			// Get the next element as value
-			if i+1 < len(elements) {
-				value := strings.TrimSpace(elements[i+1].MustText())
-				
-				switch {
-				case strings.Contains(lowerText, "case no"):
-					caseInfo.CaseNumber = value
-				case strings.Contains(lowerText, "case type"):
-					caseInfo.CaseType = value
-				case strings.Contains(lowerText, "year"):
-					caseInfo.FilingYear = value
-				case strings.Contains(lowerText, "filing date"):
-					caseInfo.FilingDate, _ = p.parseDate(value)
-				case strings.Contains(lowerText, "next date"):
-					caseInfo.NextHearing, _ = p.parseDate(value)
-				case strings.Contains(lowerText, "status"):
-					caseInfo.Status = value
-				case strings.Contains(lowerText, "judge"):
-					caseInfo.Judge = value
-				}
-			}
+
+		// Check if this element is a label, with the next element as its value
+		if !strings.HasSuffix(text, ":") || i+1 >= elements.Length() {
+			continue
+		}
+		value := innerText(elements.Eq(i + 1))
+
+		switch {
+		case strings.Contains(lowerText, "cnr"):
+			caseInfo.CNR = value
+		case strings.Contains(lowerText, "case no"):
+			caseInfo.CaseNumber = value
+		case strings.Contains(lowerText, "case type"):
+			caseInfo.CaseType = value
+		case strings.Contains(lowerText, "year"):
+			caseInfo.FilingYear = value
+		case strings.Contains(lowerText, "filing date"):
+			caseInfo.FilingDate = p.readDate(caseInfo.ParseReport, "filing_date", value)
+		case strings.Contains(lowerText, "next date"):
+			caseInfo.NextHearing = p.readDate(caseInfo.ParseReport, "next_hearing", value)
+		case strings.Contains(lowerText, "status"):
+			caseInfo.Status = value
+		case strings.Contains(lowerText, "judge"):
+			caseInfo.Judge = value
 		}
 	}
 }
 
 // parseCaseDetailsFromText uses regex patterns to extract info from text
 func (p *Parser) parseCaseDetailsFromText(text string, caseInfo *database.CaseInfo) {
-	// Case Number patterns
-	caseNumberPatterns := []string{
-		`Case\s*No[\.\s:]+([A-Z]+[\/\-]?\d+[\/\-]?\d+)`,
-		`CNR\s*Number[\.\s:]+([A-Z0-9]+)`,
-		`([A-Z]+[\/\-]\d+[\/\-]\d{4})`,
-	}
-	
-	for _, pattern := range caseNumberPatterns {
-		if matches := regexp.MustCompile(pattern).FindStringSubmatch(text); len(matches) > 1 {
-			caseInfo.CaseNumber = matches[1]
-			break
+	// Case number, preferring the one labelled as such
+	if matches := regexp.MustCompile(`Case\s*No[\.\s:]+([^\n]+)`).FindStringSubmatch(text); len(matches) > 1 {
+		if id, ok := caseid.Find(matches[1]); ok {
+			caseInfo.CaseNumber = id.String()
 		}
 	}
-	
+	if cnr, ok := caseid.FindCNR(text); ok {
+		caseInfo.CNR = cnr.String()
+	}
+	if caseInfo.CaseNumber == "" {
+		if id, ok := caseid.Find(text); ok {
+			caseInfo.CaseNumber = id.String()
+		}
+	}
+
 	// Extract case type from case number if not separately available
 	if caseInfo.CaseType == "" && caseInfo.CaseNumber != "" {
-		parts := regexp.MustCompile(`[\/\-]`).Split(caseInfo.CaseNumber, -1)
-		if len(parts) > 0 {
-			caseInfo.CaseType = parts[0]
+		if id, err := caseid.Parse(caseInfo.CaseNumber); err == nil {
+			caseInfo.CaseType = id.Type
 		}
 	}
-	
+
 	// Filing Year
 	if matches := regexp.MustCompile(`Year[\.\s:]+(\d{4})`).FindStringSubmatch(text); len(matches) > 1 {
 		caseInfo.FilingYear = matches[1]
 	}
-	
+
 	// Dates
 	datePattern := `(\d{1,2}[\-\/]\d{1,2}[\-\/]\d{4})`
-	// dates := regexp.MustCompile(datePattern).FindAllString(text, -1) // Removed unused variable
-	
+
 	// Try to identify dates by context
 	lines := strings.Split(text, "\n")
 	for _, line := range lines {
 		lowerLine := strings.ToLower(line)
 		if strings.Contains(lowerLine, "filing date") || strings.Contains(lowerLine, "institution") {
 			if date := regexp.MustCompile(datePattern).FindString(line); date != "" {
-				caseInfo.FilingDate, _ = p.parseDate(date)
+				caseInfo.FilingDate = p.readDate(caseInfo.ParseReport, "filing_date", date)
 			}
 		}
 		if strings.Contains(lowerLine, "next") && strings.Contains(lowerLine, "date") {
 			if date := regexp.MustCompile(datePattern).FindString(line); date != "" {
-				caseInfo.NextHearing, _ = p.parseDate(date)
+				caseInfo.NextHearing = p.readDate(caseInfo.ParseReport, "next_hearing", date)
 			}
 		}
 	}
 }
 
-// parseParties extracts party information from Delhi District Court format
-func (p *Parser) parseParties(page *rod.Page) ([]database.Party, error) {
-	// var parties []database.Party // Removed unused variable
-	
+// parseParties extracts party information from Delhi District Court format,
+// returning the strategy that found them
+func (p *Parser) parseParties(doc *goquery.Document) ([]database.Party, string, error) {
 	// Method 1: Look for parties in table format
-	partyTable, err := page.Element("table#party_table, table.party-table, div#party_details table")
-	if err == nil {
-		return p.parsePartiesFromTable(partyTable)
+	if partyTable := doc.Find("table#party_table, table.party-table, div#party_details table").First(); partyTable.Length() > 0 {
+		parties, err := p.parsePartiesFromTable(partyTable)
+		return parties, StrategyTable, err
 	}
-	
+
 	// Method 2: Look for parties in div structure
-	partyContainer, err := page.Element("div#petitioner_respondent, div.party-details, div#party_info")
-	if err == nil {
-		return p.parsePartiesFromDivs(partyContainer)
+	if partyContainer := doc.Find("div#petitioner_respondent, div.party-details, div#party_info").First(); partyContainer.Length() > 0 {
+		parties, err := p.parsePartiesFromDivs(partyContainer)
+		return parties, StrategyDivs, err
 	}
-	
+
 	// Method 3: Parse from text patterns
-	bodyElem, err := page.Element("body")
-	if err != nil {
-		return nil, err
+	body := doc.Find("body")
+	if body.Length() == 0 {
+		return nil, "", fmt.Errorf("page has no body")
 	}
-	bodyText, _ := bodyElem.Text()
-	return p.parsePartiesFromText(bodyText)
+	parties, err := p.parsePartiesFromText(innerText(body))
+	return parties, StrategyText, err
 }
 
 // parsePartiesFromTable extracts parties from table format
-func (p *Parser) parsePartiesFromTable(table *rod.Element) ([]database.Party, error) {
+func (p *Parser) parsePartiesFromTable(table *goquery.Selection) ([]database.Party, error) {
 	var parties []database.Party
-	rows := table.MustElements("tr")
-	
+
 	// Skip header row
-	for i := 1; i < len(rows); i++ {
-		cells := rows[i].MustElements("td")
-		if len(cells) >= 2 {
-			party := database.Party{}
-			
-			// First cell usually contains party type
-			partyType := strings.TrimSpace(cells[0].MustText())
-			if strings.Contains(strings.ToLower(partyType), "petitioner") {
-				party.Type = "Petitioner"
-			} else if strings.Contains(strings.ToLower(partyType), "respondent") {
-				party.Type = "Respondent"
-			} else {
-				party.Type = partyType
-			}
-			
-			// Second cell contains party name
-			party.Name = strings.TrimSpace(cells[1].MustText())
-			
-			// Additional cells may contain advocate info
-			if len(cells) > 2 {
-				party.AdvocateName = strings.TrimSpace(cells[2].MustText())
-			}
-			if len(cells) > 3 {
-				party.AdvocateCode = strings.TrimSpace(cells[3].MustText())
-			}
-			
-			if party.Name != "" {
-				parties = append(parties, party)
-			}
+	table.Find("tr").Slice(1, goquery.ToEnd).Each(func(_ int, row *goquery.Selection) {
+		cells := row.Find("td")
+		if cells.Length() < 2 {
+			return
 		}
-	}
-	
+		party := database.Party{}
+
+		// First cell usually contains party type
+		partyType := innerText(cells.Eq(0))
+		if strings.Contains(strings.ToLower(partyType), "petitioner") {
+			party.Type = "Petitioner"
+		} else if strings.Contains(strings.ToLower(partyType), "respondent") {
+			party.Type = "Respondent"
+		} else {
+			party.Type = partyType
+		}
+
+		// Second cell contains party name
+		party.Name = innerText(cells.Eq(1))
+
+		// Additional cells may contain advocate info
+		if cells.Length() > 2 {
+			party.AdvocateName = innerText(cells.Eq(2))
+		}
+		if cells.Length() > 3 {
+			party.AdvocateCode = innerText(cells.Eq(3))
+		}
+
+		if party.Name != "" {
+			parties = append(parties, party)
+		}
+	})
+
 	return parties, nil
 }
 
 // parsePartiesFromDivs extracts parties from div structure
-func (p *Parser) parsePartiesFromDivs(container *rod.Element) ([]database.Party, error) {
+func (p *Parser) parsePartiesFromDivs(container *goquery.Selection) ([]database.Party, error) {
 	var parties []database.Party
-	
+
 	// Look for petitioner section
-	petitionerSection, err := container.Element("div.petitioner, div#petitioner")
-	if err == nil {
-		petitionerText := petitionerSection.MustText()
-		petitioners := p.extractPartyNames(petitionerText)
-		for _, name := range petitioners {
+	if petitionerSection := container.Find("div.petitioner, div#petitioner").First(); petitionerSection.Length() > 0 {
+		for _, name := range p.ExtractPartyNames(innerText(petitionerSection)) {
 			parties = append(parties, database.Party{
 				Type: "Petitioner",
 				Name: name,
 			})
 		}
 	}
-	
+
 	// Look for respondent section
-	respondentSection, err := container.Element("div.respondent, div#respondent")
-	if err == nil {
-		respondentText := respondentSection.MustText()
-		respondents := p.extractPartyNames(respondentText)
-		for _, name := range respondents {
+	if respondentSection := container.Find("div.respondent, div#respondent").First(); respondentSection.Length() > 0 {
+		for _, name := range p.ExtractPartyNames(innerText(respondentSection)) {
 			parties = append(parties, database.Party{
 				Type: "Respondent",
 				Name: name,
 			})
 		}
 	}
-	
+
 	return parties, nil
 }
 
 // parsePartiesFromText extracts parties using text patterns
 func (p *Parser) parsePartiesFromText(text string) ([]database.Party, error) {
 	var parties []database.Party
-	
+
 	// Look for patterns like "Petitioner: Name" or "Petitioner(s): Name"
 	petitionerPattern := regexp.MustCompile(`(?i)Petitioner\(?\s?\)?:?\s*([^\n\r]+)`)
 	if matches := petitionerPattern.FindStringSubmatch(text); len(matches) > 1 {
-		names := p.extractPartyNames(matches[1])
-		for _, name := range names {
+		for _, name := range p.ExtractPartyNames(matches[1]) {
 			parties = append(parties, database.Party{
 				Type: "Petitioner",
 				Name: name,
 			})
 		}
 	}
-	
+
 	respondentPattern := regexp.MustCompile(`(?i)Respondent\(?\s?\)?:?\s*([^\n\r]+)`)
 	if matches := respondentPattern.FindStringSubmatch(text); len(matches) > 1 {
-		names := p.extractPartyNames(matches[1])
-		for _, name := range names {
+		for _, name := range p.ExtractPartyNames(matches[1]) {
 			parties = append(parties, database.Party{
 				Type: "Respondent",
 				Name: name,
 			})
 		}
 	}
-	
+
 	return parties, nil
 }
 
-// extractPartyNames splits multiple party names
-func (p *Parser) extractPartyNames(text string) []string {
+// ExtractPartyNames splits a list of parties such as "A and B & C etc." into names
+func (p *Parser) ExtractPartyNames(text string) []string {
 	var names []string
-	
+
 	// Clean up the text
 	text = strings.TrimSpace(text)
 	text = regexp.MustCompile(`\s+`).ReplaceAllString(text, " ")
-	
+
 	// Split by common separators
 	parts := regexp.MustCompile(`\s+(?:and|AND|And|&)\s+`).Split(text, -1)
-	
+
 	for _, part := range parts {
 		name := strings.TrimSpace(part)
 		// Remove trailing "etc" or numbers
@@ -341,14 +428,35 @@ func (p *Parser) extractPartyNames(text string) []string {
 			names = append(names, name)
 		}
 	}
-	
+
 	return names
 }
 
-// ParseOrders extracts order/judgment information from Delhi District Courts
-func (p *Parser) ParseOrders(page *rod.Page) ([]database.Order, error) {
+// ParseOrders extracts order/judgment information from Delhi District Courts.
+// Order dates that can't be read as printed are noted in report, which may
+// be nil.
+func (p *Parser) ParseOrders(page *rod.Page, report *database.ParseReport) ([]database.Order, error) {
+	pageHTML, err := page.HTML()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page: %w", err)
+	}
+	info, err := page.Info()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page URL: %w", err)
+	}
+	return p.ParseOrdersHTML(pageHTML, info.URL, report)
+}
+
+// ParseOrdersHTML extracts orders from a page's HTML. Relative PDF links are
+// resolved against pageURL.
+func (p *Parser) ParseOrdersHTML(pageHTML, pageURL string, report *database.ParseReport) ([]database.Order, error) {
 	var orders []database.Order
-	
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(pageHTML))
+	if err != nil {
+		return orders, fmt.Errorf("failed to parse page: %w", err)
+	}
+
 	// Try multiple selectors for orders table
 	orderSelectors := []string{
 		"table#order_table",
@@ -357,166 +465,269 @@ func (p *Parser) ParseOrders(page *rod.Page) ([]database.Order, error) {
 		"table[summary*='order']",
 		"table[summary*='Order']",
 	}
-	
-	var ordersTable *rod.Element
+
+	var ordersTable *goquery.Selection
 	for _, selector := range orderSelectors {
-		table, err := page.Element(selector)
-		if err == nil {
+		if table := doc.Find(selector).First(); table.Length() > 0 {
 			ordersTable = table
 			break
 		}
 	}
-	
+
 	if ordersTable == nil {
 		// Try to find any table that might contain orders
-		tables := page.MustElements("table")
-		for _, table := range tables {
-			text := table.MustText()
-			if strings.Contains(strings.ToLower(text), "order") && 
-			   (strings.Contains(text, "PDF") || strings.Contains(text, "Download")) {
+		doc.Find("table").EachWithBreak(func(_ int, table *goquery.Selection) bool {
+			text := innerText(table)
+			if strings.Contains(strings.ToLower(text), "order") &&
+				(strings.Contains(text, "PDF") || strings.Contains(text, "Download")) {
 				ordersTable = table
-				break
+				return false
 			}
-		}
+			return true
+		})
 	}
-	
+
 	if ordersTable == nil {
 		return orders, fmt.Errorf("orders table not found")
 	}
-	
-	rows := ordersTable.MustElements("tr")
-	for i := 1; i < len(rows); i++ { // Skip header
-		cells := rows[i].MustElements("td")
-		if len(cells) >= 2 {
-			order := database.Order{}
-			
-			// Parse order date (usually first column)
-			dateStr := strings.TrimSpace(cells[0].MustText())
-			if dateStr != "" {
-				order.OrderDate, _ = p.parseDate(dateStr)
-			}
-			
-			// Parse description (usually second column)
-			order.Description = strings.TrimSpace(cells[1].MustText())
-			
-			// Look for PDF link (usually in last column or as link in description)
-			for _, cell := range cells {
-				links := cell.MustElements("a")
-				for _, link := range links {
-					href, err := link.Attribute("href")
-					if err == nil && href != nil {
-						hrefStr := *href
-						if strings.Contains(strings.ToLower(hrefStr), "pdf") ||
-						   strings.Contains(strings.ToLower(hrefStr), "download") ||
-						   strings.Contains(strings.ToLower(hrefStr), "order") {
-							order.PDFLink = p.makeAbsoluteURL(page, hrefStr)
-							break
-						}
-					}
-				}
-			}
-			
-			// Try to extract judge name if available
-			if len(cells) > 2 {
-				order.JudgeName = strings.TrimSpace(cells[2].MustText())
-			}
-			
-			if order.OrderDate.Year() > 1900 { // Valid date check
-				orders = append(orders, order)
+
+	ordersTable.Find("tr").Slice(1, goquery.ToEnd).Each(func(_ int, row *goquery.Selection) { // Skip header
+		cells := row.Find("td")
+		if cells.Length() < 2 {
+			return
+		}
+		order := database.Order{}
+
+		// Parse order date (usually first column)
+		if dateStr := innerText(cells.Eq(0)); dateStr != "" {
+			order.OrderDate = p.readDate(report, "order_date", dateStr)
+		}
+
+		// Parse description (usually second column)
+		order.Description = innerText(cells.Eq(1))
+
+		// Look for PDF link (usually in last column or as link in description)
+		cells.Find("a[href]").EachWithBreak(func(_ int, link *goquery.Selection) bool {
+			href, _ := link.Attr("href")
+			lowerHref := strings.ToLower(href)
+			if strings.Contains(lowerHref, "pdf") ||
+				strings.Contains(lowerHref, "download") ||
+				strings.Contains(lowerHref, "order") {
+				order.PDFLink = makeAbsoluteURL(pageURL, href)
+				return false
 			}
+			return true
+		})
+
+		// Try to extract judge name if available
+		if cells.Length() > 2 {
+			order.JudgeName = innerText(cells.Eq(2))
 		}
-	}
-	
+
+		result := p.classifier.Classify(order.Description, "")
+		order.OrderType, order.OrderTypeConfidence = result.Type, result.Confidence
+
+		if order.OrderDate.Year() > 1900 { // Valid date check
+			orders = append(orders, order)
+		}
+	})
+
 	return orders, nil
 }
 
-// parseCaseHistory extracts case history/status
-func (p *Parser) parseCaseHistory(page *rod.Page, caseInfo *database.CaseInfo) {
-	// Look for case history table
-	historyTable, err := page.Element("table#case_history, table.case-history, div#history table")
+// ParseCaseTypesHTML reads the options of the search form's case type
+// dropdown, skipping the "Select" placeholder and repeated labels
+func (p *Parser) ParseCaseTypesHTML(pageHTML string) ([]database.CaseType, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(pageHTML))
 	if err != nil {
-		return
+		return nil, fmt.Errorf("failed to parse page: %w", err)
 	}
-	
-	rows := historyTable.MustElements("tr")
-	for _, row := range rows {
-		text := strings.ToLower(row.MustText())
+
+	dropdown := doc.Find("select#case_type").First()
+	if dropdown.Length() == 0 {
+		return nil, fmt.Errorf("case type dropdown not found")
+	}
+
+	var types []database.CaseType
+	seen := make(map[string]bool)
+	dropdown.Find("option").Each(func(_ int, option *goquery.Selection) {
+		label := strings.Join(strings.Fields(option.Text()), " ")
+		value, _ := option.Attr("value")
+		if strings.TrimSpace(value) == "" || label == "" || seen[label] {
+			return
+		}
+		seen[label] = true
+		types = append(types, database.CaseType{Value: strings.TrimSpace(value), Label: label})
+	})
+
+	return types, nil
+}
+
+// CaseTypeValue returns the option value of caseType among the form's types.
+// The label must match exactly or, as in catalogue.Resolve, differ only in
+// spacing, dots and case.
+func CaseTypeValue(types []database.CaseType, caseType string) (string, bool) {
+	for _, candidate := range types {
+		if candidate.Label == caseType {
+			return candidate.Value, true
+		}
+	}
+	normalised, compact := caseid.NormaliseType(caseType), caseid.CompactType(caseType)
+	for _, candidate := range types {
+		if caseid.NormaliseType(candidate.Label) == normalised {
+			return candidate.Value, true
+		}
+	}
+	for _, candidate := range types {
+		if caseid.CompactType(candidate.Label) == compact {
+			return candidate.Value, true
+		}
+	}
+	return "", false
+}
+
+// parseCaseHistory extracts case history/status
+func (p *Parser) parseCaseHistory(doc *goquery.Document, caseInfo *database.CaseInfo) {
+	// Look for case history table
+	historyTable := doc.Find("table#case_history, table.case-history, div#history table").First()
+
+	historyTable.Find("tr").EachWithBreak(func(_ int, row *goquery.Selection) bool {
+		text := strings.ToLower(innerText(row))
 		// Look for disposal status
 		if strings.Contains(text, "disposed") || strings.Contains(text, "decided") {
 			caseInfo.Status = "Disposed"
-			return
+			return false
 		}
 		// Look for current status
 		if strings.Contains(text, "pending") {
 			caseInfo.Status = "Pending"
 		}
+		return true
+	})
+}
+
+// ParseDate parses the date formats used by Indian courts, see
+// courtdate.Parse, returning the first day of a range
+func (p *Parser) ParseDate(dateStr string) (time.Time, error) {
+	date, err := courtdate.Parse(dateStr)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to parse date: %w", err)
 	}
+	return date.Time, nil
 }
 
-// parseDate parses various date formats used by Indian courts
-func (p *Parser) parseDate(dateStr string) (time.Time, error) {
-	// Clean the date string
-	dateStr = strings.TrimSpace(dateStr)
-	dateStr = regexp.MustCompile(`\s+`).ReplaceAllString(dateStr, " ")
-	
-	// Common date formats in Indian court systems
-	formats := []string{
-		"02-01-2006",
-		"02/01/2006",
-		"02.01.2006",
-		"02-Jan-2006",
-		"02-January-2006",
-		"02 Jan 2006",
-		"02 January 2006",
-		"2006-01-02",
-		"Jan 02, 2006",
-		"January 02, 2006",
-	}
-	
-	for _, format := range formats {
-		if date, err := time.Parse(format, dateStr); err == nil {
-			return date, nil
-		}
-	}
-	
-	// Try to handle some edge cases
-	// Remove day names if present
-	dateStr = regexp.MustCompile(`(?i)(Monday|Tuesday|Wednesday|Thursday|Friday|Saturday|Sunday),?\s*`).ReplaceAllString(dateStr, "")
-	
-	for _, format := range formats {
-		if date, err := time.Parse(format, dateStr); err == nil {
-			return date, nil
-		}
-	}
-	
-	return time.Time{}, fmt.Errorf("unable to parse date: %s", dateStr)
+// readDate parses the date printed for field, noting in report, which may be
+// nil, values that couldn't be read, needed a guess or were ranges
+func (p *Parser) readDate(report *database.ParseReport, field, value string) time.Time {
+	date, err := courtdate.Parse(value)
+	issue := database.DateIssue{Field: field, Value: value}
+	switch {
+	case err != nil:
+		issue.Problem = database.DateUnreadable
+	case date.Ambiguous != "":
+		issue.Problem, issue.Detail = database.DateAmbiguous, date.Ambiguous
+	case date.IsRange():
+		issue.Problem = database.DateRange
+	default:
+		return date.Time
+	}
+
+	p.logger.Warn("Date not read as printed", "field", field, "value", value, "problem", issue.Problem)
+	if report != nil {
+		report.DateIssues = append(report.DateIssues, issue)
+	}
+	return date.Time
 }
 
 // makeAbsoluteURL converts a relative URL to absolute
-func (p *Parser) makeAbsoluteURL(page *rod.Page, relativeURL string) string {
+func makeAbsoluteURL(pageURL, relativeURL string) string {
 	if strings.HasPrefix(relativeURL, "http://") || strings.HasPrefix(relativeURL, "https://") {
 		return relativeURL
 	}
-	
-	pageURL := page.MustInfo().URL
-	
+
 	// Parse base URL
 	parts := strings.Split(pageURL, "/")
 	if len(parts) >= 3 {
 		baseURL := strings.Join(parts[:3], "/")
-		
+
 		if strings.HasPrefix(relativeURL, "/") {
 			return baseURL + relativeURL
-		} else {
-			// Get directory path
-			dirParts := parts[:len(parts)-1]
-			return strings.Join(dirParts, "/") + "/" + relativeURL
 		}
+		// Get directory path
+		dirParts := parts[:len(parts)-1]
+		return strings.Join(dirParts, "/") + "/" + relativeURL
 	}
-	
+
 	return relativeURL
 }
 
+// blockElements start a new line in innerText
+var blockElements = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true,
+	"dd": true, "div": true, "dl": true, "dt": true, "fieldset": true,
+	"footer": true, "form": true, "h1": true, "h2": true, "h3": true,
+	"h4": true, "h5": true, "h6": true, "header": true, "hr": true,
+	"li": true, "main": true, "nav": true, "ol": true, "p": true,
+	"pre": true, "section": true, "table": true, "tbody": true,
+	"tfoot": true, "thead": true, "tr": true, "ul": true,
+}
+
+var horizontalSpace = regexp.MustCompile(`[ \t\f\r\x{00a0}]+`)
+
+// innerText approximates the browser's innerText for a selection: block
+// elements and <br> break lines, table cells are tab separated, runs of
+// whitespace collapse and blank lines are dropped. The result is trimmed.
+func innerText(sel *goquery.Selection) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			b.WriteString(strings.ReplaceAll(n.Data, "\n", " "))
+			return
+		case html.ElementNode:
+			switch n.Data {
+			case "script", "style", "noscript", "template", "head":
+				return
+			case "br":
+				b.WriteString("\n")
+				return
+			}
+		}
+
+		block := n.Type == html.ElementNode && blockElements[n.Data]
+		if block {
+			b.WriteString("\n")
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+		switch {
+		case block:
+			b.WriteString("\n")
+		case n.Type == html.ElementNode && (n.Data == "td" || n.Data == "th"):
+			b.WriteString("\t")
+		}
+	}
+	for _, n := range sel.Nodes {
+		walk(n)
+	}
+
+	var lines []string
+	for _, line := range strings.Split(b.String(), "\n") {
+		line = strings.TrimSpace(horizontalSpace.ReplaceAllStringFunc(line, func(space string) string {
+			if strings.Contains(space, "\t") {
+				return "\t"
+			}
+			return " "
+		}))
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
 // ParseError extracts error message from the page
 func (p *Parser) ParseError(page *rod.Page) string {
 	errorSelectors := []string{
@@ -559,4 +770,4 @@ func (p *Parser) ParseError(page *rod.Page) string {
 	}
 
 	return ""
-}
\ No newline at end of file
+}
@@ -9,31 +9,47 @@ import (
 	"strings"
 	"time"
 
+	"github.com/JustJay7/court-data-fetcher/internal/courtdate"
 	"github.com/JustJay7/court-data-fetcher/internal/database"
+	"github.com/JustJay7/court-data-fetcher/internal/ordertype"
+	"github.com/JustJay7/court-data-fetcher/internal/upstream"
 	"github.com/JustJay7/court-data-fetcher/pkg/logger"
 	"gorm.io/gorm"
 )
 
 // PDFDownloader handles downloading and storing PDF files
 type PDFDownloader struct {
-	db       *gorm.DB
-	logger   *logger.Logger
-	savePath string
-	client   *http.Client
+	db         *gorm.DB
+	logger     *logger.Logger
+	savePath   string
+	client     *http.Client
+	classifier *ordertype.Classifier
 }
 
 // NewPDFDownloader creates a new PDF downloader
 func NewPDFDownloader(db *gorm.DB, logger *logger.Logger, savePath string) *PDFDownloader {
 	return &PDFDownloader{
-		db:       db,
-		logger:   logger,
-		savePath: savePath,
+		db:         db,
+		logger:     logger,
+		savePath:   savePath,
 		client: &http.Client{
 			Timeout: 60 * time.Second,
 		},
+		classifier: ordertype.Default(),
 	}
 }
 
+// SetClassifier replaces the rules downloaded orders are relabelled with
+func (d *PDFDownloader) SetClassifier(classifier *ordertype.Classifier) {
+	d.classifier = classifier
+}
+
+// SetUpstream sends downloads through up's proxies and per-host limits,
+// without which they go out directly and back to back
+func (d *PDFDownloader) SetUpstream(up *upstream.Upstream) {
+	d.client = up.Client(60 * time.Second)
+}
+
 // DownloadOrderPDFs downloads all PDFs for orders that haven't been downloaded yet
 func (d *PDFDownloader) DownloadOrderPDFs() error {
 	var orders []database.Order
@@ -54,9 +70,6 @@ func (d *PDFDownloader) DownloadOrderPDFs() error {
 		// Mark as downloaded
 		order.Downloaded = true
 		d.db.Save(&order)
-		
-		// Rate limiting
-		time.Sleep(2 * time.Second)
 	}
 
 	return nil
@@ -77,7 +90,7 @@ func (d *PDFDownloader) downloadPDF(order *database.Order) error {
 	// Generate filename
 	filename := fmt.Sprintf("order_%d_%s.pdf", 
 		order.ID, 
-		strings.ReplaceAll(order.OrderDate.Format("2006-01-02"), "-", ""))
+		strings.ReplaceAll(order.OrderDate.In(courtdate.IST).Format("2006-01-02"), "-", ""))
 	fullPath := filepath.Join(dirPath, filename)
 
 	// Download the file
@@ -120,32 +133,137 @@ func (d *PDFDownloader) downloadPDF(order *database.Order) error {
 		"size", size, 
 		"path", fullPath)
 
+	// The PDF may say what the description doesn't
+	text := d.pdfText(order)
+	d.classifyText(order, text)
+	d.proposeNextHearing(order, text)
 	return nil
 }
 
-// CleanupOldPDFs removes PDFs older than specified days
-func (d *PDFDownloader) CleanupOldPDFs(daysToKeep int) error {
+// pdfText returns the text of an order's downloaded PDF, or "" when there is
+// none to read
+func (d *PDFDownloader) pdfText(order *database.Order) string {
+	if order.LocalPath == "" {
+		return ""
+	}
+	data, err := os.ReadFile(order.LocalPath)
+	if err != nil {
+		d.logger.Warn("No text read from order PDF", "orderID", order.ID, "error", err)
+		return ""
+	}
+	text, err := ExtractPDFText(data)
+	if err != nil {
+		d.logger.Warn("No text read from order PDF", "orderID", order.ID, "error", err)
+	}
+	return text
+}
+
+// classify labels an order from its description and, once downloaded, the
+// text of its PDF
+func (d *PDFDownloader) classify(order *database.Order) {
+	d.classifyText(order, d.pdfText(order))
+}
+
+func (d *PDFDownloader) classifyText(order *database.Order, text string) {
+	result := d.classifier.Classify(order.Description, text)
+	order.OrderType, order.OrderTypeConfidence = result.Type, result.Confidence
+}
+
+// proposeNextHearing updates the stored next hearing of the order's case
+// from a listing direction in the order PDF, when the details page gave no
+// date after the latest order
+func (d *PDFDownloader) proposeNextHearing(order *database.Order, text string) {
+	direction, ok := NewParser(d.logger).FindHearingDirection(text)
+	if !ok {
+		return
+	}
+
+	var caseInfo database.CaseInfo
+	if err := d.db.Preload("Orders").First(&caseInfo, order.CaseInfoID).Error; err != nil {
+		d.logger.Warn("Case not found for order", "orderID", order.ID, "error", err)
+		return
+	}
+	latest := latestOrderDate(caseInfo.Orders)
+	if !direction.Date.After(latest) {
+		return
+	}
+	if !applyHearingDirection(&caseInfo, latest, direction, database.NextHearingFromOrderPDF) {
+		return
+	}
+
+	err := d.db.Model(&caseInfo).Updates(map[string]interface{}{
+		"next_hearing":        caseInfo.NextHearing,
+		"next_hearing_source": caseInfo.NextHearingSource,
+		"next_hearing_note":   caseInfo.NextHearingNote,
+	}).Error
+	if err != nil {
+		d.logger.Error("Failed to save next hearing", "caseID", caseInfo.ID, "error", err)
+		return
+	}
+	d.logger.Info("Next hearing read from order PDF",
+		"caseNumber", caseInfo.CaseNumber,
+		"nextHearing", caseInfo.NextHearing,
+		"direction", direction.Phrase)
+}
+
+// ClassifyOrders relabels every stored order, for instance after the rules
+// changed, and returns how many changed type
+func (d *PDFDownloader) ClassifyOrders() (int, error) {
+	changed := 0
+	var orders []database.Order
+	err := d.db.FindInBatches(&orders, 200, func(tx *gorm.DB, _ int) error {
+		for i := range orders {
+			previous := orders[i].OrderType
+			d.classify(&orders[i])
+			if err := d.db.Model(&orders[i]).Updates(map[string]interface{}{
+				"order_type":            orders[i].OrderType,
+				"order_type_confidence": orders[i].OrderTypeConfidence,
+			}).Error; err != nil {
+				return err
+			}
+			if orders[i].OrderType != previous {
+				changed++
+			}
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return changed, fmt.Errorf("failed to classify orders: %w", err)
+	}
+	return changed, nil
+}
+
+// CleanupOldPDFs removes PDFs older than specified days and returns how many
+// were removed. With dryRun set nothing is deleted and the count is of the
+// PDFs that would be removed.
+func (d *PDFDownloader) CleanupOldPDFs(daysToKeep int, dryRun bool) (int, error) {
 	cutoffDate := time.Now().AddDate(0, 0, -daysToKeep)
 	
 	var orders []database.Order
 	if err := d.db.Where("downloaded = ? AND created_at < ? AND local_path != ?", 
 		true, cutoffDate, "").Find(&orders).Error; err != nil {
-		return err
+		return 0, err
+	}
+
+	if dryRun {
+		return len(orders), nil
 	}
 
+	removed := 0
 	for _, order := range orders {
-		if err := os.Remove(order.LocalPath); err != nil {
+		if err := os.Remove(order.LocalPath); err != nil && !os.IsNotExist(err) {
 			d.logger.Warn("Failed to remove PDF", "path", order.LocalPath, "error", err)
 			continue
 		}
 		
-		// Update database
+		// Update database. Downloaded stays set so the download worker
+		// doesn't fetch the PDF again.
 		order.LocalPath = ""
-		order.Downloaded = false
 		d.db.Save(&order)
+		removed++
 		
 		d.logger.Info("Removed old PDF", "orderID", order.ID)
 	}
 
-	return nil
+	return removed, nil
 }
\ No newline at end of file
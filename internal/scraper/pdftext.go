@@ -0,0 +1,112 @@
+package scraper
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// maxPDFStreamBytes bounds the size of one decompressed content stream
+const maxPDFStreamBytes = 8 << 20
+
+var (
+	pdfStreamPattern = regexp.MustCompile(`(?s)<<((?:[^<>]|<<[^<>]*>>)*)>>\s*stream\r?\n(.*?)\r?\n?endstream`)
+	// pdfTextPattern finds the strings shown by the Tj, ' and " operators and
+	// the arrays shown by TJ, and the T* and Td operators that end a line.
+	// Strings may hold one level of balanced parentheses, as in "W.P.(C)".
+	pdfTextPattern   = regexp.MustCompile(`(?s)\((?:\\.|[^\\()]|\((?:\\.|[^\\()])*\))*\)\s*(?:Tj|'|")|\[(?:\\.|[^\]])*\]\s*TJ|\bT\*|\bTd\b|\bTD\b`)
+	pdfStringPattern = regexp.MustCompile(`(?s)\((?:\\.|[^\\()]|\((?:\\.|[^\\()])*\))*\)`)
+)
+
+// ErrNoPDFText is returned for PDFs with no readable text, such as scanned
+// orders or ones using fonts with custom encodings
+var ErrNoPDFText = errors.New("no text found in PDF")
+
+// ExtractPDFText returns the text shown by a PDF's content streams, one line
+// per text positioning operator. Only plain and Flate-compressed streams
+// with literal strings are read, which covers orders typed into the court's
+// own system but not scanned ones.
+func ExtractPDFText(data []byte) (string, error) {
+	var text strings.Builder
+	for _, match := range pdfStreamPattern.FindAllSubmatch(data, -1) {
+		dictionary, content := match[1], match[2]
+		if bytes.Contains(dictionary, []byte("/FlateDecode")) {
+			inflated, err := inflate(content)
+			if err != nil {
+				continue
+			}
+			content = inflated
+		} else if bytes.Contains(dictionary, []byte("/Filter")) {
+			// Images and other encodings hold no text
+			continue
+		}
+
+		for _, op := range pdfTextPattern.FindAll(content, -1) {
+			if op[0] != '(' && op[0] != '[' {
+				text.WriteByte('\n')
+				continue
+			}
+			for _, literal := range pdfStringPattern.FindAll(op, -1) {
+				text.WriteString(unescapePDFString(literal[1 : len(literal)-1]))
+			}
+		}
+		text.WriteByte('\n')
+	}
+
+	var lines []string
+	for _, line := range strings.Split(text.String(), "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		return "", ErrNoPDFText
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func inflate(content []byte) ([]byte, error) {
+	reader, err := zlib.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(io.LimitReader(reader, maxPDFStreamBytes))
+}
+
+// unescapePDFString resolves the backslash escapes of a PDF literal string
+func unescapePDFString(s []byte) string {
+	var out strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			out.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch c := s[i]; c {
+		case 'n', 'r':
+			out.WriteByte(' ')
+		case 't':
+			out.WriteByte('\t')
+		case 'b', 'f':
+		case '\r', '\n':
+			// A backslash before a line break continues the string
+		default:
+			if c >= '0' && c <= '7' {
+				value, digits := 0, 0
+				for ; digits < 3 && i < len(s) && s[i] >= '0' && s[i] <= '7'; digits++ {
+					value = value*8 + int(s[i]-'0')
+					i++
+				}
+				i--
+				out.WriteByte(byte(value))
+				continue
+			}
+			out.WriteByte(c)
+		}
+	}
+	return out.String()
+}
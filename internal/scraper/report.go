@@ -0,0 +1,90 @@
+package scraper
+
+import (
+	"math"
+
+	"github.com/JustJay7/court-data-fetcher/internal/database"
+)
+
+// Parser strategies named in a ParseReport
+const (
+	StrategyTable   = "table"
+	StrategyDivs    = "divs"
+	StrategyText    = "text"
+	StrategyHistory = "history"
+	// StrategyOrders reads the next hearing from the orders' listing directions
+	StrategyOrders = "orders"
+)
+
+// expectedFields are the fields a complete case page yields, weighted by how
+// much a record is worth without them
+var expectedFields = []struct {
+	name   string
+	weight float64
+}{
+	{"case_number", 3},
+	{"parties", 2},
+	{"case_type", 1},
+	{"filing_date", 1},
+	{"next_hearing", 1},
+	{"status", 1},
+	{"judge", 1},
+}
+
+// strategyReliability discounts fields read by strategies that guess more
+var strategyReliability = map[string]float64{
+	StrategyTable:   1,
+	StrategyDivs:    0.9,
+	StrategyText:    0.6,
+	StrategyHistory: 1,
+	StrategyOrders:  0.8,
+}
+
+// caseFieldValues flattens the scalar fields of a case for comparison
+func caseFieldValues(caseInfo *database.CaseInfo) map[string]string {
+	values := map[string]string{
+		"case_number":   caseInfo.CaseNumber,
+		"cnr":           caseInfo.CNR,
+		"case_type":     caseInfo.CaseType,
+		"filing_year":   caseInfo.FilingYear,
+		"status":        caseInfo.Status,
+		"judge":         caseInfo.Judge,
+		"court_complex": caseInfo.CourtComplex,
+	}
+	if !caseInfo.FilingDate.IsZero() {
+		values["filing_date"] = caseInfo.FilingDate.Format("2006-01-02")
+	}
+	if !caseInfo.NextHearing.IsZero() {
+		values["next_hearing"] = caseInfo.NextHearing.Format("2006-01-02")
+	}
+	return values
+}
+
+// trackStrategy runs parse and credits strategy with every field it set or changed
+func trackStrategy(report *database.ParseReport, caseInfo *database.CaseInfo, strategy string, parse func()) {
+	before := caseFieldValues(caseInfo)
+	parse()
+	for field, value := range caseFieldValues(caseInfo) {
+		if value != "" && value != before[field] {
+			report.Fields[field] = strategy
+		}
+	}
+}
+
+// scoreParseReport fills in the missing fields and the confidence score
+func scoreParseReport(report *database.ParseReport) {
+	report.Missing = []string{}
+
+	var total, score float64
+	for _, field := range expectedFields {
+		total += field.weight
+		strategy, found := report.Fields[field.name]
+		if !found {
+			report.Missing = append(report.Missing, field.name)
+			continue
+		}
+		score += field.weight * strategyReliability[strategy]
+	}
+
+	report.Confidence = math.Round(score/total*100) / 100
+}
@@ -2,7 +2,9 @@ package scraper
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -10,8 +12,11 @@ import (
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/launcher"
 	"github.com/go-rod/rod/lib/proto"
+	"github.com/JustJay7/court-data-fetcher/internal/caseid"
 	"github.com/JustJay7/court-data-fetcher/internal/config"
 	"github.com/JustJay7/court-data-fetcher/internal/database"
+	"github.com/JustJay7/court-data-fetcher/internal/ordertype"
+	"github.com/JustJay7/court-data-fetcher/internal/upstream"
 	"github.com/JustJay7/court-data-fetcher/pkg/logger"
 )
 
@@ -21,11 +26,26 @@ type Scraper struct {
 	Browser  *rod.Browser // Made public for testing
 	mu       sync.Mutex
 	logger   *logger.Logger
-	sessions map[string]*rod.Page
+	// classifier labels scraped orders, see config.OrderRulesFile
+	classifier *ordertype.Classifier
+	// upstream picks the proxy for each page and spaces out navigations
+	upstream *upstream.Upstream
+	// contexts holds a browser context per proxy, pageProxies the proxy
+	// each open page browses through and pageAuth stops the proxy
+	// authentication handler of each page that has one
+	contexts    map[*upstream.Proxy]proto.BrowserBrowserContextID
+	pageProxies map[*rod.Page]*upstream.Proxy
+	pageAuth    map[*rod.Page]context.CancelFunc
 }
 
-// NewScraper creates a new scraper instance
-func NewScraper(cfg *config.Config, logger *logger.Logger) (*Scraper, error) {
+// NewScraper creates a new scraper instance whose pages browse through the
+// proxies of up
+func NewScraper(cfg *config.Config, up *upstream.Upstream, logger *logger.Logger) (*Scraper, error) {
+	classifier, err := ordertype.LoadFile(cfg.OrderRulesFile)
+	if err != nil {
+		return nil, err
+	}
+
 	// Configure launcher with proper options
 	l := launcher.New().
 		Headless(cfg.HeadlessMode).
@@ -52,20 +72,155 @@ func NewScraper(cfg *config.Config, logger *logger.Logger) (*Scraper, error) {
 	browser := rod.New().ControlURL(browserURL).MustConnect()
 
 	return &Scraper{
-		cfg:      cfg,
-		Browser:  browser,
-		logger:   logger,
-		sessions: make(map[string]*rod.Page),
+		cfg:         cfg,
+		Browser:     browser,
+		logger:      logger,
+		classifier:  classifier,
+		upstream:    up,
+		contexts:    make(map[*upstream.Proxy]proto.BrowserBrowserContextID),
+		pageProxies: make(map[*rod.Page]*upstream.Proxy),
+		pageAuth:    make(map[*rod.Page]context.CancelFunc),
 	}, nil
 }
 
+// newPage opens a page browsing through the next proxy, in a browser
+// context created for that proxy on first use. Pages must be closed with
+// closePage. s.mu must be held.
+func (s *Scraper) newPage() (*rod.Page, *upstream.Proxy, error) {
+	proxy := s.upstream.Proxies().Next()
+	if proxy == nil {
+		page, err := s.Browser.Page(proto.TargetCreateTarget{})
+		return page, nil, err
+	}
+
+	contextID, ok := s.contexts[proxy]
+	if !ok {
+		res, err := proto.TargetCreateBrowserContext{ProxyServer: proxy.Server()}.Call(s.Browser)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create browser context for %s: %w", proxy, err)
+		}
+		contextID = res.BrowserContextID
+		s.contexts[proxy] = contextID
+	}
+	page, err := s.Browser.Page(proto.TargetCreateTarget{BrowserContextID: contextID})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stop, err := handleProxyAuth(page, proxy)
+	if err != nil {
+		page.Close()
+		return nil, nil, err
+	}
+	s.pageProxies[page] = proxy
+	if stop != nil {
+		s.pageAuth[page] = stop
+	}
+	return page, proxy, nil
+}
+
+// handleProxyAuth answers the proxy's authentication challenges on page with
+// the credentials in its URL, which Chromium doesn't read from the proxy
+// setting. Catching the challenges pauses every request, so each one is
+// continued unchanged. The returned function stops the handler, and is nil
+// for proxies without credentials.
+func handleProxyAuth(page *rod.Page, proxy *upstream.Proxy) (context.CancelFunc, error) {
+	if proxy.URL.User == nil {
+		return nil, nil
+	}
+	username := proxy.URL.User.Username()
+	password, _ := proxy.URL.User.Password()
+
+	if err := (proto.FetchEnable{HandleAuthRequests: true}).Call(page); err != nil {
+		return nil, fmt.Errorf("failed to handle authentication for %s: %w", proxy, err)
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+	go page.Context(ctx).EachEvent(
+		func(e *proto.FetchRequestPaused) {
+			_ = proto.FetchContinueRequest{RequestID: e.RequestID}.Call(page)
+		},
+		func(e *proto.FetchAuthRequired) {
+			// Only the proxy's challenges are answered, the court's own get
+			// the browser's default handling
+			response := &proto.FetchAuthChallengeResponse{
+				Response: proto.FetchAuthChallengeResponseResponseDefault,
+			}
+			if e.AuthChallenge != nil && e.AuthChallenge.Source == proto.FetchAuthChallengeSourceProxy {
+				response = &proto.FetchAuthChallengeResponse{
+					Response: proto.FetchAuthChallengeResponseResponseProvideCredentials,
+					Username: username,
+					Password: password,
+				}
+			}
+			_ = proto.FetchContinueWithAuth{RequestID: e.RequestID, AuthChallengeResponse: response}.Call(page)
+		},
+	)()
+	return stop, nil
+}
+
+// closePage closes a page from newPage and forgets its proxy. s.mu must be
+// held.
+func (s *Scraper) closePage(page *rod.Page) error {
+	if stop, ok := s.pageAuth[page]; ok {
+		stop()
+		delete(s.pageAuth, page)
+	}
+	delete(s.pageProxies, page)
+	return page.Close()
+}
+
+// navigate loads pageURL once the host's rate limit allows, resting the
+// page's proxy if it couldn't connect. A deadline or cancellation is the
+// caller's doing and leaves the proxy in the rotation.
+func (s *Scraper) navigate(ctx context.Context, page *rod.Page, pageURL string) error {
+	if err := s.upstream.Wait(ctx, pageURL); err != nil {
+		return err
+	}
+	err := page.Context(ctx).Navigate(pageURL)
+	if err != nil && ctx.Err() == nil && isConnectionFailure(err) {
+		s.upstream.ProxyFailed(s.pageProxy(page), err)
+	}
+	return err
+}
+
+// connectionFailures are the parts of Chromium's network error names that
+// mean the page couldn't be fetched through the proxy at all
+var connectionFailures = []string{"PROXY", "TUNNEL", "SOCKS", "CONNECTION", "TIMED_OUT", "EMPTY_RESPONSE"}
+
+// isConnectionFailure reports whether a navigation failed to connect, as
+// opposed to the court answering with an error or the page being stopped
+func isConnectionFailure(err error) bool {
+	var navErr *rod.ErrNavigation
+	if !errors.As(err, &navErr) {
+		return false
+	}
+	for _, failure := range connectionFailures {
+		if strings.Contains(navErr.Reason, failure) {
+			return true
+		}
+	}
+	return false
+}
+
+// pageProxy returns the proxy page browses through, nil when direct
+func (s *Scraper) pageProxy(page *rod.Page) *upstream.Proxy {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pageProxies[page]
+}
+
 // Close closes the browser and all pages
 func (s *Scraper) Close() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	for _, page := range s.sessions {
-		page.MustClose()
+	for page, stop := range s.pageAuth {
+		stop()
+		delete(s.pageAuth, page)
+	}
+	for page := range s.pageProxies {
+		delete(s.pageProxies, page)
 	}
 
 	return s.Browser.Close()
@@ -73,14 +228,42 @@ func (s *Scraper) Close() error {
 
 // SearchCase searches for a case and returns the parsed information
 func (s *Scraper) SearchCase(ctx context.Context, caseType, caseNumber, filingYear string) (*database.CaseInfo, string, error) {
+	if err := ValidateQuery(CaseQuery{CaseType: caseType, CaseNumber: caseNumber, FilingYear: filingYear}); err != nil {
+		return nil, "", err
+	}
+
+	// Each search gets its own page, so concurrent searches don't fill in
+	// the same form and each takes its turn in the proxy rotation
 	s.mu.Lock()
-	page, err := s.getOrCreatePage(ctx)
+	page, err := s.openSearchPage()
 	s.mu.Unlock()
 
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to create page: %w", err)
+		return nil, "", newScrapeError(ErrCourtUnavailable, "create page", err)
+	}
+	defer func() {
+		s.mu.Lock()
+		s.closePage(page)
+		s.mu.Unlock()
+	}()
+
+	if !s.cfg.DebugCapture {
+		return s.searchCase(ctx, page, caseType, caseNumber, filingYear)
 	}
 
+	// Record the session so a failure can be diagnosed afterwards
+	recorder := recordSession(page)
+	caseInfo, html, err := s.searchCase(ctx, page, caseType, caseNumber, filingYear)
+	if shouldCapture(err) {
+		err = WithDebugBundle(err, recorder.capture(page))
+	} else {
+		recorder.stop()
+	}
+	return caseInfo, html, err
+}
+
+// searchCase fills in and submits the court form on page
+func (s *Scraper) searchCase(ctx context.Context, page *rod.Page, caseType, caseNumber, filingYear string) (*database.CaseInfo, string, error) {
 	// Create a timeout context
 	searchCtx, cancel := context.WithTimeout(ctx, s.cfg.ScraperTimeout)
 	defer cancel()
@@ -93,10 +276,10 @@ func (s *Scraper) SearchCase(ctx context.Context, caseType, caseNumber, filingYe
 	navCtx, navCancel := context.WithTimeout(searchCtx, 15*time.Second)
 	defer navCancel()
 	
-	err = page.Context(navCtx).Navigate(courtURL)
+	err := s.navigate(navCtx, page, courtURL)
 	if err != nil {
 		s.logger.Error("Navigation failed", "url", courtURL, "error", err)
-		return nil, "", fmt.Errorf("failed to navigate: %w", err)
+		return nil, "", newScrapeError(ErrCourtUnavailable, "navigate", err)
 	}
 	s.logger.Debug("Navigation successful, waiting for page load")
 
@@ -112,8 +295,9 @@ func (s *Scraper) SearchCase(ctx context.Context, caseType, caseNumber, filingYe
 	s.logger.Debug("Page loaded, checking for elements")
 
 	// Get page info
-	info := page.MustInfo()
-	s.logger.Info("Page info", "url", info.URL)
+	if info, err := page.Info(); err == nil {
+		s.logger.Info("Page info", "url", info.URL)
+	}
 
 	// Wait a bit for JavaScript to load
 	time.Sleep(3 * time.Second)
@@ -129,9 +313,11 @@ func (s *Scraper) SearchCase(ctx context.Context, caseType, caseNumber, filingYe
 	if err != nil {
 		s.logger.Error("Case type select not found", "error", err)
 		html, _ := page.HTML()
-		return nil, html, fmt.Errorf("case type select not found: %w", err)
+		return nil, html, newScrapeError(ErrLayoutChanged, "find case type select", err)
+	}
+	if err := s.selectCaseType(page, caseTypeSelect, caseType); err != nil {
+		return nil, "", newScrapeError(ErrInvalidInput, "select case type", err)
 	}
-	caseTypeSelect.MustSelect(caseType)
 	s.logger.Debug("Selected case type", "type", caseType)
 	time.Sleep(1 * time.Second)
 	
@@ -141,9 +327,11 @@ func (s *Scraper) SearchCase(ctx context.Context, caseType, caseNumber, filingYe
 	if err != nil {
 		s.logger.Error("Case number input not found", "error", err)
 		html, _ := page.HTML()
-		return nil, html, fmt.Errorf("case number input not found: %w", err)
+		return nil, html, newScrapeError(ErrLayoutChanged, "find case number input", err)
+	}
+	if err := caseNumberInput.Input(caseNumber); err != nil {
+		return nil, "", newScrapeError(ErrLayoutChanged, "enter case number", err)
 	}
-	caseNumberInput.MustInput(caseNumber)
 	s.logger.Debug("Entered case number", "number", caseNumber)
 	time.Sleep(1 * time.Second)
 	
@@ -153,9 +341,11 @@ func (s *Scraper) SearchCase(ctx context.Context, caseType, caseNumber, filingYe
 	if err != nil {
 		s.logger.Error("Year select not found", "error", err)
 		html, _ := page.HTML()
-		return nil, html, fmt.Errorf("year select not found: %w", err)
+		return nil, html, newScrapeError(ErrLayoutChanged, "find year select", err)
+	}
+	if err := yearSelect.Select([]string{filingYear}, true, rod.SelectorTypeText); err != nil {
+		return nil, "", newScrapeError(ErrInvalidInput, "select year", err)
 	}
-	yearSelect.MustSelect(filingYear)
 	s.logger.Debug("Selected year", "year", filingYear)
 	time.Sleep(1 * time.Second)
 	
@@ -170,7 +360,9 @@ func (s *Scraper) SearchCase(ctx context.Context, caseType, caseNumber, filingYe
 		// Enter CAPTCHA
 		captchaInput, err := page.Element("#captchaInput")
 		if err == nil {
-			captchaInput.MustInput(captchaText)
+			if err := captchaInput.Input(captchaText); err != nil {
+				return nil, "", newScrapeError(ErrLayoutChanged, "enter captcha", err)
+			}
 			s.logger.Debug("Entered CAPTCHA", "code", captchaText)
 			time.Sleep(1 * time.Second)
 		} else {
@@ -182,7 +374,7 @@ func (s *Scraper) SearchCase(ctx context.Context, caseType, caseNumber, filingYe
 
 	// Handle CAPTCHA before submission
 	if err := s.handleCaptcha(page); err != nil {
-		return nil, "", fmt.Errorf("captcha handling failed: %w", err)
+		return nil, "", newScrapeError(ErrCaptchaFailed, "handle captcha", err)
 	}
 
 	// Submit form
@@ -191,68 +383,125 @@ func (s *Scraper) SearchCase(ctx context.Context, caseType, caseNumber, filingYe
 	if err != nil {
 		s.logger.Error("Submit button not found", "error", err)
 		html, _ := page.HTML()
-		return nil, html, fmt.Errorf("submit button not found: %w", err)
+		return nil, html, newScrapeError(ErrLayoutChanged, "find submit button", err)
 	}
 	
 	s.logger.Debug("Clicking submit button")
-	submitBtn.MustClick()
+	if err := s.upstream.Wait(searchCtx, courtURL); err != nil {
+		return nil, "", newScrapeError(ErrTimeout, "wait to submit", err)
+	}
+	if err := submitBtn.Context(searchCtx).Click(proto.InputMouseButtonLeft, 1); err != nil {
+		return nil, "", newScrapeError(ErrLayoutChanged, "submit search", err)
+	}
 	
 	// Wait for results
 	s.logger.Debug("Waiting for results after submission")
-	time.Sleep(5 * time.Second)
+	time.Sleep(7 * time.Second)
 
-	// Wait for results
-	page.MustWaitNavigation()
-	time.Sleep(2 * time.Second)
+	// Get page HTML for logging
+	html, _ := page.HTML()
 
 	// Check for errors
-	errorMsg := s.checkForErrors(page)
-	if errorMsg != "" {
-		return nil, "", fmt.Errorf("search error: %s", errorMsg)
+	if err := s.checkForErrors(page); err != nil {
+		return nil, html, err
 	}
 
-	// Get page HTML for logging
-	html, _ := page.HTML()
-
 	// Parse results
-	caseInfo, err := s.parseResults(page)
+	caseInfo, err := s.parseResults(searchCtx, page)
 	if err != nil {
-		return nil, html, fmt.Errorf("failed to parse results: %w", err)
+		return nil, html, err
+	}
+
+	// Keep the details page the case was parsed from rather than the results
+	// list, so the stored page can be replayed through the parser
+	if detailsHTML, err := page.HTML(); err == nil {
+		html = detailsHTML
 	}
 
 	// Try to fetch additional details
-	if err := s.fetchAdditionalDetails(page, caseInfo); err != nil {
+	if err := s.fetchAdditionalDetails(searchCtx, page, caseInfo); err != nil {
 		s.logger.Warn("Failed to fetch additional details", "error", err)
 	}
 
 	return caseInfo, html, nil
 }
 
-// getOrCreatePage gets an existing page or creates a new one
-func (s *Scraper) getOrCreatePage(ctx context.Context) (*rod.Page, error) {
-	sessionID := fmt.Sprintf("session_%d", time.Now().Unix())
-	
-	if page, exists := s.sessions[sessionID]; exists {
-		return page, nil
+// FetchCaseTypes reads the case types offered by the court's search form
+func (s *Scraper) FetchCaseTypes(ctx context.Context) ([]database.CaseType, error) {
+	fetchCtx, cancel := context.WithTimeout(ctx, s.cfg.ScraperTimeout)
+	defer cancel()
+
+	s.mu.Lock()
+	page, _, err := s.newPage()
+	s.mu.Unlock()
+	if err != nil {
+		return nil, newScrapeError(ErrCourtUnavailable, "create page", err)
+	}
+	defer func() {
+		s.mu.Lock()
+		s.closePage(page)
+		s.mu.Unlock()
+	}()
+
+	courtURL := s.cfg.CourtBaseURL + "/app/get-case-type-status"
+	if err := s.navigate(fetchCtx, page, courtURL); err != nil {
+		return nil, newScrapeError(ErrCourtUnavailable, "navigate", err)
+	}
+	if err := page.Context(fetchCtx).WaitLoad(); err != nil {
+		return nil, newScrapeError(ErrTimeout, "wait for form", err)
 	}
 
-	page, err := s.Browser.Page(proto.TargetCreateTarget{})
+	html, err := page.HTML()
 	if err != nil {
-		return nil, err
+		return nil, newScrapeError(ErrCourtUnavailable, "read form", err)
+	}
+	types, err := NewParser(s.logger).ParseCaseTypesHTML(html)
+	if err != nil {
+		return nil, newScrapeError(ErrLayoutChanged, "read case types", err)
 	}
+	return types, nil
+}
 
-	// Set viewport
-	page.MustSetViewport(1920, 1080, 1, false)
+// selectCaseType picks caseType in the form's dropdown by its option value.
+// rod's text selector matches substrings, so selecting "CS" by label could
+// pick "CS(COMM)".
+func (s *Scraper) selectCaseType(page *rod.Page, caseTypeSelect *rod.Element, caseType string) error {
+	html, err := page.HTML()
+	if err != nil {
+		return fmt.Errorf("failed to read form: %w", err)
+	}
+	types, err := NewParser(s.logger).ParseCaseTypesHTML(html)
+	if err != nil {
+		return err
+	}
+	value, ok := CaseTypeValue(types, caseType)
+	if !ok {
+		return fmt.Errorf("case type %q is not offered by the form", caseType)
+	}
+	return caseTypeSelect.Select([]string{"option[value=" + strconv.Quote(value) + "]"}, true, rod.SelectorTypeCSSSector)
+}
 
-	// Set extra headers to appear more human-like
-	page.MustSetExtraHeaders("Accept-Language", "en-US,en;q=0.9")
+// openSearchPage opens a page for one search, set up to look like an
+// ordinary visitor. It must be closed with closePage. s.mu must be held.
+func (s *Scraper) openSearchPage() (*rod.Page, error) {
+	page, _, err := s.newPage()
+	if err != nil {
+		return nil, err
+	}
 
-	s.sessions[sessionID] = page
+	err = page.SetViewport(&proto.EmulationSetDeviceMetricsOverride{Width: 1920, Height: 1080, DeviceScaleFactor: 1})
+	if err == nil {
+		_, err = page.SetExtraHeaders([]string{"Accept-Language", "en-US,en;q=0.9"})
+	}
+	if err != nil {
+		s.closePage(page)
+		return nil, err
+	}
 	return page, nil
 }
 
-// checkForErrors checks for error messages on the page
-func (s *Scraper) checkForErrors(page *rod.Page) string {
+// checkForErrors checks for error messages on the page and classifies them
+func (s *Scraper) checkForErrors(page *rod.Page) error {
 	// Check for common error messages
 	errorSelectors := []string{
 		"div.error",
@@ -266,7 +515,7 @@ func (s *Scraper) checkForErrors(page *rod.Page) string {
 		if err == nil && elem != nil {
 			text, _ := elem.Text()
 			if text != "" {
-				return text
+				return ClassifyCourtMessage(text)
 			}
 		}
 	}
@@ -279,38 +528,96 @@ func (s *Scraper) checkForErrors(page *rod.Page) string {
 		if strings.Contains(lowerText, "no record") || 
 		   strings.Contains(lowerText, "not found") ||
 		   strings.Contains(lowerText, "invalid case") {
-			return "No records found for the given case details"
+			return &ScrapeError{Kind: ErrCaseNotFound, Op: "search"}
 		}
 	}
 
-	return ""
+	return nil
+}
+
+// ClassifyCourtMessage maps an error message shown by the court to a ScrapeError
+func ClassifyCourtMessage(text string) error {
+	lowerText := strings.ToLower(text)
+	// Messages that aren't recognised are taken to be the court's trouble,
+	// such as "server busy, try later", not the user's input
+	kind := ErrCourtUnavailable
+
+	switch {
+	case strings.Contains(lowerText, "captcha"):
+		kind = ErrCaptchaFailed
+	case strings.Contains(lowerText, "no record") ||
+		strings.Contains(lowerText, "not found") ||
+		strings.Contains(lowerText, "invalid case"):
+		kind = ErrCaseNotFound
+	case isValidationMessage(lowerText):
+		kind = ErrInvalidInput
+	}
+
+	return &ScrapeError{Kind: kind, Op: "search", Err: errors.New(strings.TrimSpace(text))}
 }
 
-// parseResults parses the case information from the results page
-func (s *Scraper) parseResults(page *rod.Page) (*database.CaseInfo, error) {
+// validationPhrases appear in the court's messages about the form's fields
+var validationPhrases = []string{"please enter", "please select", "is required", "invalid", "enter a valid", "enter valid", "must be"}
+
+func isValidationMessage(lowerText string) bool {
+	for _, phrase := range validationPhrases {
+		if strings.Contains(lowerText, phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseResults opens the case from the results page and parses its details.
+// Missing elements are reported as layout changes rather than waited for.
+func (s *Scraper) parseResults(ctx context.Context, page *rod.Page) (*database.CaseInfo, error) {
 	parser := NewParser(s.logger)
+	page = page.Context(ctx)
 	
 	// First check if we're on the case details page
 	// Delhi District Courts shows results in a table format
-	resultsTable, err := page.Element("table.table")
+	has, resultsTable, err := page.Has("table.table")
 	if err != nil {
-		return nil, fmt.Errorf("no results table found")
+		return nil, newScrapeError(ErrLayoutChanged, "find results table", err)
+	}
+	if !has {
+		return nil, &ScrapeError{Kind: ErrCaseNotFound, Op: "find results table"}
 	}
 
 	// Click on View button to get full details
-	viewBtn := resultsTable.MustElement("a[href*='view']")
-	if viewBtn != nil {
-		viewBtn.MustClick()
-		page.MustWaitNavigation()
+	has, viewBtn, err := resultsTable.Has("a[href*='view']")
+	if err != nil {
+		return nil, newScrapeError(ErrLayoutChanged, "find view link", err)
+	}
+	if has {
+		// Start listening before the click so a fast navigation isn't missed
+		wait := page.WaitNavigation(proto.PageLifecycleEventNameNetworkAlmostIdle)
+		if err := viewBtn.Click(proto.InputMouseButtonLeft, 1); err != nil {
+			return nil, newScrapeError(ErrLayoutChanged, "open case details", err)
+		}
+		wait()
+		if err := ctx.Err(); err != nil {
+			return nil, newScrapeError(ErrTimeout, "open case details", err)
+		}
 	}
 
-	return parser.ParseCaseDetails(page)
+	caseInfo, err := parser.ParseCaseDetails(page)
+	if err != nil {
+		return nil, newScrapeError(ErrLayoutChanged, "parse results", err)
+	}
+	return caseInfo, nil
 }
 
-// fetchAdditionalDetails fetches order details and other information
-func (s *Scraper) fetchAdditionalDetails(page *rod.Page, caseInfo *database.CaseInfo) error {
+// fetchAdditionalDetails fetches order details and other information, giving
+// up when ctx is done
+func (s *Scraper) fetchAdditionalDetails(ctx context.Context, page *rod.Page, caseInfo *database.CaseInfo) error {
+	page = page.Context(ctx)
+
 	// Look for Orders/Judgments tab
-	links := page.MustElements("a")
+	links, err := page.Elements("a")
+	if err != nil {
+		return newScrapeError(ErrLayoutChanged, "find orders tab", err)
+	}
 	var ordersTab *rod.Element
 	
 	for _, link := range links {
@@ -322,16 +629,34 @@ func (s *Scraper) fetchAdditionalDetails(page *rod.Page, caseInfo *database.Case
 	}
 
 	if ordersTab != nil {
-		ordersTab.MustClick()
-		page.MustWaitNavigation()
+		if info, err := page.Info(); err == nil {
+			if err := s.upstream.Wait(ctx, info.URL); err != nil {
+				return newScrapeError(ErrTimeout, "wait to open orders", err)
+			}
+		}
+		wait := page.WaitNavigation(proto.PageLifecycleEventNameNetworkAlmostIdle)
+		if err := ordersTab.Click(proto.InputMouseButtonLeft, 1); err != nil {
+			return newScrapeError(ErrLayoutChanged, "open orders", err)
+		}
+		wait()
+		if err := ctx.Err(); err != nil {
+			return newScrapeError(ErrTimeout, "open orders", err)
+		}
 
 		// Parse orders
 		parser := NewParser(s.logger)
-		orders, err := parser.ParseOrders(page)
+		parser.SetClassifier(s.classifier)
+		orders, err := parser.ParseOrders(page, caseInfo.ParseReport)
 		if err != nil {
 			return err
 		}
 		caseInfo.Orders = orders
+
+		// The details page often leaves the next date blank when the
+		// latest order says when the matter is listed
+		if parser.InferNextHearing(caseInfo) {
+			s.logger.Info("Next hearing read from orders", "case", caseInfo.CaseNumber, "direction", caseInfo.NextHearingNote)
+		}
 	}
 
 	return nil
@@ -375,6 +700,15 @@ type CaseQuery struct {
 	FilingYear string
 }
 
+// ValidateQuery checks that a query has every field the court form needs
+// and that each is well formed, see caseid.New
+func ValidateQuery(q CaseQuery) error {
+	if _, err := caseid.New(q.CaseType, q.CaseNumber, q.FilingYear); err != nil {
+		return &ScrapeError{Kind: ErrInvalidInput, Op: "validate", Err: err}
+	}
+	return nil
+}
+
 // CaseResult represents the result of a case search
 type CaseResult struct {
 	Query    CaseQuery
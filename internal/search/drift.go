@@ -0,0 +1,91 @@
+package search
+
+import (
+	"sync"
+
+	"github.com/JustJay7/court-data-fetcher/pkg/logger"
+)
+
+// DriftMonitor keeps the parse confidence of the most recent scrapes and
+// warns once when their average falls below the threshold, which usually
+// means the court changed its page layout. It warns again only after the
+// average has recovered and dropped once more.
+type DriftMonitor struct {
+	mu        sync.Mutex
+	threshold float64
+	recent    []float64 // ring buffer of the last len(recent) scores
+	next      int
+	count     int
+	drifting  bool
+	alerts    int64
+	logger    *logger.Logger
+}
+
+// NewDriftMonitor averages over window scrapes. A window below 1 disables it.
+func NewDriftMonitor(threshold float64, window int, logger *logger.Logger) *DriftMonitor {
+	if window < 0 {
+		window = 0
+	}
+	return &DriftMonitor{
+		threshold: threshold,
+		recent:    make([]float64, window),
+		logger:    logger,
+	}
+}
+
+// Observe records the confidence of one scrape
+func (d *DriftMonitor) Observe(confidence float64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(d.recent) == 0 {
+		return
+	}
+	d.recent[d.next] = confidence
+	d.next = (d.next + 1) % len(d.recent)
+	if d.count < len(d.recent) {
+		d.count++
+	}
+
+	// Judge only a full window so a single bad page at startup doesn't alert
+	if d.count < len(d.recent) {
+		return
+	}
+	average := d.average()
+
+	switch {
+	case average < d.threshold && !d.drifting:
+		d.drifting = true
+		d.alerts++
+		d.logger.Warn("Parse confidence dropped below threshold, the court page layout may have changed",
+			"average_confidence", average,
+			"threshold", d.threshold,
+			"scrapes", d.count,
+		)
+	case average >= d.threshold && d.drifting:
+		d.drifting = false
+		d.logger.Info("Parse confidence recovered",
+			"average_confidence", average,
+			"threshold", d.threshold,
+		)
+	}
+}
+
+// Status returns the recent average, whether it is below the threshold and
+// how many times it has dropped there
+func (d *DriftMonitor) Status() (average float64, drifting bool, alerts int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.average(), d.drifting, d.alerts
+}
+
+func (d *DriftMonitor) average() float64 {
+	if d.count == 0 {
+		return 0
+	}
+	var sum float64
+	for i := 0; i < d.count; i++ {
+		sum += d.recent[i]
+	}
+	return sum / float64(d.count)
+}
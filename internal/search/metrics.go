@@ -0,0 +1,79 @@
+package search
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/JustJay7/court-data-fetcher/internal/scraper"
+)
+
+// Metrics counts searches and their outcomes across every entry point
+type Metrics struct {
+	searches      atomic.Int64
+	cacheHits     atomic.Int64
+	negativeHits  atomic.Int64
+	scrapes       atomic.Int64
+	coalesced     atomic.Int64
+	revalidations atomic.Int64
+	failures      atomic.Int64
+	scrapeNanos   atomic.Int64
+
+	mu             sync.Mutex
+	failuresByCode map[string]int64
+}
+
+// MetricsSnapshot is a point-in-time copy of Metrics
+type MetricsSnapshot struct {
+	Searches         int64            `json:"searches"`
+	CacheHits        int64            `json:"cache_hits"`
+	NegativeHits     int64            `json:"negative_cache_hits"`
+	Scrapes          int64            `json:"scrapes"`
+	Coalesced        int64            `json:"coalesced"`
+	Revalidations    int64            `json:"revalidations"`
+	Failures         int64            `json:"failures"`
+	FailuresByCode   map[string]int64 `json:"failures_by_code"`
+	AvgScrapeSeconds float64          `json:"avg_scrape_seconds"`
+	// ParseConfidence averages the parse confidence of recent scrapes, and
+	// LayoutDrift is set while it is below PARSE_CONFIDENCE_THRESHOLD
+	ParseConfidence   float64 `json:"parse_confidence"`
+	LayoutDrift       bool    `json:"layout_drift"`
+	LayoutDriftAlerts int64   `json:"layout_drift_alerts"`
+}
+
+func newMetrics() *Metrics {
+	return &Metrics{failuresByCode: make(map[string]int64)}
+}
+
+func (m *Metrics) recordFailure(err error) {
+	m.failures.Add(1)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failuresByCode[scraper.ErrorCode(err)]++
+}
+
+// Snapshot copies the current counter values
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	snapshot := MetricsSnapshot{
+		Searches:      m.searches.Load(),
+		CacheHits:     m.cacheHits.Load(),
+		NegativeHits:  m.negativeHits.Load(),
+		Scrapes:       m.scrapes.Load(),
+		Coalesced:     m.coalesced.Load(),
+		Revalidations: m.revalidations.Load(),
+		Failures:      m.failures.Load(),
+	}
+	if snapshot.Scrapes > 0 {
+		snapshot.AvgScrapeSeconds = (time.Duration(m.scrapeNanos.Load()) / time.Duration(snapshot.Scrapes)).Seconds()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot.FailuresByCode = make(map[string]int64, len(m.failuresByCode))
+	for code, count := range m.failuresByCode {
+		snapshot.FailuresByCode[code] = count
+	}
+	return snapshot
+}
@@ -0,0 +1,358 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/JustJay7/court-data-fetcher/internal/cache"
+	"github.com/JustJay7/court-data-fetcher/internal/caseid"
+	"github.com/JustJay7/court-data-fetcher/internal/catalogue"
+	"github.com/JustJay7/court-data-fetcher/internal/config"
+	"github.com/JustJay7/court-data-fetcher/internal/database"
+	"github.com/JustJay7/court-data-fetcher/internal/scraper"
+	"github.com/JustJay7/court-data-fetcher/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// Where a search came from, recorded in QueryLog.Source
+const (
+	SourceForm       = "form"
+	SourceAPI        = "api"
+	SourceBulk       = "bulk"
+	SourceRevalidate = "revalidate"
+)
+
+// ErrScraperUnavailable is returned when the service was built without a browser
+var ErrScraperUnavailable = &scraper.ScrapeError{
+	Kind: scraper.ErrCourtUnavailable,
+	Op:   "scraper",
+	Err:  errors.New("scraper not initialised"),
+}
+
+// Request describes a single case search
+type Request struct {
+	CaseType   string `json:"case_type"`
+	CaseNumber string `json:"case_number"`
+	FilingYear string `json:"filing_year"`
+	IPAddress  string `json:"-"`
+	Source     string `json:"-"`
+	// Refresh bypasses cached results, including cached not-found results
+	Refresh bool `json:"-"`
+}
+
+// Result is the outcome of a successful search
+type Result struct {
+	CaseInfo  *database.CaseInfo
+	QueryLog  *database.QueryLog
+	FromCache bool
+	CachedAt  time.Time
+	// Stale is set when a cached result is being refreshed in the background
+	Stale bool
+	// Shared is set when the scrape was performed for another identical request
+	Shared bool
+}
+
+// BulkResult pairs a request with its outcome
+type BulkResult struct {
+	Request Request
+	Result  *Result
+	Error   error
+}
+
+// Service is the single entry point for case searches. It checks the cache,
+// coalesces identical scrapes, logs every scrape in QueryLog, persists
+// results and records metrics.
+type Service struct {
+	repo         *database.Repository
+	cache        cache.Cache
+	flight       *cache.Group
+	scraper      *scraper.Scraper
+	catalogue    *catalogue.Catalogue
+	logger       *logger.Logger
+	cfg          *config.Config
+	metrics      *Metrics
+	drift        *DriftMonitor
+	revalidating sync.Map
+}
+
+// NewService creates a search service. scraper may be nil, in which case
+// every scrape fails with ErrScraperUnavailable.
+func NewService(db *gorm.DB, cacheService cache.Cache, scraper *scraper.Scraper, logger *logger.Logger, cfg *config.Config) *Service {
+	var fetcher catalogue.Fetcher
+	if scraper != nil {
+		fetcher = scraper
+	}
+	return &Service{
+		repo:      database.NewRepository(db),
+		cache:     cacheService,
+		flight:    cache.NewGroup(),
+		scraper:   scraper,
+		catalogue: catalogue.New(db, fetcher, logger),
+		logger:    logger,
+		cfg:       cfg,
+		metrics:   newMetrics(),
+		drift:     NewDriftMonitor(cfg.ParseConfidenceThreshold, cfg.ParseConfidenceWindow, logger),
+	}
+}
+
+// Catalogue returns the case types searches are checked against
+func (s *Service) Catalogue() *catalogue.Catalogue {
+	return s.catalogue
+}
+
+// Metrics returns the service's counters
+func (s *Service) Metrics() MetricsSnapshot {
+	snapshot := s.metrics.Snapshot()
+	snapshot.ParseConfidence, snapshot.LayoutDrift, snapshot.LayoutDriftAlerts = s.drift.Status()
+	return snapshot
+}
+
+// Search returns case information from the cache or the court website
+func (s *Service) Search(ctx context.Context, req Request) (*Result, error) {
+	s.metrics.searches.Add(1)
+
+	// The court form wants the type as it lists it and the number without
+	// leading zeros, so every notation of a case is searched and cached alike
+	id, err := caseid.FromInput(req.CaseType, req.CaseNumber, req.FilingYear)
+	if err != nil {
+		err = &scraper.ScrapeError{Kind: scraper.ErrInvalidInput, Op: "validate", Err: err}
+		s.metrics.recordFailure(err)
+		return nil, err
+	}
+	// The court's dropdown is selected by label, an unknown type would
+	// only fail once the browser reaches the form
+	if id.Type, err = s.catalogue.Resolve(id.Type); err != nil {
+		if errors.Is(err, catalogue.ErrUnknownCaseType) {
+			err = &scraper.ScrapeError{Kind: scraper.ErrInvalidInput, Op: "validate", Err: err}
+		}
+		s.metrics.recordFailure(err)
+		return nil, err
+	}
+	req.CaseType, req.CaseNumber, req.FilingYear = id.Type, id.Number, id.Year
+
+	key := cache.CaseKey(id)
+
+	if !req.Refresh {
+		if caseInfo, cachedAt, found := s.cache.Lookup(key); found {
+			return s.cachedResult(key, req, caseInfo, cachedAt), nil
+		}
+	}
+
+	// Create query log entry BEFORE scraping
+	queryLog := &database.QueryLog{
+		CaseType:   req.CaseType,
+		CaseNumber: req.CaseNumber,
+		FilingYear: req.FilingYear,
+		QueryTime:  time.Now(),
+		IPAddress:  req.IPAddress,
+		Source:     req.Source,
+	}
+	if err := s.repo.CreateQueryLog(queryLog); err != nil {
+		s.logger.Error("Failed to create query log", "error", err)
+	}
+
+	// A recent search for the same case found nothing, don't ask the court again
+	if !req.Refresh && s.cache.IsNotFound(key) {
+		s.logger.Info("Negative cache hit", "key", key)
+		s.metrics.negativeHits.Add(1)
+		err := &scraper.ScrapeError{Kind: scraper.ErrCaseNotFound, Op: "cache"}
+		s.finishQuery(queryLog, "", err)
+		s.metrics.recordFailure(err)
+		return nil, err
+	}
+
+	caseInfo, rawHTML, err, shared := s.scrape(ctx, key, req, queryLog)
+	if shared {
+		s.logger.Info("Joined in-flight search", "key", key)
+	}
+
+	s.finishQuery(queryLog, rawHTML, err)
+	if err != nil {
+		s.metrics.recordFailure(err)
+		return nil, err
+	}
+
+	return &Result{
+		CaseInfo: caseInfo,
+		QueryLog: queryLog,
+		CachedAt: time.Now(),
+		Shared:   shared,
+	}, nil
+}
+
+// SearchBulk runs several searches concurrently, bounded by MaxConcurrentScrapes
+func (s *Service) SearchBulk(ctx context.Context, reqs []Request) []BulkResult {
+	results := make([]BulkResult, len(reqs))
+	var wg sync.WaitGroup
+
+	limit := s.cfg.MaxConcurrentScrapes
+	if limit < 1 {
+		limit = 1
+	}
+	semaphore := make(chan struct{}, limit)
+
+	for i, req := range reqs {
+		wg.Add(1)
+		go func(index int, r Request) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			result, err := s.Search(ctx, r)
+			results[index] = BulkResult{Request: r, Result: result, Error: err}
+		}(i, req)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// cachedResult builds a result for a cache hit, starting a background
+// refresh when the entry is stale
+func (s *Service) cachedResult(key string, req Request, caseInfo *database.CaseInfo, cachedAt time.Time) *Result {
+	s.logger.Info("Cache hit", "key", key)
+	s.metrics.cacheHits.Add(1)
+
+	stale := s.cfg.CacheStaleAfter > 0 && time.Since(cachedAt) > s.cfg.CacheStaleAfter
+	if stale {
+		s.revalidate(key, req)
+	}
+
+	// Load the query log for this cached case
+	queryLog := &database.QueryLog{}
+	if caseInfo.QueryLogID > 0 {
+		if found, err := s.repo.FindQueryLog(caseInfo.QueryLogID); err == nil {
+			queryLog = found
+		}
+	}
+
+	return &Result{
+		CaseInfo:  caseInfo,
+		QueryLog:  queryLog,
+		FromCache: true,
+		CachedAt:  cachedAt,
+		Stale:     stale,
+	}
+}
+
+// revalidate refreshes a cached case in the background, at most once per key
+func (s *Service) revalidate(key string, req Request) {
+	if _, running := s.revalidating.LoadOrStore(key, struct{}{}); running {
+		return
+	}
+	s.metrics.revalidations.Add(1)
+
+	req.Refresh = true
+	req.Source = SourceRevalidate
+
+	go func() {
+		defer s.revalidating.Delete(key)
+
+		if _, err := s.Search(context.Background(), req); err != nil {
+			s.logger.Warn("Background cache refresh failed", "key", key, "error", err)
+		}
+	}()
+}
+
+// scrape fetches a case from the court, sharing the work between concurrent
+// identical searches. Only the caller that runs the scrape persists and
+// caches its outcome, against its own query log.
+func (s *Service) scrape(ctx context.Context, key string, req Request, queryLog *database.QueryLog) (*database.CaseInfo, string, error, bool) {
+	v, err, shared := s.flight.Do(key, func() (interface{}, error) {
+		if s.scraper == nil {
+			return scrapeResult{}, ErrScraperUnavailable
+		}
+
+		s.metrics.scrapes.Add(1)
+		start := time.Now()
+
+		// Other requests may be waiting on this scrape, so it must not be
+		// cancelled when the caller that started it goes away
+		scrapeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.cfg.ScraperTimeout)
+		defer cancel()
+
+		caseInfo, rawHTML, err := s.scraper.SearchCase(scrapeCtx, req.CaseType, req.CaseNumber, req.FilingYear)
+		s.metrics.scrapeNanos.Add(int64(time.Since(start)))
+
+		switch {
+		case err == nil:
+			if caseInfo.ParseReport != nil {
+				s.drift.Observe(caseInfo.ParseReport.Confidence)
+			}
+			queryLog.RawResponse = rawHTML
+			// A case that wasn't saved isn't cached either, or later hits
+			// would point at a query log and records that don't exist
+			if saveErr := s.repo.SaveCaseSnapshot(queryLog, caseInfo); saveErr != nil {
+				s.logger.Error("Failed to save case snapshot", "error", saveErr)
+				err = fmt.Errorf("failed to save case snapshot: %w", saveErr)
+				break
+			}
+			s.cache.Set(key, caseInfo)
+		case errors.Is(err, scraper.ErrCaseNotFound):
+			s.cache.SetNotFound(key)
+		case errors.Is(err, scraper.ErrLayoutChanged):
+			// A page the parser can't read at all counts as zero confidence
+			s.drift.Observe(0)
+		}
+
+		if bundle := scraper.DebugBundleOf(err); bundle != nil {
+			if err := s.repo.SaveDebugArtifacts(queryLog.ID, debugArtifacts(bundle)); err != nil {
+				s.logger.Error("Failed to save debug artifacts", "error", err)
+			}
+		}
+
+		return scrapeResult{caseInfo: caseInfo, rawHTML: rawHTML}, err
+	})
+	if shared {
+		s.metrics.coalesced.Add(1)
+	}
+	var panicErr *cache.PanicError
+	if errors.As(err, &panicErr) && !shared {
+		s.logger.Error("Scrape panicked", "key", key, "panic", panicErr.Value, "stack", string(panicErr.Stack))
+	}
+
+	result, _ := v.(scrapeResult)
+	return result.caseInfo, result.rawHTML, err, shared
+}
+
+// scrapeResult carries a scrape outcome through the call group
+type scrapeResult struct {
+	caseInfo *database.CaseInfo
+	rawHTML  string
+}
+
+// finishQuery records the outcome of a search on its query log
+func (s *Service) finishQuery(queryLog *database.QueryLog, rawHTML string, err error) {
+	queryLog.RawResponse = rawHTML
+	queryLog.Success = err == nil
+	if err != nil {
+		queryLog.ErrorMessage = err.Error()
+		queryLog.ErrorCode = scraper.ErrorCode(err)
+	}
+
+	if err := s.repo.UpdateQueryLog(queryLog); err != nil {
+		s.logger.Error("Failed to update query log", "error", err)
+	}
+}
+
+// debugArtifacts splits a bundle into the files stored against a query log
+func debugArtifacts(bundle *scraper.DebugBundle) []database.DebugArtifact {
+	files := []database.DebugArtifact{
+		{Kind: database.ArtifactScreenshot, ContentType: "image/png", Data: bundle.Screenshot},
+		{Kind: database.ArtifactHAR, ContentType: "application/json", Data: bundle.HAR},
+		{Kind: database.ArtifactConsole, ContentType: "text/plain; charset=utf-8", Data: []byte(bundle.Console)},
+		{Kind: database.ArtifactDOM, ContentType: "text/html; charset=utf-8", Data: []byte(bundle.DOM)},
+	}
+
+	artifacts := make([]database.DebugArtifact, 0, len(files))
+	for _, file := range files {
+		if len(file.Data) > 0 {
+			artifacts = append(artifacts, file)
+		}
+	}
+	return artifacts
+}
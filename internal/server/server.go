@@ -12,8 +12,10 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/JustJay7/court-data-fetcher/internal/api"
 	"github.com/JustJay7/court-data-fetcher/internal/cache"
+	"github.com/JustJay7/court-data-fetcher/internal/catalogue"
 	"github.com/JustJay7/court-data-fetcher/internal/config"
 	"github.com/JustJay7/court-data-fetcher/internal/scraper"
+	"github.com/JustJay7/court-data-fetcher/internal/upstream"
 	"github.com/JustJay7/court-data-fetcher/pkg/logger"
 	"gorm.io/gorm"
 )
@@ -27,7 +29,7 @@ type Server struct {
 	scraper  *scraper.Scraper
 }
 
-func New(cfg *config.Config, db *gorm.DB, cache cache.Cache, logger *logger.Logger) *Server {
+func New(cfg *config.Config, db *gorm.DB, cache cache.Cache, up *upstream.Upstream, logger *logger.Logger) *Server {
 	if cfg.LogLevel == "debug" {
 		gin.SetMode(gin.DebugMode)
 	} else {
@@ -40,7 +42,7 @@ func New(cfg *config.Config, db *gorm.DB, cache cache.Cache, logger *logger.Logg
 	router.Use(loggingMiddleware(logger))
 	router.Use(corsMiddleware())
 
-	scraperInstance, err := scraper.NewScraper(cfg, logger)
+	scraperInstance, err := scraper.NewScraper(cfg, up, logger)
 	if err != nil {
 		logger.Fatal("Failed to initialize scraper", "error", err)
 	}
@@ -54,7 +56,12 @@ func New(cfg *config.Config, db *gorm.DB, cache cache.Cache, logger *logger.Logg
 		scraper: scraperInstance,
 	}
 
-	api.SetupRoutes(router, db, cache, scraperInstance, logger, cfg)
+	api.SetupRoutes(router, db, cache, scraperInstance, up, logger, cfg)
+
+	// Keep the case type dropdown in step with the court's form
+	if cfg.CaseTypesRefreshInterval > 0 {
+		go catalogue.New(db, scraperInstance, logger).Start(context.Background(), cfg.CaseTypesRefreshInterval)
+	}
 
 	return server
 }
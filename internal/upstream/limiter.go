@@ -0,0 +1,58 @@
+package upstream
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Limiter spaces out requests to each host by an interval plus a random
+// jitter, so fetches don't arrive at the court in a regular burst
+type Limiter struct {
+	interval time.Duration
+	jitter   time.Duration
+
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+// NewLimiter creates a limiter. With a zero interval and jitter requests
+// are never delayed.
+func NewLimiter(interval, jitter time.Duration) *Limiter {
+	return &Limiter{interval: interval, jitter: jitter, next: make(map[string]time.Time)}
+}
+
+// Wait blocks until a request to host may be sent, or ctx is done
+func (l *Limiter) Wait(ctx context.Context, host string) error {
+	if l.interval <= 0 && l.jitter <= 0 {
+		return nil
+	}
+
+	// Reserve the next free slot for the host, then wait for it
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next[host]
+	if slot.Before(now) {
+		slot = now
+	}
+	gap := l.interval
+	if l.jitter > 0 {
+		gap += time.Duration(rand.Int63n(int64(l.jitter)))
+	}
+	l.next[host] = slot.Add(gap)
+	l.mu.Unlock()
+
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
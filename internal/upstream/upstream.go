@@ -0,0 +1,164 @@
+// Package upstream routes requests to the court through the configured
+// proxies and spaces out requests to each host, for the browser and plain
+// HTTP fetches alike
+package upstream
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/JustJay7/court-data-fetcher/internal/config"
+	"github.com/JustJay7/court-data-fetcher/pkg/logger"
+)
+
+// Upstream is shared by everything that fetches from the court, so the
+// per-host limits hold across the browser, the PDF worker and the API
+type Upstream struct {
+	proxies *Pool
+	limiter *Limiter
+	logger  *logger.Logger
+}
+
+// New creates the upstream for cfg.Proxies and the per-host request
+// interval and jitter
+func New(cfg *config.Config, logger *logger.Logger) (*Upstream, error) {
+	pool, err := NewPool(cfg.Proxies, cfg.ProxyCooldown)
+	if err != nil {
+		return nil, err
+	}
+	return &Upstream{
+		proxies: pool,
+		limiter: NewLimiter(cfg.HostRequestInterval, cfg.HostRequestJitter),
+		logger:  logger,
+	}, nil
+}
+
+// Direct returns an upstream without proxies or limits
+func Direct() *Upstream {
+	pool, _ := NewPool(nil, 0)
+	return &Upstream{proxies: pool, limiter: NewLimiter(0, 0)}
+}
+
+// Proxies returns the proxy pool
+func (u *Upstream) Proxies() *Pool {
+	return u.proxies
+}
+
+// Wait blocks until a request to rawURL's host may be sent. Browser
+// navigations call it before loading a page.
+func (u *Upstream) Wait(ctx context.Context, rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	return u.limiter.Wait(ctx, parsed.Host)
+}
+
+// Client returns an HTTP client whose requests wait their turn for the host
+// and go out through the next healthy proxy
+func (u *Upstream) Client(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: &transport{upstream: u}}
+}
+
+// ClientVia is like Client but sends every request through proxy, for
+// requests that must share a browser session's address. A nil proxy
+// connects directly.
+func (u *Upstream) ClientVia(proxy *Proxy, timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: &transport{upstream: u, proxy: proxy, pinned: true}}
+}
+
+// transport applies the host limits and proxy rotation to each request
+type transport struct {
+	upstream *Upstream
+	proxy    *Proxy
+	pinned   bool
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.upstream.limiter.Wait(req.Context(), req.URL.Host); err != nil {
+		return nil, err
+	}
+
+	proxy := t.proxy
+	if !t.pinned {
+		proxy = t.upstream.proxies.Next()
+	}
+	if proxy == nil {
+		return http.DefaultTransport.RoundTrip(req)
+	}
+
+	resp, err := proxy.transport.RoundTrip(req)
+	switch {
+	case err != nil:
+		t.upstream.ProxyFailed(proxy, err)
+	case resp.StatusCode == http.StatusProxyAuthRequired:
+		t.upstream.ProxyFailed(proxy, fmt.Errorf("proxy returned %s", resp.Status))
+	}
+	return resp, err
+}
+
+// ProxyFailed rests a proxy after a request through it failed
+func (u *Upstream) ProxyFailed(proxy *Proxy, err error) {
+	if proxy == nil {
+		return
+	}
+	u.proxies.MarkFailed(proxy, err)
+	if u.logger != nil {
+		u.logger.Warn("Proxy failed, resting it", "proxy", proxy.String(), "error", err)
+	}
+}
+
+// CheckProxies requests target through every proxy, resting those that
+// fail and returning those that answer to the rotation. It returns how many
+// are healthy.
+func (u *Upstream) CheckProxies(ctx context.Context, target string) int {
+	healthy := 0
+	for _, proxy := range u.proxies.Proxies() {
+		client := u.ClientVia(proxy, 15*time.Second)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+		if err != nil {
+			return 0
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			// Transport failures were already recorded
+			continue
+		}
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+		resp.Body.Close()
+		if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusProxyAuthRequired {
+			u.proxies.MarkFailed(proxy, fmt.Errorf("health check returned %s", resp.Status))
+			continue
+		}
+		u.proxies.MarkHealthy(proxy)
+		healthy++
+	}
+	return healthy
+}
+
+// Start checks the proxies against target now and then every interval
+// until ctx is cancelled
+func (u *Upstream) Start(ctx context.Context, interval time.Duration, target string) {
+	if u.proxies.Len() == 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		healthy := u.CheckProxies(ctx, target)
+		if u.logger != nil {
+			u.logger.Info("Proxies checked", "healthy", healthy, "total", u.proxies.Len())
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
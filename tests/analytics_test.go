@@ -0,0 +1,149 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/JustJay7/court-data-fetcher/internal/analytics"
+	"github.com/JustJay7/court-data-fetcher/internal/database"
+	"github.com/JustJay7/court-data-fetcher/internal/ordertype"
+	"github.com/JustJay7/court-data-fetcher/pkg/logger"
+	"gorm.io/gorm"
+)
+
+// seedAnalyticsCases stores two pending civil suits and a disposed appeal.
+// The first suit is stored twice, only its latest snapshot should count.
+func seedAnalyticsCases(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	repo := database.NewRepository(db)
+	now := time.Now().UTC()
+	day := func(offset int) time.Time {
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, offset)
+	}
+	save := func(caseInfo database.CaseInfo) {
+		queryLog := &database.QueryLog{CaseNumber: caseInfo.CaseNumber, Success: true}
+		if err := repo.CreateQueryLog(queryLog); err != nil {
+			t.Fatalf("CreateQueryLog failed: %v", err)
+		}
+		if err := repo.SaveCaseSnapshot(queryLog, &caseInfo); err != nil {
+			t.Fatalf("SaveCaseSnapshot failed: %v", err)
+		}
+	}
+
+	suit := database.CaseInfo{
+		CaseNumber: "CS/1/2023", CaseType: "CS", FilingYear: "2023", FilingDate: day(-400),
+		Status: "Disposed", Judge: "Hon'ble Mr. Justice A. K. Mehra",
+	}
+	save(suit)
+	suit.Status, suit.NextHearing = "Pending", day(10)
+	suit.Orders = []database.Order{
+		{OrderDate: day(-200), Description: "Notice issued."},
+		{OrderDate: day(-170), Description: "Adjourned at the request of counsel for the respondent.", OrderType: ordertype.Adjournment},
+		{OrderDate: day(-110), Description: "Not taken up. Renotify.", OrderType: ordertype.Adjournment},
+	}
+	save(suit)
+
+	save(database.CaseInfo{
+		CaseNumber: "CS/2/2024", CaseType: "CS", FilingYear: "2024", FilingDate: day(-100),
+		Status: "Pending", Judge: "Hon'ble Mr. Justice A. K. Mehra", NextHearing: day(60),
+		Orders: []database.Order{{OrderDate: day(-90), Description: "Summons issued."}},
+	})
+	save(database.CaseInfo{
+		CaseNumber: "CRL.A./3/2023", CaseType: "CRL.A.", FilingYear: "2023", FilingDate: day(-700),
+		Status: "Disposed", Judge: "Hon'ble Ms. Justice N. Bansal",
+		Orders: []database.Order{
+			{OrderDate: day(-40), Description: "Arguments heard."},
+			{OrderDate: day(-30), Description: "Appeal allowed."},
+		},
+	})
+}
+
+func TestAnalyticsDashboard(t *testing.T) {
+	db := newTestDB(t)
+	seedAnalyticsCases(t, db)
+	log, _ := logger.NewLogger("error", "json")
+	service := analytics.NewService(db, time.Minute, log)
+
+	dashboard, err := service.Dashboard(context.Background(), false)
+	if err != nil {
+		t.Fatalf("Dashboard failed: %v", err)
+	}
+	if dashboard.Cases != 3 || dashboard.Pending != 2 {
+		t.Errorf("Expected 3 cases with 2 pending, got %d and %d", dashboard.Cases, dashboard.Pending)
+	}
+
+	if len(dashboard.Pendency) != 1 {
+		t.Fatalf("Expected pendency for CS only, got %+v", dashboard.Pendency)
+	}
+	pendency := dashboard.Pendency[0]
+	if pendency.CaseType != "CS" || pendency.Pending != 2 || pendency.Buckets[0] != 1 || pendency.Buckets[1] != 1 ||
+		math.Abs(pendency.AverageAgeDays-250) > 1 {
+		t.Errorf("Unexpected pendency %+v", pendency)
+	}
+
+	hearings := dashboard.Hearings
+	if hearings.Intervals != 3 || math.Abs(hearings.AverageDays-100.0/3) > 0.01 || len(hearings.ByCaseType) != 2 {
+		t.Errorf("Unexpected hearing intervals %+v", hearings)
+	} else if byType := hearings.ByCaseType[1]; byType.CaseType != "CS" || byType.Intervals != 2 || math.Abs(byType.AverageDays-45) > 0.01 {
+		t.Errorf("Unexpected CS hearing interval %+v", byType)
+	}
+
+	adjournments := dashboard.Adjournments
+	if adjournments.Total != 2 || len(adjournments.MostAdjourned) != 1 ||
+		adjournments.MostAdjourned[0].CaseNumber != "CS/1/2023" || adjournments.MostAdjourned[0].Adjournments != 2 {
+		t.Errorf("Unexpected adjournments %+v", adjournments)
+	}
+
+	if len(dashboard.Disposals) != 2 {
+		t.Fatalf("Expected disposals for 2 years, got %+v", dashboard.Disposals)
+	}
+	if year := dashboard.Disposals[0]; year.Year != "2023" || year.Filed != 2 || year.Disposed != 1 || year.DisposalRate != 50 {
+		t.Errorf("Unexpected 2023 disposals %+v", year)
+	}
+
+	if len(dashboard.Judges) != 2 {
+		t.Fatalf("Expected 2 judges, got %+v", dashboard.Judges)
+	}
+	if judge := dashboard.Judges[0]; judge.Judge != "Hon'ble Mr. Justice A. K. Mehra" || judge.Pending != 2 || judge.Upcoming != 1 {
+		t.Errorf("Expected the busiest judge first, got %+v", judge)
+	}
+
+	// New cases show up once the cached dashboard is refreshed
+	saveTestCase(t, database.NewRepository(db), "CS/4/2024")
+	if cached, _ := service.Dashboard(context.Background(), false); cached.Cases != 3 {
+		t.Errorf("Expected the cached dashboard, got %d cases", cached.Cases)
+	}
+	if fresh, _ := service.Dashboard(context.Background(), true); fresh.Cases != 4 {
+		t.Errorf("Expected a recomputed dashboard, got %d cases", fresh.Cases)
+	}
+}
+
+func TestAnalyticsAPI(t *testing.T) {
+	router, db := setupTestRouter()
+	seedAnalyticsCases(t, db)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/analytics/disposals", nil)
+	router.ServeHTTP(w, req)
+
+	var response struct {
+		Success bool                     `json:"success"`
+		Data    []analytics.DisposalYear `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if w.Code != http.StatusOK || !response.Success || len(response.Data) != 2 {
+		t.Errorf("Expected disposals for 2 years, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/analytics/unknown", nil)
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown section, got %d", w.Code)
+	}
+}
@@ -3,28 +3,56 @@ package tests
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
+	"html/template"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/JustJay7/court-data-fetcher/internal/api"
 	"github.com/JustJay7/court-data-fetcher/internal/cache"
 	"github.com/JustJay7/court-data-fetcher/internal/config"
 	"github.com/JustJay7/court-data-fetcher/internal/database"
+	"github.com/JustJay7/court-data-fetcher/internal/upstream"
 	"github.com/JustJay7/court-data-fetcher/pkg/logger"
-	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
+func TestMain(m *testing.M) {
+	// Templates and static files are resolved relative to the repository root
+	if err := os.Chdir(".."); err != nil {
+		panic(err)
+	}
+
+	stop, err := startEmbeddedPostgres()
+	if err != nil {
+		panic(err)
+	}
+	code := m.Run()
+	dropTestSchemas()
+	stop()
+	os.Exit(code)
+}
+
 func setupTestRouter() (*gin.Engine, *gorm.DB) {
+	router, db, _ := setupTestRouterWithCache()
+	return router, db
+}
+
+func setupTestRouterWithCache() (*gin.Engine, *gorm.DB, cache.Cache) {
 	// Set Gin to test mode
 	gin.SetMode(gin.TestMode)
 
 	// Create test database
-	db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
-	database.Migrate(db)
+	db, err := openTestDB()
+	if err != nil {
+		panic(err)
+	}
 
 	// Create test config
 	cfg := &config.Config{
@@ -36,13 +64,13 @@ func setupTestRouter() (*gin.Engine, *gorm.DB) {
 	log, _ := logger.NewLogger("error", "json")
 
 	// Create cache
-	testCache := cache.NewCache(100, 30)
+	testCache := cache.NewCache(100, 30*time.Minute, 5*time.Minute)
 
 	// Create router
 	router := gin.New()
-	api.SetupRoutes(router, db, testCache, nil, log, cfg)
+	api.SetupRoutes(router, db, testCache, nil, upstream.Direct(), log, cfg)
 
-	return router, db
+	return router, db, testCache
 }
 
 func TestHealthCheck(t *testing.T) {
@@ -71,21 +99,25 @@ func TestGetCaseAPI(t *testing.T) {
 		name       string
 		query      string
 		wantStatus int
+		wantCode   string
 	}{
 		{
 			name:       "Valid request",
 			query:      "?type=CS&number=1234&year=2023",
-			wantStatus: http.StatusInternalServerError, // Will fail without scraper
+			wantStatus: http.StatusServiceUnavailable, // Will fail without scraper
+			wantCode:   "court_unavailable",
 		},
 		{
 			name:       "Missing parameters",
 			query:      "?type=CS",
 			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_input",
 		},
 		{
 			name:       "Empty parameters",
 			query:      "",
 			wantStatus: http.StatusBadRequest,
+			wantCode:   "invalid_input",
 		},
 	}
 
@@ -98,6 +130,12 @@ func TestGetCaseAPI(t *testing.T) {
 			if w.Code != tt.wantStatus {
 				t.Errorf("Expected status %d, got %d", tt.wantStatus, w.Code)
 			}
+
+			var response map[string]interface{}
+			json.Unmarshal(w.Body.Bytes(), &response)
+			if response["error_code"] != tt.wantCode {
+				t.Errorf("Expected error_code %q, got %v", tt.wantCode, response["error_code"])
+			}
 		})
 	}
 }
@@ -162,7 +200,7 @@ func TestBulkSearchAPI(t *testing.T) {
 }
 
 func TestSearchFormSubmission(t *testing.T) {
-	router, _ := setupTestRouter()
+	router, db := setupTestRouter()
 
 	form := url.Values{}
 	form.Add("case_type", "CS")
@@ -175,8 +213,16 @@ func TestSearchFormSubmission(t *testing.T) {
 	router.ServeHTTP(w, req)
 
 	// Should render error template without scraper
-	if w.Code != http.StatusInternalServerError {
-		t.Errorf("Expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status %d, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+
+	var queryLog database.QueryLog
+	if err := db.Last(&queryLog).Error; err != nil {
+		t.Fatalf("Expected query log to be saved: %v", err)
+	}
+	if queryLog.Success || queryLog.ErrorCode != "court_unavailable" {
+		t.Errorf("Expected failed query log with error_code court_unavailable, got %+v", queryLog)
 	}
 }
 
@@ -202,4 +248,162 @@ func TestCacheStats(t *testing.T) {
 	if stats["size"] == nil {
 		t.Error("Cache stats should include size")
 	}
-}
\ No newline at end of file
+}
+
+func TestCacheFreshnessControls(t *testing.T) {
+	router, _, testCache := setupTestRouterWithCache()
+
+	key := cache.GenerateCacheKey("CS", "1234", "2023")
+	testCache.Set(key, &database.CaseInfo{CaseNumber: "CS/1234/2023"})
+
+	get := func(query string, header http.Header) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/case?type=CS&number=1234&year=2023"+query, nil)
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		router.ServeHTTP(w, req)
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response
+	}
+
+	code, response := get("", nil)
+	if code != http.StatusOK || response["fromCache"] != true {
+		t.Fatalf("Expected cached response, got %d %v", code, response)
+	}
+	if response["cached_at"] == nil || response["age"] == nil {
+		t.Errorf("Expected cached_at and age metadata, got %v", response)
+	}
+
+	// Bypassing the cache reaches the (missing) scraper
+	if code, _ := get("&refresh=true", nil); code != http.StatusServiceUnavailable {
+		t.Errorf("refresh=true: expected status %d, got %d", http.StatusServiceUnavailable, code)
+	}
+	if code, _ := get("", http.Header{"Cache-Control": {"no-cache"}}); code != http.StatusServiceUnavailable {
+		t.Errorf("Cache-Control: no-cache: expected status %d, got %d", http.StatusServiceUnavailable, code)
+	}
+	// A browser reload sends max-age=0, which shouldn't force a scrape
+	if code, response := get("", http.Header{"Cache-Control": {"max-age=0"}}); code != http.StatusOK || response["fromCache"] != true {
+		t.Errorf("Cache-Control: max-age=0: expected cached response, got %d %v", code, response)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/api/cache/"+key, nil)
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if _, found := testCache.Get(key); found {
+		t.Error("Expected entry to be invalidated")
+	}
+
+	testCache.Set(key, &database.CaseInfo{CaseNumber: "CS/1234/2023"})
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("DELETE", "/api/cache", nil)
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || testCache.Stats().Size != 0 {
+		t.Errorf("Expected cache to be cleared, got status %d and %+v", w.Code, testCache.Stats())
+	}
+}
+
+func TestAPISearchesAreLogged(t *testing.T) {
+	router, db := setupTestRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/case?type=CS&number=1234&year=2023", nil)
+	router.ServeHTTP(w, req)
+
+	payload, _ := json.Marshal(map[string]interface{}{
+		"queries": []map[string]string{
+			{"case_type": "CS", "case_number": "100", "filing_year": "2023"},
+			{"case_type": "CS", "case_number": "200", "filing_year": "2023"},
+		},
+	})
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("POST", "/api/cases/bulk", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+
+	var logs []database.QueryLog
+	db.Order("id").Find(&logs)
+	if len(logs) != 3 {
+		t.Fatalf("Expected 3 query logs, got %d", len(logs))
+	}
+
+	sources := map[string]int{}
+	for _, log := range logs {
+		sources[log.Source]++
+		if log.Success || log.ErrorCode != "court_unavailable" {
+			t.Errorf("Expected failed log with error_code court_unavailable, got %+v", log)
+		}
+	}
+	if sources["api"] != 1 || sources["bulk"] != 2 {
+		t.Errorf("Expected 1 api and 2 bulk logs, got %v", sources)
+	}
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	results := response["results"].([]interface{})
+	first := results[0].(map[string]interface{})
+	if first["query"].(map[string]interface{})["case_number"] != "100" {
+		t.Errorf("Expected bulk results to echo the query, got %v", first["query"])
+	}
+}
+
+func TestDebugArtifactsEndpoints(t *testing.T) {
+	router, db := setupTestRouter()
+	repo := database.NewRepository(db)
+
+	queryLog := &database.QueryLog{CaseType: "CS", CaseNumber: "1234", FilingYear: "2023", QueryTime: time.Now(), ErrorCode: "layout_changed"}
+	repo.CreateQueryLog(queryLog)
+	png := []byte("\x89PNG\r\n\x1a\n")
+	err := repo.SaveDebugArtifacts(queryLog.ID, []database.DebugArtifact{
+		{Kind: database.ArtifactScreenshot, ContentType: "image/png", Data: png},
+		{Kind: database.ArtifactConsole, ContentType: "text/plain; charset=utf-8", Data: []byte("[error] boom")},
+	})
+	if err != nil {
+		t.Fatalf("SaveDebugArtifacts failed: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", fmt.Sprintf("/api/logs/%d/artifacts", queryLog.ID), nil)
+	router.ServeHTTP(w, req)
+
+	var response struct {
+		Data []struct {
+			Kind string `json:"kind"`
+			Size int    `json:"size"`
+			URL  string `json:"url"`
+		} `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if w.Code != http.StatusOK || len(response.Data) != 2 {
+		t.Fatalf("Expected 2 artifacts, got %d: %s", w.Code, w.Body.String())
+	}
+	if response.Data[0].Kind != database.ArtifactScreenshot || response.Data[0].Size != len(png) {
+		t.Errorf("Unexpected artifact: %+v", response.Data[0])
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", response.Data[0].URL, nil)
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "image/png" || !bytes.Equal(w.Body.Bytes(), png) {
+		t.Errorf("Expected screenshot to be served, got %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+
+	// Every page template defines "content", so render the logs page on its own
+	artifacts, _ := repo.FindDebugArtifactsFor([]uint{queryLog.ID})
+	tmpl := template.Must(template.ParseFiles("web/templates/logs.html"))
+	var page bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&page, "content", gin.H{
+		"logs":      []database.QueryLog{*queryLog},
+		"artifacts": artifacts,
+	}); err != nil {
+		t.Fatalf("Failed to render logs page: %v", err)
+	}
+	if !strings.Contains(page.String(), response.Data[1].URL) {
+		t.Error("Expected the logs page to link to the debug artifacts")
+	}
+}
@@ -0,0 +1,375 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/JustJay7/court-data-fetcher/internal/cache"
+	"github.com/JustJay7/court-data-fetcher/internal/database"
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestNegativeCache(t *testing.T) {
+	c := cache.NewCache(10, time.Minute, 50*time.Millisecond)
+	key := cache.GenerateCacheKey("CS", "404", "2023")
+
+	if c.IsNotFound(key) {
+		t.Fatal("Expected no negative entry before SetNotFound")
+	}
+
+	c.SetNotFound(key)
+	if !c.IsNotFound(key) {
+		t.Fatal("Expected negative entry after SetNotFound")
+	}
+	if _, found := c.Get(key); found {
+		t.Error("Negative entry should not be returned by Get")
+	}
+
+	stats := c.Stats()
+	if stats.NegativeHits != 1 || stats.NegativeMisses != 1 {
+		t.Errorf("Expected 1 negative hit and miss, got %+v", stats)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if c.IsNotFound(key) {
+		t.Error("Negative entry should expire after its TTL")
+	}
+
+	// A successful result replaces a negative one
+	c.SetNotFound(key)
+	c.Set(key, &database.CaseInfo{CaseNumber: "CS/404/2023"})
+	if c.IsNotFound(key) {
+		t.Error("Set should clear the negative entry")
+	}
+}
+
+func TestGroupCoalescesConcurrentCalls(t *testing.T) {
+	g := cache.NewGroup()
+	release := make(chan struct{})
+	var calls int32
+
+	const callers = 5
+	var wg sync.WaitGroup
+	results := make([]interface{}, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			v, _, _ := g.Do("case:CS:1:2023", func() (interface{}, error) {
+				atomic.AddInt32(&calls, 1)
+				<-release
+				return "result", nil
+			})
+			results[i] = v
+		}(i)
+	}
+
+	// Give every caller time to join the in-flight call
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("Expected 1 call, got %d", calls)
+	}
+	if g.Coalesced() != callers-1 {
+		t.Errorf("Expected %d coalesced callers, got %d", callers-1, g.Coalesced())
+	}
+	for i, v := range results {
+		if v != "result" {
+			t.Errorf("Caller %d got %v", i, v)
+		}
+	}
+}
+
+func TestGroupRecoversPanics(t *testing.T) {
+	g := cache.NewGroup()
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	var waiterErr error
+	var waiterShared bool
+	go func() {
+		<-started
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, waiterErr, waiterShared = g.Do("case:CS:2:2023", func() (interface{}, error) {
+				return "unexpected", nil
+			})
+		}()
+		// Coalesced counts the waiter once it holds the in-flight call
+		for g.Coalesced() == 0 {
+			runtime.Gosched()
+		}
+		close(release)
+	}()
+
+	v, err, _ := g.Do("case:CS:2:2023", func() (interface{}, error) {
+		close(started)
+		<-release
+		panic("element not found")
+	})
+	wg.Wait()
+
+	var panicErr *cache.PanicError
+	if v != nil || !errors.As(err, &panicErr) || panicErr.Value != "element not found" {
+		t.Errorf("Expected a PanicError for the caller, got %v, %v", v, err)
+	}
+	if !waiterShared || !errors.As(waiterErr, &panicErr) {
+		t.Errorf("Expected the waiter to share the PanicError, got %v (shared %v)", waiterErr, waiterShared)
+	}
+}
+
+// testPersistentCache checks the behaviour every persistent backend shares
+func testPersistentCache(t *testing.T, c cache.Cache) {
+	t.Helper()
+	key := cache.GenerateCacheKey("CS", "1234", "2023")
+
+	if _, found := c.Get(key); found {
+		t.Fatal("Expected miss on empty cache")
+	}
+
+	want := &database.CaseInfo{
+		CaseNumber: "CS/1234/2023",
+		Parties:    []database.Party{{Name: "John Doe", Type: "Petitioner"}},
+		Orders:     []database.Order{{Description: "Order on IA"}},
+	}
+	if err := c.Set(key, want); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	got, found := c.Get(key)
+	if !found {
+		t.Fatal("Expected hit after Set")
+	}
+	if got.CaseNumber != want.CaseNumber || len(got.Parties) != 1 || len(got.Orders) != 1 {
+		t.Errorf("Round trip mismatch: %+v", got)
+	}
+
+	missing := cache.GenerateCacheKey("CS", "404", "2023")
+	if err := c.SetNotFound(missing); err != nil {
+		t.Fatalf("SetNotFound failed: %v", err)
+	}
+	if !c.IsNotFound(missing) || c.IsNotFound(key) {
+		t.Error("Negative entries should only match keys marked not found")
+	}
+	if _, found := c.Get(missing); found {
+		t.Error("Negative entry should not be returned by Get")
+	}
+
+	stats := c.Stats()
+	if stats.Size != 1 || stats.NegativeSize != 1 || stats.Hits != 1 || stats.Misses != 2 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+
+	c.Delete(key)
+	if _, found := c.Get(key); found {
+		t.Error("Expected miss after Delete")
+	}
+
+	c.Clear()
+	if c.IsNotFound(missing) {
+		t.Error("Expected Clear to remove negative entries")
+	}
+}
+
+func TestSQLiteCache(t *testing.T) {
+	db := newTestDB(t)
+	testPersistentCache(t, cache.NewSQLiteCache(db, time.Minute, time.Minute))
+
+	// Entries outlive the cache instance that wrote them
+	key := cache.GenerateCacheKey("CS", "1", "2023")
+	cache.NewSQLiteCache(db, time.Minute, time.Minute).Set(key, &database.CaseInfo{CaseNumber: "CS/1/2023"})
+	if _, found := cache.NewSQLiteCache(db, time.Minute, time.Minute).Get(key); !found {
+		t.Error("Expected entry to persist across cache instances")
+	}
+
+	expired := cache.NewSQLiteCache(db, -time.Second, time.Minute)
+	expired.Set(key, &database.CaseInfo{CaseNumber: "CS/1/2023"})
+	if _, found := expired.Get(key); found {
+		t.Error("Expected expired entry to miss")
+	}
+}
+
+func TestRedisCache(t *testing.T) {
+	mr := miniredis.RunT(t)
+
+	c, err := cache.NewRedisCache("redis://"+mr.Addr()+"/0", time.Minute, 10*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to connect to miniredis: %v", err)
+	}
+	defer c.Close()
+
+	testPersistentCache(t, c)
+
+	// Stats counts entries across several scan pages without reading them
+	c.Clear()
+	for i := 0; i < 150; i++ {
+		c.Set(cache.GenerateCacheKey("CS", strconv.Itoa(i), "2024"), &database.CaseInfo{CaseNumber: fmt.Sprintf("CS/%d/2024", i)})
+	}
+	c.SetNotFound(cache.GenerateCacheKey("CS", "999", "2024"))
+	if stats := c.Stats(); stats.Size != 150 || stats.NegativeSize != 1 || stats.Bytes <= 0 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+
+	key := cache.GenerateCacheKey("CS", "404", "2024")
+	c.SetNotFound(key)
+	mr.FastForward(11 * time.Second)
+	if c.IsNotFound(key) {
+		t.Error("Negative entry should expire after its TTL")
+	}
+}
+
+func TestTieredCacheBackfillsLocal(t *testing.T) {
+	remote := cache.NewSQLiteCache(newTestDB(t), time.Minute, time.Minute)
+	local := cache.NewCache(10, time.Minute, time.Minute)
+	tiered := cache.NewTieredCache(local, remote)
+
+	key := cache.GenerateCacheKey("CS", "1234", "2023")
+	remote.Set(key, &database.CaseInfo{CaseNumber: "CS/1234/2023"})
+
+	if _, found := tiered.Get(key); !found {
+		t.Fatal("Expected hit from remote tier")
+	}
+	if _, found := local.Get(key); !found {
+		t.Error("Expected remote hit to be copied into the local tier")
+	}
+
+	stats := tiered.Stats()
+	if stats.Hits != 1 || len(stats.Tiers) != 2 {
+		t.Errorf("Unexpected tiered stats: %+v", stats)
+	}
+}
+
+func TestTieredCacheSeesOtherReplicasInvalidations(t *testing.T) {
+	remote := cache.NewSQLiteCache(newTestDB(t), time.Minute, time.Minute)
+	local := cache.NewSizedCache(10, 0, time.Minute, time.Minute)
+	local.SetMaxAge(50 * time.Millisecond)
+	tiered := cache.NewTieredCache(local, remote)
+
+	key := cache.GenerateCacheKey("CS", "1234", "2023")
+	if err := tiered.Set(key, &database.CaseInfo{CaseNumber: "CS/1234/2023"}); err != nil {
+		t.Fatalf("Failed to set: %v", err)
+	}
+
+	// Another replica invalidates the shared entry
+	remote.Delete(key)
+	if _, found := tiered.Get(key); !found {
+		t.Fatal("Expected the local copy to be served until it ages out")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, found := tiered.Get(key); found {
+		t.Error("Expected the invalidation to be seen once the local copy expired")
+	}
+}
+
+func TestLRUCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := cache.NewCache(2, time.Minute, time.Minute)
+
+	c.Set("a", &database.CaseInfo{CaseNumber: "a"})
+	c.Set("b", &database.CaseInfo{CaseNumber: "b"})
+
+	// Reading "a" makes "b" the least recently used entry
+	c.Get("a")
+	c.Set("c", &database.CaseInfo{CaseNumber: "c"})
+
+	if _, found := c.Get("b"); found {
+		t.Error("Expected least recently used entry to be evicted")
+	}
+	if _, found := c.Get("a"); !found {
+		t.Error("Expected recently read entry to survive")
+	}
+
+	stats := c.Stats()
+	if stats.Evictions != 1 || stats.Size != 2 {
+		t.Errorf("Expected 1 eviction and 2 entries, got %+v", stats)
+	}
+}
+
+func TestLRUCacheByteLimit(t *testing.T) {
+	order := database.Order{Description: strings.Repeat("x", 1000)}
+	big := &database.CaseInfo{CaseNumber: "big", Orders: []database.Order{order, order}}
+
+	c := cache.NewSizedCache(100, 5000, time.Minute, time.Minute)
+	for i := 0; i < 4; i++ {
+		c.Set(fmt.Sprintf("case:%d", i), big)
+	}
+
+	stats := c.Stats()
+	if stats.Bytes > 5000 {
+		t.Errorf("Cache holds %d bytes, limit is 5000", stats.Bytes)
+	}
+	if stats.Evictions == 0 {
+		t.Error("Expected byte limit to force evictions")
+	}
+	if _, found := c.Get("case:3"); !found {
+		t.Error("Expected newest entry to be kept")
+	}
+
+	tooBig := &database.CaseInfo{Orders: []database.Order{order, order, order, order, order}}
+	if err := c.Set("huge", tooBig); err == nil {
+		t.Error("Expected error for entry larger than the byte limit")
+	}
+}
+
+func benchmarkCache(keys int) (*cache.LRUCache, []string) {
+	c := cache.NewSizedCache(keys*2, 0, time.Hour, time.Hour)
+	names := make([]string, keys)
+	for i := range names {
+		names[i] = cache.GenerateCacheKey("CS", fmt.Sprint(i), "2023")
+		c.Set(names[i], &database.CaseInfo{CaseNumber: names[i]})
+	}
+	return c, names
+}
+
+func BenchmarkLRUCacheGetParallel(b *testing.B) {
+	c, keys := benchmarkCache(4096)
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			c.Get(keys[i%len(keys)])
+			i++
+		}
+	})
+}
+
+func BenchmarkLRUCacheMixedParallel(b *testing.B) {
+	c, keys := benchmarkCache(4096)
+	value := &database.CaseInfo{CaseNumber: "CS/1/2023"}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			// One write for every nine reads
+			if i%10 == 0 {
+				c.Set(keys[i%len(keys)], value)
+			} else {
+				c.Get(keys[i%len(keys)])
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkLRUCacheSetEvicting(b *testing.B) {
+	c := cache.NewCache(1000, time.Hour, time.Hour)
+	value := &database.CaseInfo{CaseNumber: "CS/1/2023"}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Set(strconv.Itoa(i), value)
+	}
+}
@@ -0,0 +1,176 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/JustJay7/court-data-fetcher/internal/cache"
+	"github.com/JustJay7/court-data-fetcher/internal/caseid"
+	"github.com/JustJay7/court-data-fetcher/internal/database"
+)
+
+func TestParseCaseNumber(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"CS(OS) 100/2023", "CS(OS)/100/2023"},
+		{"cs (os) no. 0100 of 2023", "CS(OS)/100/2023"},
+		{"W.P.(C)-1234-2023", "W.P.(C)/1234/2023"},
+		{"W.P. (C) 1234/2023", "W.P.(C)/1234/2023"},
+		{"BAIL APPLN. 12/2024", "BAIL APPLN./12/2024"},
+		{"CS/1/2023", "CS/1/2023"},
+		{"FAO(OS) (COMM) 12/2023", "FAO(OS)(COMM)/12/2023"},
+		{"RFA(OS)(COMM) 7/2022", "RFA(OS)(COMM)/7/2022"},
+		{"O.M.P. (I) (COMM.) 345/2021", "O.M.P.(I)(COMM.)/345/2021"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			id, err := caseid.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) failed: %v", tt.input, err)
+			}
+			if id.String() != tt.want {
+				t.Errorf("Parse(%q) = %s, want %s", tt.input, id, tt.want)
+			}
+		})
+	}
+
+	for _, input := range []string{"", "1234/2023", "CS 12345678/2023", "CS 0/2023", "CS 1/1899", "CS 1/23"} {
+		_, err := caseid.Parse(input)
+		var invalid *caseid.ValidationError
+		if !errors.Is(err, caseid.ErrInvalidCaseNumber) || !errors.As(err, &invalid) {
+			t.Errorf("Parse(%q) = %v, want a case number validation error", input, err)
+		}
+	}
+
+	if id, ok := caseid.Find("Listed today: Case No. CS(COMM) 45/2022 before the Court"); !ok || id.String() != "CS(COMM)/45/2022" {
+		t.Errorf("Find = %v %v, want CS(COMM)/45/2022", id, ok)
+	}
+
+	for caseType, want := range map[string]string{
+		"FAO(OS) (COMM)":     "FAO(OS)(COMM)",
+		"RFA(OS)(COMM)":      "RFA(OS)(COMM)",
+		"O.M.P. (I) (COMM.)": "O.M.P.(I)(COMM.)",
+	} {
+		if id, err := caseid.New(caseType, "12", "2023"); err != nil || id.Type != want {
+			t.Errorf("New(%q) = %v, %v, want type %s", caseType, id, err, want)
+		}
+	}
+	if id, ok := caseid.Find("Appeal FAO(OS) (COMM) 12/2023 is listed"); !ok || id.String() != "FAO(OS)(COMM)/12/2023" {
+		t.Errorf("Find = %v %v, want FAO(OS)(COMM)/12/2023", id, ok)
+	}
+
+	wp, _ := caseid.Parse("W.P.(C) 1234/2023")
+	compact, _ := caseid.New("wp(c)", "01234", "2023")
+	if wp.CompactType() != compact.CompactType() || cache.CaseKey(wp) != cache.CaseKey(compact) {
+		t.Errorf("Expected notations of one case to share a cache key, got %s and %s", cache.CaseKey(wp), cache.CaseKey(compact))
+	}
+}
+
+func TestParseCNR(t *testing.T) {
+	cnr, err := caseid.ParseCNR("dlct01-001234-2023")
+	if err != nil {
+		t.Fatalf("ParseCNR failed: %v", err)
+	}
+	if cnr.String() != "DLCT010012342023" || cnr.State != "DL" || cnr.District != "CT" || cnr.Establishment != "01" || cnr.Year != "2023" {
+		t.Errorf("Unexpected CNR %+v", cnr)
+	}
+
+	for _, input := range []string{"", "DLCT01001234202", "DLC1010012342023", "DLCT010012341899"} {
+		if _, err := caseid.ParseCNR(input); !errors.Is(err, caseid.ErrInvalidCNR) {
+			t.Errorf("ParseCNR(%q) = %v, want ErrInvalidCNR", input, err)
+		}
+	}
+
+	if found, ok := caseid.FindCNR("CNR Number: DLHC010012342023, Filing Year 2023"); !ok || found.String() != "DLHC010012342023" {
+		t.Errorf("FindCNR = %v %v", found, ok)
+	}
+}
+
+func TestCaseByCNRAPI(t *testing.T) {
+	router, db := setupTestRouter()
+	repo := database.NewRepository(db)
+
+	for _, status := range []string{"Pending", "Disposed"} {
+		queryLog := &database.QueryLog{CaseNumber: "CS/1/2023"}
+		repo.CreateQueryLog(queryLog)
+		caseInfo := &database.CaseInfo{CaseNumber: "CS/1/2023", CNR: "DLCT010000012023", Status: status}
+		if err := repo.SaveCaseSnapshot(queryLog, caseInfo); err != nil {
+			t.Fatalf("SaveCaseSnapshot failed: %v", err)
+		}
+	}
+
+	get := func(cnr string) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/case/by-cnr/"+cnr, nil)
+		router.ServeHTTP(w, req)
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response
+	}
+
+	code, response := get("dlct01-000001-2023")
+	data, _ := response["data"].(map[string]interface{})
+	if code != http.StatusOK || data["status"] != "Disposed" {
+		t.Errorf("Expected the latest snapshot, got %d %v", code, response)
+	}
+	if code, _ := get("DLCT010000022023"); code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown CNR, got %d", code)
+	}
+	if code, _ := get("not-a-cnr"); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid CNR, got %d", code)
+	}
+}
+
+func TestCaseAPIAcceptsWholeCaseNumber(t *testing.T) {
+	router, _, testCache := setupTestRouterWithCache()
+
+	id, _ := caseid.New("W.P.(C)", "1234", "2023")
+	testCache.Set(cache.CaseKey(id), &database.CaseInfo{CaseNumber: id.String()})
+
+	for _, query := range []string{"case=WP(C)%201234/2023", "type=w.p.(c)&number=01234&year=2023"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/case?"+query, nil)
+		router.ServeHTTP(w, req)
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		if w.Code != http.StatusOK || response["fromCache"] != true {
+			t.Errorf("%s: expected the cached case, got %d %v", query, w.Code, response)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/case?case=nonsense", nil)
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unparseable case number, got %d", w.Code)
+	}
+}
+
+func TestMigrateBackfillsCNR(t *testing.T) {
+	migrator, db := newTestMigrator(t)
+	if err := migrator.To(9); err != nil {
+		t.Fatalf("To(9) failed: %v", err)
+	}
+
+	for _, number := range []string{"DLCT010012342023", "CS/1/2023"} {
+		if err := db.Exec("INSERT INTO case_infos (query_log_id, case_number) VALUES (?, ?)", 1, number).Error; err != nil {
+			t.Fatalf("Failed to insert legacy case: %v", err)
+		}
+	}
+
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+
+	var cases []database.CaseInfo
+	db.Order("id").Find(&cases)
+	if len(cases) != 2 || cases[0].CNR != "DLCT010012342023" || cases[1].CNR != "" {
+		t.Errorf("Expected only the CNR case number backfilled, got %+v", cases)
+	}
+}
@@ -0,0 +1,167 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/JustJay7/court-data-fetcher/internal/catalogue"
+	"github.com/JustJay7/court-data-fetcher/internal/database"
+	"github.com/JustJay7/court-data-fetcher/internal/mockcourt"
+	"github.com/JustJay7/court-data-fetcher/internal/scraper"
+	"github.com/JustJay7/court-data-fetcher/pkg/logger"
+)
+
+// staticFetcher serves a fixed list of case types
+type staticFetcher struct {
+	labels []string
+}
+
+func (f *staticFetcher) FetchCaseTypes(context.Context) ([]database.CaseType, error) {
+	types := make([]database.CaseType, len(f.labels))
+	for i, label := range f.labels {
+		types[i] = database.CaseType{Value: label, Label: label}
+	}
+	return types, nil
+}
+
+func TestParseCaseTypesFromMockCourt(t *testing.T) {
+	server := httptest.NewServer(mockcourt.New(mockcourt.Config{}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + mockcourt.StatusPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	types, err := newTestParser(t).ParseCaseTypesHTML(string(body))
+	if err != nil {
+		t.Fatalf("ParseCaseTypesHTML failed: %v", err)
+	}
+	if len(types) != len(mockcourt.CaseTypes) {
+		t.Fatalf("Expected %d case types, got %+v", len(mockcourt.CaseTypes), types)
+	}
+	for i, caseType := range types {
+		if caseType.Label != mockcourt.CaseTypes[i] || caseType.Value != mockcourt.CaseTypes[i] {
+			t.Errorf("Expected %s, got %+v", mockcourt.CaseTypes[i], caseType)
+		}
+	}
+
+	if _, err := newTestParser(t).ParseCaseTypesHTML("<html><body>Maintenance</body></html>"); err == nil {
+		t.Error("Expected an error for a page without the dropdown")
+	}
+}
+
+func TestCaseTypeValue(t *testing.T) {
+	types := []database.CaseType{
+		{Value: "12", Label: "CS(COMM)"},
+		{Value: "7", Label: "CS"},
+		{Value: "31", Label: "W.P.(C)"},
+	}
+	tests := map[string]string{
+		"CS":       "7",
+		"CS(COMM)": "12",
+		"WP(C)":    "31",
+	}
+	for caseType, want := range tests {
+		if got, ok := scraper.CaseTypeValue(types, caseType); !ok || got != want {
+			t.Errorf("CaseTypeValue(%q) = %q, %v, want %q", caseType, got, ok, want)
+		}
+	}
+	if _, ok := scraper.CaseTypeValue(types, "C"); ok {
+		t.Error("Expected a label that is only a prefix not to match")
+	}
+}
+
+func TestCatalogueRefresh(t *testing.T) {
+	db := newTestDB(t)
+	log, _ := logger.NewLogger("error", "json")
+	fetcher := &staticFetcher{labels: []string{"CS(OS)", "W.P.(C)", "BAIL APPLN."}}
+	cat := catalogue.New(db, fetcher, log)
+
+	// Before the first scrape the defaults are offered and anything goes
+	if types, scraped, _ := cat.List(); scraped || len(types) != len(catalogue.DefaultTypes) {
+		t.Errorf("Expected the default types, got %d, scraped %v", len(types), scraped)
+	}
+	if label, err := cat.Resolve("XYZ"); err != nil || label != "XYZ" {
+		t.Errorf("Expected any type before the first scrape, got %q, %v", label, err)
+	}
+
+	if count, err := cat.Refresh(context.Background()); err != nil || count != 3 {
+		t.Fatalf("Refresh = %d, %v", count, err)
+	}
+	for input, want := range map[string]string{"w.p. (c)": "W.P.(C)", "WP(C)": "W.P.(C)", "bail appln": "BAIL APPLN."} {
+		if label, err := cat.Resolve(input); err != nil || label != want {
+			t.Errorf("Resolve(%q) = %q, %v, want %q", input, label, err, want)
+		}
+	}
+	if _, err := cat.Resolve("CS(COMM)"); !errors.Is(err, catalogue.ErrUnknownCaseType) {
+		t.Errorf("Expected ErrUnknownCaseType, got %v", err)
+	}
+
+	// A type the court drops is kept but no longer offered
+	fetcher.labels = []string{"CS(OS)", "W.P.(C)", "CS(COMM)"}
+	if _, err := cat.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	labels, _ := cat.Labels()
+	if len(labels) != 3 || labels[0] != "CS(COMM)" || labels[2] != "W.P.(C)" {
+		t.Errorf("Expected the active types by label, got %v", labels)
+	}
+	var stored int64
+	db.Model(&database.CaseType{}).Count(&stored)
+	if stored != 4 {
+		t.Errorf("Expected 4 stored types, got %d", stored)
+	}
+
+	// An empty form leaves the catalogue alone
+	fetcher.labels = nil
+	if _, err := cat.Refresh(context.Background()); !errors.Is(err, catalogue.ErrEmptyForm) {
+		t.Errorf("Expected ErrEmptyForm, got %v", err)
+	}
+	if labels, _ := cat.Labels(); len(labels) != 3 {
+		t.Errorf("Expected the catalogue to be kept, got %v", labels)
+	}
+}
+
+func TestCaseTypesAPI(t *testing.T) {
+	router, db := setupTestRouter()
+	log, _ := logger.NewLogger("error", "json")
+
+	get := func(path string) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(w, req)
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response
+	}
+
+	code, response := get("/api/case-types")
+	if code != http.StatusOK || response["from_court"] != false || len(response["data"].([]interface{})) != len(catalogue.DefaultTypes) {
+		t.Errorf("Expected the default types, got %d %v", code, response)
+	}
+
+	if _, err := catalogue.New(db, &staticFetcher{labels: []string{"W.P.(C)"}}, log).Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	code, response = get("/api/case-types")
+	if code != http.StatusOK || response["from_court"] != true || len(response["data"].([]interface{})) != 1 {
+		t.Errorf("Expected the scraped types, got %d %v", code, response)
+	}
+
+	// Unknown types are rejected before any browser is needed, known ones
+	// get as far as the (missing) scraper
+	if code, response := get("/api/case?type=CS(COMM)&number=1&year=2023"); code != http.StatusBadRequest || response["error_code"] != "invalid_input" {
+		t.Errorf("Expected 400 for an unknown case type, got %d %v", code, response)
+	}
+	if code, _ := get("/api/case?type=WP(C)&number=1&year=2023"); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected a known case type to reach the scraper, got %d", code)
+	}
+}
@@ -0,0 +1,148 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/JustJay7/court-data-fetcher/internal/api"
+	"github.com/JustJay7/court-data-fetcher/internal/cache"
+	"github.com/JustJay7/court-data-fetcher/internal/causelist"
+	"github.com/JustJay7/court-data-fetcher/internal/config"
+	"github.com/JustJay7/court-data-fetcher/internal/database"
+	"github.com/JustJay7/court-data-fetcher/internal/mockcourt"
+	"github.com/JustJay7/court-data-fetcher/internal/upstream"
+	"github.com/JustJay7/court-data-fetcher/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+func TestParseCauseList(t *testing.T) {
+	mock := mockcourt.New(mockcourt.Config{})
+	mock.AddCase(mockcourt.Case{
+		Type: "CS(OS)", Number: "100", Year: "2023",
+		NextHearing: time.Date(2024, time.March, 18, 0, 0, 0, 0, time.UTC),
+		Judge:       "Hon'ble Ms. Justice N. Bansal",
+		Petitioners: []string{"Lakshmi Traders"}, Respondents: []string{"Ramesh Chand"},
+	})
+	// Commercial appeals carry two bracketed qualifiers
+	mock.AddCase(mockcourt.Case{
+		Type: "FAO(OS) (COMM)", Number: "12", Year: "2023",
+		NextHearing: time.Date(2024, time.March, 18, 0, 0, 0, 0, time.UTC),
+		Judge:       "Hon'ble Ms. Justice N. Bansal",
+		Petitioners: []string{"Kohli Exports"}, Respondents: []string{"Lakshmi Traders"},
+	})
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + mockcourt.CauseListPath + "?date=18-03-2024")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	entries, err := newTestParser(t).ParseCauseListHTML(string(body))
+	if err != nil {
+		t.Fatalf("ParseCauseListHTML failed: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %+v", entries)
+	}
+	first, second := entries[0], entries[1]
+	if first.CaseNumber != "W.P.(C)/1234/2023" || first.CourtRoom != "01" || first.ItemNumber != "1" ||
+		first.Bench != "Hon'ble Mr. Justice A. K. Mehra" || first.Parties == "" || first.Advocate != "Mr. Arvind Nair" {
+		t.Errorf("Unexpected first entry %+v", first)
+	}
+	if second.CaseNumber != "CS(OS)/100/2023" || second.CourtRoom != "02" || second.ItemNumber != "1" || second.Bench != "Hon'ble Ms. Justice N. Bansal" {
+		t.Errorf("Unexpected second entry %+v", second)
+	}
+	if third := entries[2]; third.CaseNumber != "FAO(OS)(COMM)/12/2023" {
+		t.Errorf("Expected the commercial appeal to be read, got %+v", third)
+	}
+
+	resp, _ = http.Get(server.URL + mockcourt.CauseListPath + "?date=19-03-2024")
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if entries, err := newTestParser(t).ParseCauseListHTML(string(body)); err != nil || len(entries) != 0 {
+		t.Errorf("Expected no entries for a day without a list, got %+v, %v", entries, err)
+	}
+}
+
+func TestCauseListAPI(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	mock := mockcourt.New(mockcourt.Config{})
+	mock.AddCase(mockcourt.Case{
+		Type: "CS(OS)", Number: "100", Year: "2023", NextHearing: today,
+		Judge: "Hon'ble Ms. Justice N. Bansal", Petitioners: []string{"A"}, Respondents: []string{"B"},
+	})
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	db := newTestDB(t)
+	log, _ := logger.NewLogger("error", "json")
+	cfg := &config.Config{CourtBaseURL: server.URL, ScraperTimeout: 5 * time.Second}
+	router := gin.New()
+	api.SetupRoutes(router, db, cache.NewCache(100, time.Minute, time.Minute), nil, upstream.Direct(), log, cfg)
+
+	stored := saveTestCase(t, database.NewRepository(db), "CS(OS)/100/2023")
+	saveTestCase(t, database.NewRepository(db), "CS/1/2023")
+
+	get := func(path string, out interface{}) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(w, req)
+		json.Unmarshal(w.Body.Bytes(), out)
+		return w.Code
+	}
+
+	var list struct {
+		Date    string                    `json:"date"`
+		Data    []database.CauseListEntry `json:"data"`
+		Matched int                       `json:"matched"`
+	}
+	if code := get("/api/causelist", &list); code != http.StatusOK || list.Date != now.Format(causelist.DateLayout) ||
+		len(list.Data) != 1 || list.Matched != 1 || *list.Data[0].CaseInfoID != stored.ID {
+		t.Fatalf("Expected today's list matched to the stored case, got %d %+v", code, list)
+	}
+
+	var cases struct {
+		Data []database.CaseInfo `json:"data"`
+	}
+	get("/api/cases?limit=10", &cases)
+	listed := 0
+	for _, caseInfo := range cases.Data {
+		if caseInfo.ListedToday != nil {
+			listed++
+			if caseInfo.CaseNumber != "CS(OS)/100/2023" || caseInfo.ListedToday.CourtRoom != "01" {
+				t.Errorf("Unexpected listing %+v for %s", caseInfo.ListedToday, caseInfo.CaseNumber)
+			}
+		}
+	}
+	if listed != 1 {
+		t.Errorf("Expected one case listed today, got %d", listed)
+	}
+
+	// A stored list is served without asking the court again
+	server.Close()
+	if code := get("/api/causelist?date="+now.Format(causelist.DateLayout), &list); code != http.StatusOK || len(list.Data) != 1 {
+		t.Errorf("Expected the stored list, got %d %+v", code, list)
+	}
+	var failure map[string]interface{}
+	if code := get("/api/causelist?date=2024-03-19", &failure); code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 when the court is unreachable, got %d %v", code, failure)
+	}
+	if code := get("/api/causelist?date=19-03-2024", &failure); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a malformed date, got %d", code)
+	}
+
+	if _, err := causelist.NewService(db, upstream.Direct(), log, cfg).Fetch(context.Background(), today); err == nil {
+		t.Error("Expected Fetch to fail with the court down")
+	}
+}
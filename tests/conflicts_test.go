@@ -0,0 +1,144 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/JustJay7/court-data-fetcher/internal/conflicts"
+	"github.com/JustJay7/court-data-fetcher/internal/database"
+	"github.com/JustJay7/court-data-fetcher/internal/names"
+)
+
+func TestPhoneticName(t *testing.T) {
+	tests := []struct {
+		a, b string
+	}{
+		{"choudhary", "chowdhury"},
+		{"lakshmi", "laxmi"},
+		{"shyam sundar", "syam sunder"},
+		{"mohammad", "muhammad"},
+		{"wasim", "vasim"},
+	}
+
+	for _, tt := range tests {
+		if names.Phonetic(tt.a) != names.Phonetic(tt.b) {
+			t.Errorf("Phonetic(%q) = %q, Phonetic(%q) = %q, want equal", tt.a, names.Phonetic(tt.a), tt.b, names.Phonetic(tt.b))
+		}
+	}
+	if score := names.Match("ramesh chand", "suresh chand"); score >= conflicts.DefaultMinScore {
+		t.Errorf("Different names should not match, got %.2f", score)
+	}
+}
+
+func TestConflictCheck(t *testing.T) {
+	db := newTestDB(t)
+	repo := database.NewRepository(db)
+
+	saveTestCase(t, repo, "CS/1/2023",
+		database.Party{Type: "Petitioner", Name: "Sh. Ramesh Chowdhury", AdvocateName: "Mr. Arvind Nair", AdvocateCode: "D/1234/2010"},
+		database.Party{Type: "Respondent", Name: "Lakshmi Traders Pvt. Ltd."},
+	)
+	// A later snapshot of the same case is reported once
+	saveTestCase(t, repo, "CS/1/2023",
+		database.Party{Type: "Petitioner", Name: "Ramesh Chowdhury", AdvocateName: "Arvind Nair", AdvocateCode: "D/1234/2010"},
+	)
+	saveTestCase(t, repo, "CS/2/2023",
+		database.Party{Type: "Respondent", Name: "Ramesh Choudhary & Ors."},
+	)
+
+	checker := conflicts.NewChecker(db)
+	check, err := checker.Check(conflicts.Request{
+		Names:       []string{"RAMESH CHAUDHARI", "Laxmi Traders Private Limited", "Arvind Nair", "Someone Else", " "},
+		RequestedBy: "intake",
+		Reference:   "M-42",
+		IPAddress:   "10.0.0.1",
+	})
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if len(check.Results) != 4 {
+		t.Fatalf("Expected 4 results for the non-blank names, got %d", len(check.Results))
+	}
+
+	// Entity resolution keeps the two spellings apart, the check finds both
+	ramesh := check.Results[0].Matches
+	if len(ramesh) != 2 || ramesh[0].Kind != "party" || ramesh[1].Kind != "party" {
+		t.Fatalf("Expected two parties matching the transliterated name, got %+v", ramesh)
+	}
+	roles := map[string]string{}
+	for _, match := range ramesh {
+		if match.Score <= 0 || match.Score >= 1 {
+			t.Errorf("Expected a partial match score, got %v", match.Score)
+		}
+		if len(match.Cases) != 1 {
+			t.Errorf("Expected one case per match, got %+v", match.Cases)
+			continue
+		}
+		roles[match.Cases[0].CaseNumber] = match.Cases[0].Role
+	}
+	if roles["CS/1/2023"] != "Petitioner" || roles["CS/2/2023"] != "Respondent" {
+		t.Errorf("Expected both cases with their roles, got %v", roles)
+	}
+
+	if traders := check.Results[1].Matches; len(traders) != 1 || traders[0].Cases[0].Role != "Respondent" {
+		t.Errorf("Expected the company as respondent, got %+v", traders)
+	}
+	if advocate := check.Results[2].Matches; len(advocate) != 1 || advocate[0].Kind != "advocate" || advocate[0].Score != 1 ||
+		len(advocate[0].Cases) != 1 || advocate[0].Cases[0].Role != "Advocate for Petitioner" {
+		t.Errorf("Expected the advocate with one case, got %+v", advocate)
+	}
+	if none := check.Results[3].Matches; len(none) != 0 {
+		t.Errorf("Expected no matches, got %+v", none)
+	}
+
+	// The audit record keeps the request and what was reported
+	checks, total, err := checker.List(0, 10)
+	if err != nil || total != 1 {
+		t.Fatalf("Expected 1 audit record, got %d, %v", total, err)
+	}
+	audit := checks[0]
+	if audit.RequestedBy != "intake" || audit.Reference != "M-42" || audit.IPAddress != "10.0.0.1" ||
+		audit.MatchCount != check.MatchCount || len(audit.Results) != 4 || audit.Results[0].Matches[0].MatchedName != "Sh. Ramesh Chowdhury" {
+		t.Errorf("Unexpected audit record: %+v", audit)
+	}
+}
+
+func TestConflictCheckAPI(t *testing.T) {
+	router, db := setupTestRouter()
+	saveTestCase(t, database.NewRepository(db), "CS/1/2023", database.Party{Type: "Petitioner", Name: "Ramesh Chand"})
+
+	post := func(body string) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/api/conflicts/check", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response
+	}
+
+	code, response := post(`{"names": ["Ramesh Chand"], "reference": "M-1"}`)
+	if code != http.StatusOK || response["match_count"] != float64(1) {
+		t.Errorf("Expected one match, got %d %v", code, response)
+	}
+
+	for _, body := range []string{`{"names": []}`, `{"names": [""]}`, `not json`} {
+		if code, _ := post(body); code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %s, got %d", body, code)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/conflicts/checks", nil)
+	router.ServeHTTP(w, req)
+	var list struct {
+		Data []database.ConflictCheck `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &list)
+	if w.Code != http.StatusOK || len(list.Data) != 1 || list.Data[0].Reference != "M-1" {
+		t.Errorf("Expected the check in the audit trail, got %d %+v", w.Code, list.Data)
+	}
+}
@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/JustJay7/court-data-fetcher/internal/courtdate"
+	"github.com/JustJay7/court-data-fetcher/internal/database"
+)
+
+func TestParseCourtDate(t *testing.T) {
+	got, err := courtdate.Parse("12th November, 2024")
+	if err != nil || !got.Time.Equal(time.Date(2024, time.November, 12, 0, 0, 0, 0, courtdate.IST)) || got.IsRange() || got.Ambiguous != "" {
+		t.Fatalf("Parse = %+v, %v", got, err)
+	}
+	if got.Time.Location() != courtdate.IST || got.Time.UTC().Format(time.RFC3339) != "2024-11-11T18:30:00Z" {
+		t.Errorf("Expected midnight IST, got %v", got.Time)
+	}
+
+	got, err = courtdate.Parse("12 to 14 नवंबर 2024")
+	if err != nil || got.Time.Day() != 12 || got.End.Day() != 14 || got.End.Month() != time.November {
+		t.Errorf("Expected a range of 12-14 November, got %+v, %v", got, err)
+	}
+
+	// Day first is the court convention, a month over 12 means it wasn't followed
+	if got, _ := courtdate.Parse("04/13/2024"); got.Time.Month() != time.April || got.Time.Day() != 13 || got.Ambiguous == "" {
+		t.Errorf("Expected 13 April read month first, got %+v", got)
+	}
+	if got, _ := courtdate.Parse("5-2-98"); got.Time.Year() != 1998 || got.Ambiguous == "" {
+		t.Errorf("Expected a 1998 date flagged as a guess, got %+v", got)
+	}
+
+	if _, err := courtdate.Parse("next date"); !errors.Is(err, courtdate.ErrInvalidDate) {
+		t.Errorf("Expected ErrInvalidDate, got %v", err)
+	}
+}
+
+func TestParseReportDateIssues(t *testing.T) {
+	parser := newTestParser(t)
+	caseInfo, err := parser.ParseCaseDetailsHTML(`<div class="case-info"><table>
+		<tr><td>Case Number</td><td>CS/12/2024</td></tr>
+		<tr><td>Filing Date</td><td>04/13/2024</td></tr>
+		<tr><td>Next Date</td><td>To be notified</td></tr>
+	</table></div>`)
+	if err != nil {
+		t.Fatalf("ParseCaseDetailsHTML failed: %v", err)
+	}
+	want := []database.DateIssue{
+		{Field: "filing_date", Value: "04/13/2024", Problem: database.DateAmbiguous, Detail: "read month first"},
+		{Field: "next_hearing", Value: "To be notified", Problem: database.DateUnreadable},
+	}
+	issues := caseInfo.ParseReport.DateIssues
+	if len(issues) != len(want) || issues[0] != want[0] || issues[1] != want[1] {
+		t.Errorf("DateIssues = %+v, want %+v", issues, want)
+	}
+
+	report := &database.ParseReport{}
+	orders, err := parser.ParseOrdersHTML(`<table id="order_table"><tr><th>Date</th><th>Order</th></tr>
+		<tr><td>12-14 March 2024</td><td>Evidence recorded</td></tr></table>`, parserFixtureURL, report)
+	if err != nil || len(orders) != 1 || orders[0].OrderDate.Day() != 12 {
+		t.Fatalf("Expected the order dated from the first day, got %+v, %v", orders, err)
+	}
+	if len(report.DateIssues) != 1 || report.DateIssues[0].Problem != database.DateRange {
+		t.Errorf("Expected the range noted, got %+v", report.DateIssues)
+	}
+}
@@ -0,0 +1,219 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/JustJay7/court-data-fetcher/internal/database"
+	"github.com/JustJay7/court-data-fetcher/internal/names"
+)
+
+func TestCanonicalName(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"Ramesh Chand", "ramesh chand"},
+		{"  SH.  RAMESH  CHAND ", "ramesh chand"},
+		{"Shri Ramesh Chand & Ors.", "ramesh chand"},
+		{"M/s. Sharma Pvt. Ltd. & Anr.", "sharma private limited"},
+		{"Messrs Sharma Private Limited", "sharma private limited"},
+		{"Union of India", "union of india"},
+		{"U.O.I.", "union of india"},
+		{"Union of India through its Secretary, Ministry of Finance", "union of india"},
+		{"The Union of India & Ors. etc.", "union of india"},
+		{"State (NCT of Delhi)", "state nct of delhi"},
+		{"State", "state nct of delhi"},
+		{"Govt. of NCT of Delhi", "government of nct of delhi"},
+		{"Ms. Kavita Rao, CGSC", "kavita rao"},
+		{"Mohd. Irfan", "mohammad irfan"},
+		{"A.K. Jain", "ak jain"},
+		{"P. Kumar", "p kumar"},
+		{"Others", "others"},
+		{"M/s.", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := names.Canonical(tt.input); got != tt.want {
+				t.Errorf("Canonical(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNameSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		match bool
+	}{
+		{"ramesh chand", "ramesh chand", true},
+		{"kumar rajesh", "rajesh kumar", true},
+		{"rajesh kumar sharma", "rajesh kumar sharmaa", true},
+		{"mohammad irfan", "mohammed irfan", true},
+		{"ramesh chand", "suresh chand", false},
+		{"rajesh kumar", "ramesh kumar", false},
+		{"amit kumar", "amir kumar", false},
+		{"kumar rajesh", "ramesh kumar", false},
+		{"union of india", "state nct of delhi", false},
+	}
+
+	for _, tt := range tests {
+		if got := names.SamePerson(tt.a, tt.b); got != tt.match {
+			t.Errorf("SamePerson(%q, %q) = %v want %v, similarity %.2f", tt.a, tt.b, got, tt.match, names.Similarity(tt.a, tt.b))
+		}
+	}
+
+	if got := names.AdvocateCode(" d / 1234-2010 "); got != "D/1234/2010" {
+		t.Errorf("AdvocateCode = %q, want D/1234/2010", got)
+	}
+}
+
+// saveTestCase stores a scraped case with the given parties
+func saveTestCase(t *testing.T, repo *database.Repository, caseNumber string, parties ...database.Party) *database.CaseInfo {
+	t.Helper()
+	queryLog := &database.QueryLog{CaseNumber: caseNumber}
+	if err := repo.CreateQueryLog(queryLog); err != nil {
+		t.Fatalf("CreateQueryLog failed: %v", err)
+	}
+	caseInfo := &database.CaseInfo{CaseNumber: caseNumber, Parties: parties}
+	if err := repo.SaveCaseSnapshot(queryLog, caseInfo); err != nil {
+		t.Fatalf("SaveCaseSnapshot failed: %v", err)
+	}
+	return caseInfo
+}
+
+func TestPartiesResolveToEntities(t *testing.T) {
+	db := newTestDB(t)
+	repo := database.NewRepository(db)
+
+	first := saveTestCase(t, repo, "CS/1/2023",
+		database.Party{Type: "Petitioner", Name: "Sh. Rajesh Kumar Sharma", AdvocateName: "Mr. Arvind Nair"},
+		database.Party{Type: "Respondent", Name: "Union of India", AdvocateName: "Ms. Kavita Rao", AdvocateCode: "D/567/2015"},
+	)
+	second := saveTestCase(t, repo, "CS/2/2023",
+		database.Party{Type: "Petitioner", Name: "RAJESH KUMAR SHARMAA & ORS.", AdvocateName: "Arvind Nair, Adv.", AdvocateCode: "D/1234/2010"},
+		database.Party{Type: "Respondent", Name: "U.O.I. through Secretary", AdvocateName: "Kavita Rao", AdvocateCode: "d/567/2015"},
+	)
+	// A different advocate of the same name with another code stays separate
+	third := saveTestCase(t, repo, "CS/3/2023",
+		database.Party{Type: "Petitioner", Name: "Suresh Chand", AdvocateName: "Arvind Nair", AdvocateCode: "D/999/2020"},
+	)
+
+	for i := range first.Parties {
+		a, b := first.Parties[i], second.Parties[i]
+		if a.PersonID == nil || b.PersonID == nil || *a.PersonID != *b.PersonID {
+			t.Errorf("Expected %q and %q to be one person", a.Name, b.Name)
+		}
+		if a.AdvocateID == nil || b.AdvocateID == nil || *a.AdvocateID != *b.AdvocateID {
+			t.Errorf("Expected %q and %q to be one advocate", a.AdvocateName, b.AdvocateName)
+		}
+	}
+	if *third.Parties[0].AdvocateID == *first.Parties[0].AdvocateID {
+		t.Error("Advocates with different codes should not be merged")
+	}
+
+	// The advocate first seen without a code picks it up later
+	advocate, err := repo.FindAdvocateByCode("D/1234/2010")
+	if err != nil || advocate.ID != *first.Parties[0].AdvocateID {
+		t.Errorf("Expected the code to be recorded on the first advocate, got %+v, %v", advocate, err)
+	}
+
+	var persons, advocates int64
+	db.Model(&database.Person{}).Count(&persons)
+	db.Model(&database.Advocate{}).Count(&advocates)
+	if persons != 3 || advocates != 3 {
+		t.Errorf("Expected 3 persons and 3 advocates, got %d and %d", persons, advocates)
+	}
+}
+
+func TestPartyAndAdvocateCasesAPI(t *testing.T) {
+	router, db := setupTestRouter()
+	repo := database.NewRepository(db)
+
+	first := saveTestCase(t, repo, "CS/1/2023",
+		database.Party{Type: "Petitioner", Name: "Ramesh Chand", AdvocateName: "Arvind Nair", AdvocateCode: "D/1234/2010"},
+	)
+	// A later scrape of the same case replaces it in the listing
+	saveTestCase(t, repo, "CS/1/2023",
+		database.Party{Type: "Petitioner", Name: "Ramesh Chand", AdvocateName: "Arvind Nair", AdvocateCode: "D/1234/2010"},
+	)
+	saveTestCase(t, repo, "CS/2/2023",
+		database.Party{Type: "Respondent", Name: "Sh. Ramesh Chand"},
+	)
+	uncoded := saveTestCase(t, repo, "CS/3/2023",
+		database.Party{Type: "Petitioner", Name: "Suresh Chand", AdvocateName: "Kavita Rao"},
+	)
+	// A purely numeric code that is also another advocate's ID
+	saveTestCase(t, repo, "CS/4/2023",
+		database.Party{Type: "Respondent", Name: "Mohan Lal", AdvocateName: "Neha Gupta", AdvocateCode: "1"},
+	)
+
+	get := func(path string) (int, []database.CaseInfo) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(w, req)
+		var response struct {
+			Data []database.CaseInfo `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response.Data
+	}
+
+	personID := *first.Parties[0].PersonID
+	code, cases := get("/api/parties/" + strconv.FormatUint(uint64(personID), 10) + "/cases")
+	if code != http.StatusOK || len(cases) != 2 || cases[0].CaseNumber != "CS/2/2023" || cases[1].CaseNumber != "CS/1/2023" {
+		t.Errorf("Expected the person's two cases, got %d %+v", code, cases)
+	}
+
+	code, cases = get("/api/advocates/D/1234/2010/cases")
+	if code != http.StatusOK || len(cases) != 1 || cases[0].CaseNumber != "CS/1/2023" {
+		t.Errorf("Expected the advocate's case, got %d %+v", code, cases)
+	}
+
+	code, cases = get("/api/advocates/id/" + strconv.FormatUint(uint64(*uncoded.Parties[0].AdvocateID), 10) + "/cases")
+	if code != http.StatusOK || len(cases) != 1 || cases[0].CaseNumber != "CS/3/2023" {
+		t.Errorf("Expected the uncoded advocate's case by ID, got %d %+v", code, cases)
+	}
+
+	code, cases = get("/api/advocates/1/cases")
+	if code != http.StatusOK || len(cases) != 1 || cases[0].CaseNumber != "CS/4/2023" {
+		t.Errorf("Expected a numeric code to be looked up as a code, got %d %+v", code, cases)
+	}
+
+	for _, path := range []string{"/api/parties/999/cases", "/api/advocates/D/1/1999/cases", "/api/advocates/D/1234/2010", "/api/advocates/id/x/cases"} {
+		if code, _ := get(path); code != http.StatusNotFound {
+			t.Errorf("Expected 404 for %s, got %d", path, code)
+		}
+	}
+}
+
+func TestMigrateLinksExistingParties(t *testing.T) {
+	migrator, db := newTestMigrator(t)
+	if err := migrator.To(7); err != nil {
+		t.Fatalf("To(7) failed: %v", err)
+	}
+
+	for _, name := range []string{"Ramesh Chand", "Sh. Ramesh Chand & Ors."} {
+		if err := db.Exec("INSERT INTO parties (case_info_id, name, advocate_code) VALUES (?, ?, ?)", 1, name, "D/1234/2010").Error; err != nil {
+			t.Fatalf("Failed to insert legacy party: %v", err)
+		}
+	}
+
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+
+	var parties []database.Party
+	db.Order("id").Find(&parties)
+	if len(parties) != 2 || parties[0].PersonID == nil || parties[1].PersonID == nil || *parties[0].PersonID != *parties[1].PersonID {
+		t.Fatalf("Expected both parties linked to one person, got %+v", parties)
+	}
+	if parties[0].AdvocateID == nil || *parties[0].AdvocateID != *parties[1].AdvocateID {
+		t.Errorf("Expected both parties linked to one advocate, got %+v", parties)
+	}
+}
@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/JustJay7/court-data-fetcher/internal/courtdate"
+	"github.com/JustJay7/court-data-fetcher/internal/database"
+	"github.com/JustJay7/court-data-fetcher/internal/scraper"
+	"github.com/JustJay7/court-data-fetcher/pkg/logger"
+)
+
+// date returns midnight IST, as court dates are parsed
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, courtdate.IST)
+}
+
+func TestFindHearingDirection(t *testing.T) {
+	parser := newTestParser(t)
+	tests := []struct {
+		text   string
+		want   time.Time
+		phrase string
+	}{
+		{"Reply filed. List on 12.11.2024.", date(2024, time.November, 12), "List on 12.11.2024"},
+		{"Not taken up. Renotify on 3rd March, 2025.", date(2025, time.March, 3), "Renotify on 3rd March, 2025"},
+		{"Adjourned to 5-2-24 at the request of counsel.", date(2024, time.February, 5), "Adjourned to 5-2-24"},
+		{"List the matter for final arguments on Nov. 12, 2024.", date(2024, time.November, 12), "List the matter for final arguments on Nov. 12, 2024"},
+		// The last direction wins over dates the order quotes earlier
+		{"Order dated 01.02.2024 perused. Adjourned to 15/04/2024.", date(2024, time.April, 15), "Adjourned to 15/04/2024"},
+	}
+	for _, tt := range tests {
+		direction, ok := parser.FindHearingDirection(tt.text)
+		if !ok || !direction.Date.Equal(tt.want) || direction.Phrase != tt.phrase {
+			t.Errorf("FindHearingDirection(%q) = %v %q, %v", tt.text, direction.Date, direction.Phrase, ok)
+		}
+	}
+
+	for _, text := range []string{"Notice issued. Reply within four weeks.", "List on the next date.", "Adjourned to 31.02.2024."} {
+		if direction, ok := parser.FindHearingDirection(text); ok {
+			t.Errorf("Expected no direction in %q, got %+v", text, direction)
+		}
+	}
+}
+
+func TestInferNextHearing(t *testing.T) {
+	parser := newTestParser(t)
+	orders := []database.Order{
+		{OrderDate: date(2024, time.March, 1), Description: "Notice issued. List on 10.04.2024."},
+		{OrderDate: date(2024, time.April, 10), Description: "Reply filed. Renotify on 22nd May, 2024."},
+	}
+
+	caseInfo := &database.CaseInfo{
+		Orders:      orders,
+		ParseReport: &database.ParseReport{Fields: map[string]string{"case_number": scraper.StrategyTable}},
+	}
+	if !parser.InferNextHearing(caseInfo) {
+		t.Fatal("Expected the next hearing to be read from the orders")
+	}
+	if !caseInfo.NextHearing.Equal(date(2024, time.May, 22)) || caseInfo.NextHearingSource != database.NextHearingFromOrder ||
+		caseInfo.NextHearingNote != "Renotify on 22nd May, 2024" {
+		t.Errorf("Unexpected next hearing %v %q %q", caseInfo.NextHearing, caseInfo.NextHearingSource, caseInfo.NextHearingNote)
+	}
+	if caseInfo.ParseReport.Fields["next_hearing"] != scraper.StrategyOrders {
+		t.Errorf("Expected the report to credit the orders, got %+v", caseInfo.ParseReport.Fields)
+	}
+
+	// A page date older than the latest order is stale
+	stale := &database.CaseInfo{Orders: orders, NextHearing: date(2024, time.April, 10), NextHearingSource: database.NextHearingFromPage}
+	if !parser.InferNextHearing(stale) || !stale.NextHearing.Equal(date(2024, time.May, 22)) {
+		t.Errorf("Expected the stale page date to be replaced, got %v", stale.NextHearing)
+	}
+
+	// A page date after the latest order is kept
+	current := &database.CaseInfo{Orders: orders, NextHearing: date(2024, time.June, 3), NextHearingSource: database.NextHearingFromPage}
+	if parser.InferNextHearing(current) || !current.NextHearing.Equal(date(2024, time.June, 3)) || current.NextHearingSource != database.NextHearingFromPage {
+		t.Errorf("Expected the page date to be kept, got %v from %s", current.NextHearing, current.NextHearingSource)
+	}
+
+	// Directions that are already past say nothing about the next hearing
+	past := &database.CaseInfo{Orders: orders[:1]}
+	past.Orders = append(past.Orders, database.Order{OrderDate: date(2024, time.April, 10), Description: "Arguments heard."})
+	if parser.InferNextHearing(past) || !past.NextHearing.IsZero() {
+		t.Errorf("Expected no next hearing from a past direction, got %v", past.NextHearing)
+	}
+}
+
+func TestNextHearingFromOrderPDF(t *testing.T) {
+	db := newTestDB(t)
+	repo := database.NewRepository(db)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Write(testPDF("Counsel for the respondent seeks time.", "Adjourned to 14.08.2024 for arguments."))
+	}))
+	defer server.Close()
+
+	queryLog := &database.QueryLog{CaseNumber: "CS(OS)/42/2024"}
+	repo.CreateQueryLog(queryLog)
+	caseInfo := &database.CaseInfo{
+		CaseNumber: "CS(OS)/42/2024",
+		Orders:     []database.Order{{OrderDate: date(2024, time.July, 2), Description: "Order dated 02-07-2024", PDFLink: server.URL + "/order.pdf"}},
+	}
+	if err := repo.SaveCaseSnapshot(queryLog, caseInfo); err != nil {
+		t.Fatalf("SaveCaseSnapshot failed: %v", err)
+	}
+
+	log, _ := logger.NewLogger("error", "json")
+	if err := scraper.NewPDFDownloader(db, log, t.TempDir()).DownloadOrderPDFs(); err != nil {
+		t.Fatalf("DownloadOrderPDFs failed: %v", err)
+	}
+
+	var stored database.CaseInfo
+	db.First(&stored, caseInfo.ID)
+	if !stored.NextHearing.Equal(date(2024, time.August, 14)) || stored.NextHearingSource != database.NextHearingFromOrderPDF ||
+		stored.NextHearingNote != "Adjourned to 14.08.2024" {
+		t.Errorf("Unexpected next hearing %v %q %q", stored.NextHearing, stored.NextHearingSource, stored.NextHearingNote)
+	}
+}
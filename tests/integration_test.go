@@ -8,6 +8,7 @@ import (
 
 	"github.com/JustJay7/court-data-fetcher/internal/config"
 	"github.com/JustJay7/court-data-fetcher/internal/scraper"
+	"github.com/JustJay7/court-data-fetcher/internal/upstream"
 	"github.com/JustJay7/court-data-fetcher/pkg/logger"
 )
 
@@ -16,6 +17,7 @@ func TestDelhiDistrictCourtIntegration(t *testing.T) {
 	if os.Getenv("SKIP_INTEGRATION_TESTS") == "true" || testing.Short() {
 		t.Skip("Skipping integration test")
 	}
+	requireBrowser(t)
 
 	// Test configuration
 	cfg := &config.Config{
@@ -32,7 +34,7 @@ func TestDelhiDistrictCourtIntegration(t *testing.T) {
 	}
 
 	// Initialize scraper
-	s, err := scraper.NewScraper(cfg, log)
+	s, err := scraper.NewScraper(cfg, upstream.Direct(), log)
 	if err != nil {
 		t.Fatalf("Failed to create scraper: %v", err)
 	}
@@ -138,6 +140,7 @@ func TestParserWithSampleHTML(t *testing.T) {
 
 	// This would test the parser directly with known HTML
 	// Implementation depends on exposing parser methods for testing
+	_ = sampleHTML
 }
 
 func TestCAPTCHAServices(t *testing.T) {
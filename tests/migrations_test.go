@@ -0,0 +1,222 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/JustJay7/court-data-fetcher/internal/database"
+	"gorm.io/gorm"
+)
+
+func newTestMigrator(t *testing.T) (*database.Migrator, *gorm.DB) {
+	t.Helper()
+	db := newTestDB(t)
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		t.Fatalf("Failed to load migrations: %v", err)
+	}
+	return migrator, db
+}
+
+func TestMigrateUpDown(t *testing.T) {
+	migrator, db := newTestMigrator(t)
+
+	version, err := migrator.Version()
+	if err != nil {
+		t.Fatalf("Version failed: %v", err)
+	}
+	if version != migrator.Latest() {
+		t.Fatalf("Expected version %d after Migrate, got %d", migrator.Latest(), version)
+	}
+
+	if err := migrator.Down(); err != nil {
+		t.Fatalf("Down failed: %v", err)
+	}
+	if version, _ := migrator.Version(); version >= migrator.Latest() {
+		t.Errorf("Expected Down to roll back one migration, still at %d", version)
+	}
+
+	if err := migrator.To(0); err != nil {
+		t.Fatalf("To(0) failed: %v", err)
+	}
+	if db.Migrator().HasTable(&database.CaseInfo{}) {
+		t.Error("Expected tables to be dropped at version 0")
+	}
+
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+	statuses, err := migrator.Status()
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	for _, status := range statuses {
+		if !status.Applied || status.Modified {
+			t.Errorf("Expected %04d_%s to be applied cleanly", status.Version, status.Name)
+		}
+	}
+
+	if err := migrator.To(migrator.Latest() + 1); !errors.Is(err, database.ErrUnknownVersion) {
+		t.Errorf("Expected ErrUnknownVersion, got %v", err)
+	}
+}
+
+func TestMigrateRefusesNewerSchema(t *testing.T) {
+	migrator, db := newTestMigrator(t)
+
+	db.Create(&database.SchemaMigration{Version: migrator.Latest() + 1, Name: "future", Checksum: "x"})
+
+	if err := database.Migrate(db); !errors.Is(err, database.ErrSchemaTooNew) {
+		t.Errorf("Expected ErrSchemaTooNew, got %v", err)
+	}
+}
+
+func TestMigrateDetectsEditedMigration(t *testing.T) {
+	migrator, db := newTestMigrator(t)
+
+	db.Model(&database.SchemaMigration{}).Where("version = ?", 1).Update("checksum", "edited")
+
+	if err := migrator.Verify(); !errors.Is(err, database.ErrChecksumMismatch) {
+		t.Errorf("Expected ErrChecksumMismatch, got %v", err)
+	}
+	statuses, _ := migrator.Status()
+	if len(statuses) == 0 || !statuses[0].Modified {
+		t.Error("Expected status to flag the edited migration")
+	}
+}
+
+// The tables as AutoMigrate created them before versioned migrations
+type legacyQueryLog struct {
+	gorm.Model
+	CaseType     string
+	CaseNumber   string
+	FilingYear   string
+	RawResponse  string `gorm:"type:text"`
+	Success      bool
+	ErrorMessage string
+	QueryTime    time.Time
+	IPAddress    string
+}
+
+type legacyCaseInfo struct {
+	gorm.Model
+	QueryLogID   uint
+	CaseNumber   string `gorm:"index"`
+	CaseType     string
+	FilingYear   string
+	FilingDate   time.Time
+	NextHearing  time.Time
+	Status       string
+	Judge        string
+	CourtComplex string
+	Parties      []legacyParty `gorm:"foreignKey:CaseInfoID"`
+	Orders       []legacyOrder `gorm:"foreignKey:CaseInfoID"`
+}
+
+type legacyParty struct {
+	gorm.Model
+	CaseInfoID   uint
+	Name         string
+	Type         string
+	AdvocateName string
+	AdvocateCode string
+}
+
+type legacyOrder struct {
+	gorm.Model
+	CaseInfoID  uint
+	OrderDate   time.Time
+	Description string
+	PDFLink     string
+	OrderType   string
+	JudgeName   string
+	Downloaded  bool
+	LocalPath   string
+}
+
+func (legacyQueryLog) TableName() string { return "query_logs" }
+func (legacyCaseInfo) TableName() string { return "case_infos" }
+func (legacyParty) TableName() string    { return "parties" }
+func (legacyOrder) TableName() string    { return "orders" }
+
+func TestMigrateAdoptsAutoMigratedDatabase(t *testing.T) {
+	migrator, db := newTestMigrator(t)
+	if err := migrator.To(0); err != nil {
+		t.Fatalf("To(0) failed: %v", err)
+	}
+	db.Migrator().DropTable(&database.SchemaMigration{})
+
+	// Databases created before versioned migrations have the original tables
+	if err := db.AutoMigrate(&legacyQueryLog{}, &legacyCaseInfo{}, &legacyOrder{}, &legacyParty{}); err != nil {
+		t.Fatalf("Failed to create legacy schema: %v", err)
+	}
+	legacy := legacyCaseInfo{
+		CaseNumber: "1234",
+		CaseType:   "CS(OS)",
+		FilingYear: "2023",
+		Parties:    []legacyParty{{Name: "Rajesh Kumar", Type: "petitioner"}},
+	}
+	if err := db.Create(&legacyQueryLog{CaseNumber: "1234", Success: true}).Error; err != nil {
+		t.Fatalf("Failed to insert legacy query log: %v", err)
+	}
+	if err := db.Create(&legacy).Error; err != nil {
+		t.Fatalf("Failed to insert legacy case: %v", err)
+	}
+
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("Migrate failed on existing schema: %v", err)
+	}
+
+	for _, column := range []string{"error_code", "source"} {
+		if !db.Migrator().HasColumn(&database.QueryLog{}, column) {
+			t.Errorf("Expected query_logs.%s to be added", column)
+		}
+	}
+	var caseInfo database.CaseInfo
+	if err := db.Preload("Parties").First(&caseInfo).Error; err != nil {
+		t.Fatalf("Failed to load legacy case: %v", err)
+	}
+	if caseInfo.CaseNumber != "1234" || len(caseInfo.Parties) != 1 {
+		t.Errorf("Expected legacy case to survive, got %+v", caseInfo)
+	}
+}
+
+func TestMigrateMovesRawResponses(t *testing.T) {
+	migrator, db := newTestMigrator(t)
+	if err := migrator.To(3); err != nil {
+		t.Fatalf("To(3) failed: %v", err)
+	}
+
+	for _, page := range []string{"<html>a</html>", "<html>a</html>", "<html>b</html>"} {
+		if err := db.Exec("INSERT INTO query_logs (case_number, raw_response) VALUES (?, ?)", "1234", page).Error; err != nil {
+			t.Fatalf("Failed to insert legacy query log: %v", err)
+		}
+	}
+
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+
+	var blobs int64
+	db.Model(&database.RawResponseBlob{}).Count(&blobs)
+	if blobs != 2 {
+		t.Errorf("Expected 2 distinct pages, got %d", blobs)
+	}
+
+	var queryLog database.QueryLog
+	db.First(&queryLog)
+	if html, err := database.NewRepository(db).RawResponse(&queryLog); err != nil || html != "<html>a</html>" {
+		t.Errorf("RawResponse = %q, %v", html, err)
+	}
+
+	// Rolling back puts the pages back on query_logs
+	if err := migrator.To(3); err != nil {
+		t.Fatalf("To(3) failed: %v", err)
+	}
+	var restored []string
+	db.Raw("SELECT raw_response FROM query_logs ORDER BY id").Scan(&restored)
+	if len(restored) != 3 || restored[2] != "<html>b</html>" {
+		t.Errorf("Expected pages restored on rollback, got %v", restored)
+	}
+}
@@ -0,0 +1,295 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JustJay7/court-data-fetcher/internal/config"
+	"github.com/JustJay7/court-data-fetcher/internal/mockcourt"
+	"github.com/JustJay7/court-data-fetcher/internal/scraper"
+	"github.com/JustJay7/court-data-fetcher/internal/upstream"
+	"github.com/JustJay7/court-data-fetcher/pkg/logger"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// getPage fetches a mock court page and parses it
+func getPage(t *testing.T, target string) *goquery.Document {
+	t.Helper()
+	resp, err := http.Get(target)
+	return readPage(t, resp, err)
+}
+
+// postPage submits a form to the mock court and parses the response
+func postPage(t *testing.T, target string, values url.Values) *goquery.Document {
+	t.Helper()
+	resp, err := http.PostForm(target, values)
+	return readPage(t, resp, err)
+}
+
+func readPage(t *testing.T, resp *http.Response, err error) *goquery.Document {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		t.Fatalf("Failed to parse page: %v", err)
+	}
+	return doc
+}
+
+// submitSearch loads the form and submits it the way a browser would
+func submitSearch(t *testing.T, baseURL, caseType, caseNumber, year string) *goquery.Document {
+	t.Helper()
+	form := getPage(t, baseURL+mockcourt.StatusPath)
+
+	for _, selector := range []string{"#case_type", "#case_number", "#case_year", "#captchaInput", "#search"} {
+		if form.Find(selector).Length() == 0 {
+			t.Fatalf("Form is missing %s", selector)
+		}
+	}
+	token, _ := form.Find("input[name='captcha_token']").Attr("value")
+
+	return postPage(t, baseURL+mockcourt.StatusPath, url.Values{
+		"case_type":     {caseType},
+		"case_number":   {caseNumber},
+		"case_year":     {year},
+		"captcha_token": {token},
+		"captchaInput":  {form.Find("#captcha-code").Text()},
+	})
+}
+
+func TestMockCourtLayouts(t *testing.T) {
+	mock := mockcourt.New(mockcourt.Config{})
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	parser := newTestParser(t)
+
+	for _, layout := range []mockcourt.Layout{mockcourt.LayoutTable, mockcourt.LayoutDivs, mockcourt.LayoutText} {
+		t.Run(string(layout), func(t *testing.T) {
+			mock.SetConfig(mockcourt.Config{Layout: layout})
+
+			results := submitSearch(t, server.URL, "W.P.(C)", "1234", "2023")
+			view, ok := results.Find("table.table a[href*='view']").Attr("href")
+			if !ok {
+				t.Fatal("Results page has no view link")
+			}
+
+			resp, err := http.Get(server.URL + view)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+
+			caseInfo, err := parser.ParseCaseDetailsHTML(string(body))
+			if err != nil {
+				t.Fatalf("Failed to parse %s layout: %v", layout, err)
+			}
+
+			if caseInfo.CaseNumber != "W.P.(C)/1234/2023" {
+				t.Errorf("Expected case number W.P.(C)/1234/2023, got %s", caseInfo.CaseNumber)
+			}
+			if caseInfo.CNR != "DLHC010012342023" {
+				t.Errorf("Expected CNR DLHC010012342023, got %q", caseInfo.CNR)
+			}
+			if got := caseInfo.NextHearing.Format("2006-01-02"); got != "2024-03-18" {
+				t.Errorf("Expected next hearing 2024-03-18, got %s", got)
+			}
+			if len(caseInfo.Parties) != 4 {
+				t.Errorf("Expected 4 parties, got %d: %+v", len(caseInfo.Parties), caseInfo.Parties)
+			}
+		})
+	}
+}
+
+func TestMockCourtOrders(t *testing.T) {
+	server := httptest.NewServer(mockcourt.New(mockcourt.Config{}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/app/case-orders?id=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	orders, err := newTestParser(t).ParseOrdersHTML(string(body), server.URL+"/app/case-orders?id=1", nil)
+	if err != nil {
+		t.Fatalf("Failed to parse orders: %v", err)
+	}
+	if len(orders) != 3 {
+		t.Fatalf("Expected 3 orders, got %d", len(orders))
+	}
+
+	pdf, err := http.Get(orders[0].PDFLink)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pdf.Body.Close()
+	data, _ := io.ReadAll(pdf.Body)
+	if pdf.Header.Get("Content-Type") != "application/pdf" || !strings.HasPrefix(string(data), "%PDF-") {
+		t.Errorf("Expected a PDF from %s, got %s", orders[0].PDFLink, pdf.Header.Get("Content-Type"))
+	}
+}
+
+func TestMockCourtScenarios(t *testing.T) {
+	mock := mockcourt.New(mockcourt.Config{})
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	t.Run("unknown case", func(t *testing.T) {
+		page := submitSearch(t, server.URL, "W.P.(C)", "9999", "2023")
+		if msg := page.Find(".alert-danger").Text(); !strings.Contains(msg, "No record found") {
+			t.Errorf("Expected a not found message, got %q", msg)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		mock.SetConfig(mockcourt.Config{Scenario: mockcourt.ScenarioNotFound})
+		page := submitSearch(t, server.URL, "W.P.(C)", "1234", "2023")
+		if msg := page.Find(".alert-danger").Text(); !strings.Contains(msg, "No record found") {
+			t.Errorf("Expected a not found message, got %q", msg)
+		}
+	})
+
+	t.Run("captcha rejected", func(t *testing.T) {
+		mock.SetConfig(mockcourt.Config{Scenario: mockcourt.ScenarioCaptchaRejected})
+		page := submitSearch(t, server.URL, "W.P.(C)", "1234", "2023")
+		if msg := page.Find(".alert-danger").Text(); !strings.Contains(msg, "Invalid Captcha") {
+			t.Errorf("Expected a CAPTCHA error, got %q", msg)
+		}
+	})
+
+	t.Run("wrong captcha", func(t *testing.T) {
+		mock.SetConfig(mockcourt.Config{})
+		page := postPage(t, server.URL+mockcourt.StatusPath, url.Values{
+			"case_type":    {"W.P.(C)"},
+			"case_number":  {"1234"},
+			"case_year":    {"2023"},
+			"captchaInput": {"0000"},
+		})
+		if msg := page.Find(".alert-danger").Text(); !strings.Contains(msg, "Invalid Captcha") {
+			t.Errorf("Expected a CAPTCHA error, got %q", msg)
+		}
+	})
+
+	t.Run("captcha image", func(t *testing.T) {
+		mock.SetConfig(mockcourt.Config{CaptchaImage: true})
+		form := getPage(t, server.URL+mockcourt.StatusPath)
+		if form.Find("#captcha-code").Length() != 0 {
+			t.Error("Image CAPTCHA should not print the code")
+		}
+		src, ok := form.Find("img#captcha_image").Attr("src")
+		if !ok {
+			t.Fatal("Form has no CAPTCHA image")
+		}
+
+		resp, err := http.Get(server.URL + src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if _, err := png.Decode(resp.Body); err != nil {
+			t.Errorf("CAPTCHA image is not a PNG: %v", err)
+		}
+	})
+
+	t.Run("slow", func(t *testing.T) {
+		mock.SetConfig(mockcourt.Config{Delay: 200 * time.Millisecond})
+		start := time.Now()
+		getPage(t, server.URL+mockcourt.StatusPath)
+		if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+			t.Errorf("Expected the response to be delayed, took %v", elapsed)
+		}
+	})
+
+	if mock.Searches() != 4 {
+		t.Errorf("Expected 4 searches, got %d", mock.Searches())
+	}
+}
+
+func TestScraperAgainstMockCourt(t *testing.T) {
+	requireBrowser(t)
+
+	mock := mockcourt.New(mockcourt.Config{})
+	server := httptest.NewServer(mock)
+	defer server.Close()
+
+	log, err := logger.NewLogger("error", "text")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	s, err := scraper.NewScraper(&config.Config{
+		CourtBaseURL:   server.URL,
+		BrowserPath:    os.Getenv("ROD_BROWSER_PATH"),
+		HeadlessMode:   true,
+		ScraperTimeout: 60 * time.Second,
+		UserAgent:      "Test User Agent",
+	}, upstream.Direct(), log)
+	if err != nil {
+		t.Fatalf("Failed to create scraper: %v", err)
+	}
+	defer s.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	for _, layout := range []mockcourt.Layout{mockcourt.LayoutTable, mockcourt.LayoutDivs, mockcourt.LayoutText} {
+		t.Run("found "+string(layout), func(t *testing.T) {
+			mock.SetConfig(mockcourt.Config{Layout: layout})
+			caseInfo, _, err := s.SearchCase(ctx, "W.P.(C)", "1234", "2023")
+			if err != nil {
+				t.Fatalf("Search failed: %v", err)
+			}
+			if caseInfo.CaseNumber != "W.P.(C)/1234/2023" {
+				t.Errorf("Expected case W.P.(C)/1234/2023, got %s", caseInfo.CaseNumber)
+			}
+			if len(caseInfo.Orders) != 3 {
+				t.Errorf("Expected 3 orders, got %d", len(caseInfo.Orders))
+			}
+		})
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		mock.SetConfig(mockcourt.Config{Scenario: mockcourt.ScenarioNotFound})
+		_, _, err := s.SearchCase(ctx, "W.P.(C)", "1234", "2023")
+		if !errors.Is(err, scraper.ErrCaseNotFound) {
+			t.Errorf("Expected ErrCaseNotFound, got %v", err)
+		}
+	})
+
+	t.Run("captcha rejected", func(t *testing.T) {
+		mock.SetConfig(mockcourt.Config{Scenario: mockcourt.ScenarioCaptchaRejected})
+		_, _, err := s.SearchCase(ctx, "W.P.(C)", "1234", "2023")
+		if !errors.Is(err, scraper.ErrCaptchaFailed) {
+			t.Errorf("Expected ErrCaptchaFailed, got %v", err)
+		}
+	})
+
+	t.Run("slow court", func(t *testing.T) {
+		mock.SetConfig(mockcourt.Config{Delay: 10 * time.Second})
+		defer mock.SetConfig(mockcourt.Config{})
+
+		searchCtx, searchCancel := context.WithTimeout(ctx, 3*time.Second)
+		defer searchCancel()
+		_, _, err := s.SearchCase(searchCtx, "W.P.(C)", "1234", "2023")
+		if !errors.Is(err, scraper.ErrTimeout) {
+			t.Errorf("Expected ErrTimeout, got %v", err)
+		}
+	})
+}
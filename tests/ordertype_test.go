@@ -0,0 +1,213 @@
+package tests
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JustJay7/court-data-fetcher/internal/database"
+	"github.com/JustJay7/court-data-fetcher/internal/mockcourt"
+	"github.com/JustJay7/court-data-fetcher/internal/ordertype"
+	"github.com/JustJay7/court-data-fetcher/internal/scraper"
+	"github.com/JustJay7/court-data-fetcher/pkg/logger"
+)
+
+func TestClassifyOrders(t *testing.T) {
+	classifier := ordertype.Default()
+	tests := []struct {
+		description string
+		want        string
+	}{
+		{"Notice issued. Reply within four weeks.", ordertype.NoticeIssued},
+		{"Issue summons to the defendant on filing of PF.", ordertype.NoticeIssued},
+		{"Adjourned at the request of counsel for the respondent.", ordertype.Adjournment},
+		{"Not taken up. Renotify on 12.03.2024.", ordertype.Adjournment},
+		{"Ad-interim stay of the impugned order till the next date.", ordertype.InterimOrder},
+		{"Interim protection to continue.", ordertype.InterimOrder},
+		{"Judgment pronounced. Appeal allowed.", ordertype.Judgment},
+		{"Suit decreed in terms of settlement.", ordertype.Judgment},
+		{"Petition dismissed in default.", ordertype.Dismissal},
+		{"The matter stands disposed of.", ordertype.Disposal},
+		{"Petition withdrawn with liberty.", ordertype.Disposal},
+		{"Regular bail granted on furnishing a bond of Rs. 25,000.", ordertype.BailGranted},
+		{"Applicant be released on bail.", ordertype.BailGranted},
+		{"Bail application dismissed.", ordertype.BailRejected},
+		{"The court rejects the prayer for bail.", ordertype.BailRejected},
+		{"Counter affidavit filed. Rejoinder within two weeks.", ordertype.Other},
+	}
+	for _, tt := range tests {
+		if got := classifier.Classify(tt.description, ""); got.Type != tt.want {
+			t.Errorf("Classify(%q) = %s, want %s", tt.description, got.Type, tt.want)
+		}
+	}
+
+	// The description outweighs the PDF, which is only read when it says nothing
+	if got := classifier.Classify("Notice issued.", "Bail granted."); got.Type != ordertype.NoticeIssued || got.Confidence != 0.85 {
+		t.Errorf("Expected the description to win, got %+v", got)
+	}
+	got := classifier.Classify("Order dated 02-01-2024", "Regular bail granted.")
+	if got.Type != ordertype.BailGranted || got.Confidence >= 0.9 || got.Confidence <= 0 {
+		t.Errorf("Expected a less confident match from the PDF text, got %+v", got)
+	}
+	if got := classifier.Classify("Order dated 02-01-2024", ""); got.Type != ordertype.Other || got.Confidence != 0 {
+		t.Errorf("Expected Other with no confidence, got %+v", got)
+	}
+}
+
+func TestLoadOrderRules(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rules.json")
+	rules := []ordertype.Rule{{Type: "mediation", Patterns: []string{`referred to (the )?mediation`}, Confidence: 0.95}}
+	data, _ := json.Marshal(rules)
+	os.WriteFile(path, data, 0644)
+
+	classifier, err := ordertype.LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+	if got := classifier.Classify("Notice issued.", ""); got.Type != ordertype.Other {
+		t.Errorf("Expected only the configured rules to apply, got %+v", got)
+	}
+	if got := classifier.Classify("Matter referred to the mediation cell.", ""); got.Type != "mediation" || got.Confidence != 0.95 {
+		t.Errorf("Expected the configured rule to match, got %+v", got)
+	}
+
+	for _, invalid := range []string{`[{"type": "x", "patterns": ["("]}]`, `[{"patterns": ["x"]}]`, `[{"type": "x", "patterns": ["x"], "confidence": 2}]`} {
+		os.WriteFile(path, []byte(invalid), 0644)
+		if _, err := ordertype.LoadFile(path); !errors.Is(err, ordertype.ErrInvalidRule) {
+			t.Errorf("Expected ErrInvalidRule for %s, got %v", invalid, err)
+		}
+	}
+	if classifier, err := ordertype.LoadFile(""); err != nil || classifier != ordertype.Default() {
+		t.Errorf("Expected the default rules without a file, got %v", err)
+	}
+}
+
+// testPDF builds a one page PDF whose compressed content stream shows lines
+func testPDF(lines ...string) []byte {
+	var content bytes.Buffer
+	content.WriteString("BT /F1 12 Tf 72 720 Td ")
+	for i, line := range lines {
+		if i > 0 {
+			content.WriteString("0 -14 Td ")
+		}
+		fmt.Fprintf(&content, "(%s) Tj ", line)
+	}
+	content.WriteString("ET")
+
+	var compressed bytes.Buffer
+	w := zlib.NewWriter(&compressed)
+	w.Write(content.Bytes())
+	w.Close()
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n")
+	fmt.Fprintf(&pdf, "4 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n", compressed.Len())
+	pdf.Write(compressed.Bytes())
+	pdf.WriteString("\nendstream\nendobj\n%%EOF\n")
+	return pdf.Bytes()
+}
+
+func TestExtractPDFText(t *testing.T) {
+	text, err := scraper.ExtractPDFText(testPDF(`IN THE COURT OF SH. RAJESH KUMAR`, `Bail granted \(regular\)`))
+	if err != nil || text != "IN THE COURT OF SH. RAJESH KUMAR\nBail granted (regular)" {
+		t.Errorf("ExtractPDFText = %q, %v", text, err)
+	}
+	if _, err := scraper.ExtractPDFText([]byte("%PDF-1.4\n%%EOF\n")); !errors.Is(err, scraper.ErrNoPDFText) {
+		t.Errorf("Expected ErrNoPDFText, got %v", err)
+	}
+
+	// The mock court's order PDFs are uncompressed, with parentheses in the case number
+	server := httptest.NewServer(mockcourt.New(mockcourt.Config{}))
+	defer server.Close()
+	resp, err := http.Get(server.URL + "/app/orders/1/1.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if text, err := scraper.ExtractPDFText(data); err != nil || !strings.HasPrefix(text, "W.P.(C)/1234/2023 - order dated") {
+		t.Errorf("ExtractPDFText = %q, %v", text, err)
+	}
+}
+
+func TestClassifyStoredOrders(t *testing.T) {
+	router, db := setupTestRouter()
+	repo := database.NewRepository(db)
+	dir := t.TempDir()
+	pdfPath := filepath.Join(dir, "order.pdf")
+	os.WriteFile(pdfPath, testPDF("Accused is admitted to bail on furnishing a personal bond."), 0644)
+
+	// Only the latest snapshot's orders are listed
+	saveOrders := func(orders ...database.Order) {
+		queryLog := &database.QueryLog{CaseNumber: "CRL.M.C./7/2024"}
+		repo.CreateQueryLog(queryLog)
+		if err := repo.SaveCaseSnapshot(queryLog, &database.CaseInfo{CaseNumber: "CRL.M.C./7/2024", Orders: orders}); err != nil {
+			t.Fatalf("SaveCaseSnapshot failed: %v", err)
+		}
+	}
+	saveOrders(database.Order{Description: "Notice issued."})
+	saveOrders(
+		database.Order{Description: "Notice issued."},
+		database.Order{Description: "Order dated 02-01-2024", LocalPath: pdfPath, Downloaded: true},
+	)
+
+	log, _ := logger.NewLogger("error", "json")
+	changed, err := scraper.NewPDFDownloader(db, log, dir).ClassifyOrders()
+	if err != nil || changed != 3 {
+		t.Fatalf("Expected 3 orders classified, got %d, %v", changed, err)
+	}
+
+	get := func(query string) (listings []database.OrderListing, code int) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/api/orders"+query, nil)
+		router.ServeHTTP(w, req)
+		var response struct {
+			Data []database.OrderListing `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return response.Data, w.Code
+	}
+
+	if listings, code := get("?type=notice_issued"); code != http.StatusOK || len(listings) != 1 || listings[0].CaseNumber != "CRL.M.C./7/2024" {
+		t.Errorf("Expected one notice on the latest snapshot, got %d %+v", code, listings)
+	}
+	listings, _ := get("?type=bail_granted&case=crl.m.c. 7/2024")
+	if len(listings) != 1 || listings[0].OrderTypeConfidence <= 0 || listings[0].OrderTypeConfidence >= 0.9 {
+		t.Errorf("Expected the bail order read from its PDF, got %+v", listings)
+	}
+	if listings, _ := get("?min_confidence=0.8"); len(listings) != 1 || listings[0].OrderType != ordertype.NoticeIssued {
+		t.Errorf("Expected only the notice above 0.8, got %+v", listings)
+	}
+	if _, code := get("?min_confidence=high"); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid min_confidence, got %d", code)
+	}
+}
+
+func TestMigrateClassifiesOrders(t *testing.T) {
+	migrator, db := newTestMigrator(t)
+	if err := migrator.To(12); err != nil {
+		t.Fatalf("To(12) failed: %v", err)
+	}
+	if err := db.Exec("INSERT INTO orders (case_info_id, description) VALUES (?, ?)", 1, "Adjourned for want of time.").Error; err != nil {
+		t.Fatalf("Failed to insert legacy order: %v", err)
+	}
+	if err := migrator.Up(); err != nil {
+		t.Fatalf("Up failed: %v", err)
+	}
+
+	var order database.Order
+	db.First(&order)
+	if order.OrderType != ordertype.Adjournment || order.OrderTypeConfidence == 0 {
+		t.Errorf("Expected the order to be classified, got %q %v", order.OrderType, order.OrderTypeConfidence)
+	}
+}
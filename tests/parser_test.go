@@ -0,0 +1,307 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JustJay7/court-data-fetcher/internal/scraper"
+	"github.com/JustJay7/court-data-fetcher/internal/search"
+	"github.com/JustJay7/court-data-fetcher/pkg/logger"
+)
+
+// Parser fixtures are saved court pages in testdata/parser. Each NAME.html is
+// parsed and compared with NAME.golden.json. Add a page with
+// "server fixture <query-log-id> [name]", then record its golden with
+//
+//	go test ./tests -run TestParserGolden -update
+var update = flag.Bool("update", false, "Rewrite the parser golden files from the current parser output")
+
+const (
+	parserFixtureDir = "tests/testdata/parser"
+	// parserFixtureURL is the page URL relative order links resolve against
+	parserFixtureURL = "https://delhidistrictcourts.nic.in/case/details.php"
+)
+
+// parserGolden is the parser output recorded for a fixture. Dates are written
+// as YYYY-MM-DD so the goldens stay readable.
+type parserGolden struct {
+	Error        string        `json:"error,omitempty"`
+	CaseNumber   string        `json:"case_number,omitempty"`
+	CNR          string        `json:"cnr,omitempty"`
+	CaseType     string        `json:"case_type,omitempty"`
+	FilingYear   string        `json:"filing_year,omitempty"`
+	FilingDate   string        `json:"filing_date,omitempty"`
+	NextHearing  string        `json:"next_hearing,omitempty"`
+	Status       string        `json:"status,omitempty"`
+	Judge        string        `json:"judge,omitempty"`
+	CourtComplex string        `json:"court_complex,omitempty"`
+	Parties      []goldenParty `json:"parties"`
+	Report       *goldenReport `json:"report,omitempty"`
+	OrdersError  string        `json:"orders_error,omitempty"`
+	Orders       []goldenOrder `json:"orders"`
+}
+
+type goldenParty struct {
+	Type         string `json:"type"`
+	Name         string `json:"name"`
+	AdvocateName string `json:"advocate_name,omitempty"`
+	AdvocateCode string `json:"advocate_code,omitempty"`
+}
+
+type goldenReport struct {
+	Fields     map[string]string `json:"fields"`
+	Missing    []string          `json:"missing"`
+	Confidence float64           `json:"confidence"`
+}
+
+type goldenOrder struct {
+	OrderDate   string `json:"order_date"`
+	Description string `json:"description"`
+	PDFLink     string `json:"pdf_link,omitempty"`
+	JudgeName   string `json:"judge_name,omitempty"`
+	OrderType   string `json:"order_type"`
+}
+
+func goldenDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+func newTestParser(t *testing.T) *scraper.Parser {
+	t.Helper()
+	log, err := logger.NewLogger("error", "text")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	return scraper.NewParser(log)
+}
+
+// parseFixture runs every parser entry point over a saved page
+func parseFixture(parser *scraper.Parser, pageHTML string) parserGolden {
+	golden := parserGolden{Parties: []goldenParty{}, Orders: []goldenOrder{}}
+
+	caseInfo, err := parser.ParseCaseDetailsHTML(pageHTML)
+	if err != nil {
+		golden.Error = err.Error()
+	} else {
+		golden.CaseNumber = caseInfo.CaseNumber
+		golden.CNR = caseInfo.CNR
+		golden.CaseType = caseInfo.CaseType
+		golden.FilingYear = caseInfo.FilingYear
+		golden.FilingDate = goldenDate(caseInfo.FilingDate)
+		golden.NextHearing = goldenDate(caseInfo.NextHearing)
+		golden.Status = caseInfo.Status
+		golden.Judge = caseInfo.Judge
+		golden.CourtComplex = caseInfo.CourtComplex
+		for _, party := range caseInfo.Parties {
+			golden.Parties = append(golden.Parties, goldenParty{
+				Type:         party.Type,
+				Name:         party.Name,
+				AdvocateName: party.AdvocateName,
+				AdvocateCode: party.AdvocateCode,
+			})
+		}
+		if report := caseInfo.ParseReport; report != nil {
+			golden.Report = &goldenReport{Fields: report.Fields, Missing: report.Missing, Confidence: report.Confidence}
+		}
+	}
+
+	orders, err := parser.ParseOrdersHTML(pageHTML, parserFixtureURL, nil)
+	if err != nil {
+		golden.OrdersError = err.Error()
+	}
+	for _, order := range orders {
+		golden.Orders = append(golden.Orders, goldenOrder{
+			OrderDate:   goldenDate(order.OrderDate),
+			Description: order.Description,
+			PDFLink:     order.PDFLink,
+			JudgeName:   order.JudgeName,
+			OrderType:   order.OrderType,
+		})
+	}
+
+	return golden
+}
+
+func TestParserGolden(t *testing.T) {
+	fixtures, err := filepath.Glob(filepath.Join(parserFixtureDir, "*.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(fixtures) == 0 {
+		t.Fatalf("No parser fixtures found in %s", parserFixtureDir)
+	}
+
+	parser := newTestParser(t)
+	for _, fixture := range fixtures {
+		name := strings.TrimSuffix(filepath.Base(fixture), ".html")
+		t.Run(name, func(t *testing.T) {
+			pageHTML, err := os.ReadFile(fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := json.MarshalIndent(parseFixture(parser, string(pageHTML)), "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+
+			goldenPath := strings.TrimSuffix(fixture, ".html") + ".golden.json"
+			if *update {
+				if err := os.WriteFile(goldenPath, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			want, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("Missing golden file, run with -update to create it: %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("Parser output differs from %s, rerun with -update if the change is intended.\nGot:\n%s\nWant:\n%s", goldenPath, got, want)
+			}
+		})
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	parser := newTestParser(t)
+
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"14-03-2023", "2023-03-14", false},
+		{"14/03/2023", "2023-03-14", false},
+		{"14.03.2023", "2023-03-14", false},
+		{"14-Mar-2023", "2023-03-14", false},
+		{"14-March-2023", "2023-03-14", false},
+		{"14 Mar 2023", "2023-03-14", false},
+		{"14 March 2023", "2023-03-14", false},
+		{"2023-03-14", "2023-03-14", false},
+		{"Mar 14, 2023", "2023-03-14", false},
+		{"March 14, 2023", "2023-03-14", false},
+		{"  14   March\t2023 ", "2023-03-14", false},
+		{"Tuesday, 14 March 2023", "2023-03-14", false},
+		{"tuesday 14-03-2023", "2023-03-14", false},
+		{"7/6/2021", "2021-06-07", false},
+		{"14th March, 2023", "2023-03-14", false},
+		{"Mar. 14th, 2023", "2023-03-14", false},
+		{"14-03-23", "2023-03-14", false},
+		{"१४ मार्च २०२३", "2023-03-14", false},
+		{"14 सितम्बर 2023", "2023-09-14", false},
+		{"14-16 March 2023", "2023-03-14", false},
+		{"14.03.2023 to 16.03.2023", "2023-03-14", false},
+		{"31-02-2023", "", true},
+		{"16.03.2023 to 14.03.2023", "", true},
+		{"14 Smarch 2023", "", true},
+		{"--", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parser.ParseDate(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDate(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if goldenDate(got) != tt.want {
+				t.Errorf("ParseDate(%q) = %s, want %s", tt.input, goldenDate(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractPartyNames(t *testing.T) {
+	parser := newTestParser(t)
+
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"Ramesh Chand", []string{"Ramesh Chand"}},
+		{"Ramesh Chand and Suresh Chand", []string{"Ramesh Chand", "Suresh Chand"}},
+		{"A.K. Jain AND Co. & Meena Jain And Others", []string{"A.K. Jain", "Co.", "Meena Jain", "Others"}},
+		{"State (NCT of Delhi) & Anr. etc.", []string{"State (NCT of Delhi)", "Anr."}},
+		{"  Kamla   Devi\n and\tRaju 2. ", []string{"Kamla Devi", "Raju"}},
+		// Joins are only split on whole words
+		{"Anand Traders", []string{"Anand Traders"}},
+		// Fragments of two characters or less are dropped
+		{"M and Sons", []string{"Sons"}},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := parser.ExtractPartyNames(tt.input)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractPartyNames(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCaseDetailsHTMLErrors(t *testing.T) {
+	parser := newTestParser(t)
+
+	if _, err := parser.ParseCaseDetailsHTML(`<html><body><p>No records found</p></body></html>`); err == nil {
+		t.Error("Expected an error for a page without a details container")
+	}
+	if _, err := parser.ParseCaseDetailsHTML(`<div class="case-info"><p>Nothing here</p></div>`); err == nil {
+		t.Error("Expected an error when no case number can be found")
+	}
+
+	orders, err := parser.ParseOrdersHTML(`<table><tr><td>No orders</td></tr></table>`, parserFixtureURL, nil)
+	if err == nil {
+		t.Error("Expected an error for a page without an orders table")
+	}
+	if len(orders) != 0 {
+		t.Errorf("Expected no orders, got %d", len(orders))
+	}
+}
+
+func TestDriftMonitor(t *testing.T) {
+	log, err := logger.NewLogger("error", "text")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	monitor := search.NewDriftMonitor(0.6, 3, log)
+	for _, confidence := range []float64{1, 0.2, 0.2} {
+		monitor.Observe(confidence)
+	}
+	if _, drifting, _ := monitor.Status(); !drifting {
+		t.Error("Expected drift once the window averages below the threshold")
+	}
+
+	// Staying low must not raise a second alert
+	monitor.Observe(0.1)
+	if _, _, alerts := monitor.Status(); alerts != 1 {
+		t.Errorf("Expected 1 alert, got %d", alerts)
+	}
+
+	for i := 0; i < 3; i++ {
+		monitor.Observe(1)
+	}
+	if average, drifting, _ := monitor.Status(); drifting || average != 1 {
+		t.Errorf("Expected recovery at average 1, got %v (drifting %v)", average, drifting)
+	}
+
+	disabled := search.NewDriftMonitor(0.6, 0, log)
+	disabled.Observe(0)
+	if _, drifting, alerts := disabled.Status(); drifting || alerts != 0 {
+		t.Error("A zero window should disable drift detection")
+	}
+}
@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/JustJay7/court-data-fetcher/internal/database"
+)
+
+func newTestSnapshot() (*database.QueryLog, *database.CaseInfo) {
+	queryLog := &database.QueryLog{CaseType: "CS", CaseNumber: "1234", FilingYear: "2023"}
+	caseInfo := &database.CaseInfo{
+		CaseNumber: "CS/1234/2023",
+		Parties: []database.Party{
+			{Name: "John Doe", Type: "Petitioner"},
+			{Name: "Jane Doe", Type: "Respondent"},
+		},
+		Orders: []database.Order{{Description: "Order on IA"}},
+		ParseReport: &database.ParseReport{
+			Fields:     map[string]string{"case_number": "table", "parties": "table"},
+			Missing:    []string{"judge"},
+			Confidence: 0.9,
+		},
+	}
+	return queryLog, caseInfo
+}
+
+func TestSaveCaseSnapshot(t *testing.T) {
+	db := newTestDB(t)
+	repo := database.NewRepository(db)
+
+	queryLog, caseInfo := newTestSnapshot()
+	if err := repo.CreateQueryLog(queryLog); err != nil {
+		t.Fatalf("CreateQueryLog failed: %v", err)
+	}
+	if err := repo.SaveCaseSnapshot(queryLog, caseInfo); err != nil {
+		t.Fatalf("SaveCaseSnapshot failed: %v", err)
+	}
+
+	var parties, orders int64
+	db.Model(&database.Party{}).Count(&parties)
+	db.Model(&database.Order{}).Count(&orders)
+	if parties != 2 || orders != 1 {
+		t.Errorf("Expected 2 parties and 1 order, got %d and %d", parties, orders)
+	}
+
+	saved, err := repo.FindCaseSnapshotByQueryLog(queryLog.ID)
+	if err != nil {
+		t.Fatalf("FindCaseSnapshotByQueryLog failed: %v", err)
+	}
+	if saved.ID != caseInfo.ID || len(saved.Parties) != 2 || len(saved.Orders) != 1 {
+		t.Errorf("Unexpected snapshot: %+v", saved)
+	}
+
+	storedLog, err := repo.FindQueryLog(queryLog.ID)
+	if err != nil {
+		t.Fatalf("FindQueryLog failed: %v", err)
+	}
+	if !storedLog.Success {
+		t.Error("Expected query log to be marked successful")
+	}
+	if storedLog.ParseConfidence == nil || *storedLog.ParseConfidence != 0.9 {
+		t.Errorf("Expected parse confidence 0.9, got %v", storedLog.ParseConfidence)
+	}
+	if storedLog.ParseReport == nil || storedLog.ParseReport.Fields["case_number"] != "table" {
+		t.Errorf("Expected the parse report to be stored, got %+v", storedLog.ParseReport)
+	}
+
+	if err := repo.SaveCaseSnapshot(queryLog, caseInfo); !errors.Is(err, database.ErrSnapshotExists) {
+		t.Errorf("Expected ErrSnapshotExists, got %v", err)
+	}
+}
+
+func TestSaveCaseSnapshotRollsBack(t *testing.T) {
+	db := newTestDB(t)
+	repo := database.NewRepository(db)
+
+	queryLog, caseInfo := newTestSnapshot()
+	if err := repo.CreateQueryLog(queryLog); err != nil {
+		t.Fatalf("CreateQueryLog failed: %v", err)
+	}
+
+	// Make the last insert of the transaction fail
+	if err := db.Migrator().DropTable(&database.Order{}); err != nil {
+		t.Fatalf("Failed to drop orders table: %v", err)
+	}
+
+	if err := repo.SaveCaseSnapshot(queryLog, caseInfo); err == nil {
+		t.Fatal("Expected SaveCaseSnapshot to fail")
+	}
+
+	var cases, parties int64
+	db.Model(&database.CaseInfo{}).Count(&cases)
+	db.Model(&database.Party{}).Count(&parties)
+	if cases != 0 || parties != 0 {
+		t.Errorf("Expected nothing to be written, got %d cases and %d parties", cases, parties)
+	}
+
+	storedLog, err := repo.FindQueryLog(queryLog.ID)
+	if err != nil {
+		t.Fatalf("FindQueryLog failed: %v", err)
+	}
+	if storedLog.Success {
+		t.Error("Query log should not be marked successful after a rollback")
+	}
+	if caseInfo.ID != 0 || queryLog.Success {
+		t.Error("Expected in-memory IDs and status to be reset after a rollback")
+	}
+}
+
+func TestRawResponsesAreDeduplicated(t *testing.T) {
+	db := newTestDB(t)
+	repo := database.NewRepository(db)
+
+	page := "<html><body>Case details</body></html>"
+	for i := 0; i < 3; i++ {
+		if err := repo.CreateQueryLog(&database.QueryLog{CaseNumber: "1234", RawResponse: page}); err != nil {
+			t.Fatalf("CreateQueryLog failed: %v", err)
+		}
+	}
+
+	var blobs int64
+	db.Model(&database.RawResponseBlob{}).Count(&blobs)
+	if blobs != 1 {
+		t.Errorf("Expected identical pages to be stored once, got %d", blobs)
+	}
+
+	var queryLog database.QueryLog
+	db.Last(&queryLog)
+	if queryLog.RawResponseHash != database.HashRawResponse(page) {
+		t.Errorf("Expected query log to reference the page hash, got %q", queryLog.RawResponseHash)
+	}
+	html, err := repo.RawResponse(&queryLog)
+	if err != nil || html != page {
+		t.Errorf("RawResponse = %q, %v", html, err)
+	}
+}
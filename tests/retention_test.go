@@ -0,0 +1,131 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/JustJay7/court-data-fetcher/internal/config"
+	"github.com/JustJay7/court-data-fetcher/internal/database"
+	"github.com/JustJay7/court-data-fetcher/internal/retention"
+	"github.com/JustJay7/court-data-fetcher/pkg/logger"
+)
+
+func TestAnonymiseIP(t *testing.T) {
+	tests := map[string]string{
+		"203.0.113.42":        "203.0.113.0",
+		"2001:db8:abcd:12::1": "2001:db8:abcd::",
+		"::ffff:198.51.100.7": "198.51.100.0",
+		"not-an-ip":           "",
+		"203.0.113.0":         "203.0.113.0",
+	}
+	for ip, want := range tests {
+		if got := retention.AnonymiseIP(ip); got != want {
+			t.Errorf("AnonymiseIP(%q) = %q, want %q", ip, got, want)
+		}
+	}
+}
+
+func TestPurgerAppliesRetentionPolicies(t *testing.T) {
+	db := newTestDB(t)
+	log, _ := logger.NewLogger("error", "json")
+	old := time.Now().AddDate(0, 0, -100)
+
+	oldLog := &database.QueryLog{RawResponse: "<html>old</html>", IPAddress: "203.0.113.42"}
+	newLog := &database.QueryLog{RawResponse: "<html>new</html>", IPAddress: "198.51.100.7"}
+	db.Create(oldLog)
+	db.Create(newLog)
+	db.Model(oldLog).UpdateColumn("created_at", old)
+
+	pdfPath := filepath.Join(t.TempDir(), "order.pdf")
+	os.WriteFile(pdfPath, []byte("%PDF"), 0644)
+	caseInfo := &database.CaseInfo{CaseNumber: "CS/1/2023"}
+	db.Create(caseInfo)
+	order := &database.Order{CaseInfoID: caseInfo.ID, Downloaded: true, LocalPath: pdfPath}
+	db.Create(order)
+	db.Model(order).UpdateColumn("created_at", old)
+
+	db.Create(&database.Party{CaseInfoID: caseInfo.ID + 100, Name: "Orphan"})
+
+	repo := database.NewRepository(db)
+	repo.SaveDebugArtifacts(oldLog.ID, []database.DebugArtifact{{Kind: database.ArtifactDOM, Data: []byte("<html>old</html>")}})
+	repo.SaveDebugArtifacts(newLog.ID, []database.DebugArtifact{{Kind: database.ArtifactDOM, Data: []byte("<html>new</html>")}})
+	repo.SaveDebugArtifacts(newLog.ID+100, []database.DebugArtifact{{Kind: database.ArtifactDOM, Data: []byte("<html>orphan</html>")}})
+	db.Model(&database.DebugArtifact{}).Where("query_log_id = ?", oldLog.ID).UpdateColumn("created_at", old)
+
+	cfg := &config.Config{
+		IPRetention:            30 * 24 * time.Hour,
+		PDFRetention:           90 * 24 * time.Hour,
+		DebugArtifactRetention: 14 * 24 * time.Hour,
+	}
+	purger := retention.NewPurger(db, log, cfg)
+
+	report, err := purger.Run(true)
+	if err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+	if report.IPsAnonymised != 1 || report.PDFsRemoved != 1 || report.OrphanedParties != 1 ||
+		report.DebugArtifactsPurged != 1 || report.OrphanedDebugArtifacts != 1 {
+		t.Errorf("Unexpected dry run report: %+v", report)
+	}
+	if _, err := os.Stat(pdfPath); err != nil {
+		t.Error("Dry run should not remove PDFs")
+	}
+	var unchanged database.QueryLog
+	db.First(&unchanged, oldLog.ID)
+	if unchanged.IPAddress != "203.0.113.42" {
+		t.Error("Dry run should not change query logs")
+	}
+
+	if _, err := purger.Run(false); err != nil {
+		t.Fatalf("Purge failed: %v", err)
+	}
+
+	var purged database.QueryLog
+	db.First(&purged, oldLog.ID)
+	if html, _ := database.NewRepository(db).RawResponse(&purged); html != "<html>old</html>" {
+		t.Errorf("Raw HTML should be kept without a retention period, got %q", html)
+	}
+	if purged.IPAddress != "203.0.113.0" {
+		t.Errorf("Expected anonymised IP, got %s", purged.IPAddress)
+	}
+
+	var kept database.QueryLog
+	db.First(&kept, newLog.ID)
+	if kept.IPAddress != "198.51.100.7" {
+		t.Error("Recent query logs should be left alone")
+	}
+
+	if _, err := os.Stat(pdfPath); !os.IsNotExist(err) {
+		t.Error("Expected old PDF to be removed")
+	}
+
+	var parties int64
+	db.Unscoped().Model(&database.Party{}).Count(&parties)
+	if parties != 0 {
+		t.Errorf("Expected orphaned party to be deleted, %d left", parties)
+	}
+
+	var artifacts []database.DebugArtifact
+	db.Find(&artifacts)
+	if len(artifacts) != 1 || artifacts[0].QueryLogID != newLog.ID {
+		t.Errorf("Expected only the recent debug artifact to be kept, got %+v", artifacts)
+	}
+
+	cfg.RawHTMLRetention = 30 * 24 * time.Hour
+	report, err = purger.Run(false)
+	if err != nil {
+		t.Fatalf("Purge failed: %v", err)
+	}
+	db.First(&purged, oldLog.ID)
+	if report.RawHTMLDropped != 1 || report.OrphanedRawResponses != 1 || purged.RawResponseHash != "" {
+		t.Errorf("Expected old raw HTML to be dropped, report %+v", report)
+	}
+
+	var blobs int64
+	db.Model(&database.RawResponseBlob{}).Count(&blobs)
+	if blobs != 1 {
+		t.Errorf("Expected only the recent page to be kept, %d stored", blobs)
+	}
+}
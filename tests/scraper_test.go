@@ -2,16 +2,38 @@ package tests
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"testing"
 	"time"
 
+	"github.com/go-rod/rod/lib/launcher"
+
 	"github.com/JustJay7/court-data-fetcher/internal/config"
 	"github.com/JustJay7/court-data-fetcher/internal/scraper"
+	"github.com/JustJay7/court-data-fetcher/internal/upstream"
 	"github.com/JustJay7/court-data-fetcher/pkg/logger"
 )
 
+// requireBrowser skips tests that need a local Chromium when none is installed
+func requireBrowser(t *testing.T) {
+	t.Helper()
+	if os.Getenv("ROD_BROWSER_PATH") != "" {
+		return
+	}
+	if _, found := launcher.LookPath(); !found {
+		// CI installs a browser, so a missing one there is a broken setup
+		if os.Getenv("CI") != "" {
+			t.Fatal("No browser binary found in CI, set ROD_BROWSER_PATH")
+		}
+		t.Skip("No browser binary found, set ROD_BROWSER_PATH to run this test")
+	}
+}
+
 func TestScraperInitialization(t *testing.T) {
+	requireBrowser(t)
+
 	// Create test config
 	cfg := &config.Config{
 		CourtBaseURL:   "https://delhihighcourt.nic.in",
@@ -27,7 +49,7 @@ func TestScraperInitialization(t *testing.T) {
 	}
 
 	// Initialize scraper
-	s, err := scraper.NewScraper(cfg, log)
+	s, err := scraper.NewScraper(cfg, upstream.Direct(), log)
 	if err != nil {
 		t.Fatalf("Failed to create scraper: %v", err)
 	}
@@ -71,14 +93,26 @@ func TestCaseQuery(t *testing.T) {
 			},
 			wantError: true,
 		},
+		{
+			name: "Filing year out of range",
+			query: scraper.CaseQuery{
+				CaseType:   "CS",
+				CaseNumber: "1234",
+				FilingYear: "1023",
+			},
+			wantError: true,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Validate query
-			err := validateQuery(tt.query)
+			err := scraper.ValidateQuery(tt.query)
 			if (err != nil) != tt.wantError {
-				t.Errorf("validateQuery() error = %v, wantError %v", err, tt.wantError)
+				t.Errorf("ValidateQuery() error = %v, wantError %v", err, tt.wantError)
+			}
+			if err != nil && !errors.Is(err, scraper.ErrInvalidInput) {
+				t.Errorf("ValidateQuery() error = %v, want ErrInvalidInput", err)
 			}
 		})
 	}
@@ -89,6 +123,7 @@ func TestConcurrentScraping(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration test in short mode")
 	}
+	requireBrowser(t)
 
 	cfg := &config.Config{
 		CourtBaseURL:         "https://delhihighcourt.nic.in",
@@ -99,7 +134,7 @@ func TestConcurrentScraping(t *testing.T) {
 	}
 
 	log, _ := logger.NewLogger("debug", "text")
-	s, err := scraper.NewScraper(cfg, log)
+	s, err := scraper.NewScraper(cfg, upstream.Direct(), log)
 	if err != nil {
 		t.Fatalf("Failed to create scraper: %v", err)
 	}
@@ -134,16 +169,58 @@ func TestConcurrentScraping(t *testing.T) {
 	}
 }
 
-// Helper function to validate query
-func validateQuery(q scraper.CaseQuery) error {
-	if q.CaseType == "" {
-		return fmt.Errorf("case type is required")
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"nil", nil, ""},
+		{"not found", &scraper.ScrapeError{Kind: scraper.ErrCaseNotFound, Op: "search"}, scraper.CodeCaseNotFound},
+		{"wrapped captcha", fmt.Errorf("retry: %w", &scraper.ScrapeError{Kind: scraper.ErrCaptchaFailed, Op: "handle captcha"}), scraper.CodeCaptchaFailed},
+		{"deadline", &scraper.ScrapeError{Kind: scraper.ErrCourtUnavailable, Op: "navigate", Err: context.DeadlineExceeded}, scraper.CodeTimeout},
+		{"layout", &scraper.ScrapeError{Kind: scraper.ErrLayoutChanged, Op: "find submit button"}, scraper.CodeLayoutChanged},
+		{"unclassified", errors.New("boom"), scraper.CodeInternal},
+		{"with debug bundle", scraper.WithDebugBundle(&scraper.ScrapeError{Kind: scraper.ErrLayoutChanged, Op: "parse results"}, &scraper.DebugBundle{DOM: "<html></html>"}), scraper.CodeLayoutChanged},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scraper.ErrorCode(tt.err); got != tt.want {
+				t.Errorf("ErrorCode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyCourtMessage(t *testing.T) {
+	tests := map[string]error{
+		"Invalid Captcha, please try again":       scraper.ErrCaptchaFailed,
+		"No Record Found":                         scraper.ErrCaseNotFound,
+		"Please enter a valid case number":        scraper.ErrInvalidInput,
+		"Case type is required":                   scraper.ErrInvalidInput,
+		"Server busy, please try after some time": scraper.ErrCourtUnavailable,
+		"Service temporarily unavailable":         scraper.ErrCourtUnavailable,
+	}
+	for message, want := range tests {
+		if err := scraper.ClassifyCourtMessage(message); !errors.Is(err, want) {
+			t.Errorf("ClassifyCourtMessage(%q) = %v, want %v", message, err, want)
+		}
+	}
+}
+
+func TestDebugBundleOf(t *testing.T) {
+	err := &scraper.ScrapeError{Kind: scraper.ErrLayoutChanged, Op: "parse results"}
+	if scraper.DebugBundleOf(err) != nil {
+		t.Error("Expected no bundle on a plain error")
 	}
-	if q.CaseNumber == "" {
-		return fmt.Errorf("case number is required")
+
+	bundle := &scraper.DebugBundle{Console: "[error] boom"}
+	wrapped := fmt.Errorf("search: %w", scraper.WithDebugBundle(err, bundle))
+	if scraper.DebugBundleOf(wrapped) != bundle {
+		t.Error("Expected bundle to be found through wrapping")
 	}
-	if q.FilingYear == "" {
-		return fmt.Errorf("filing year is required")
+	if !errors.Is(wrapped, scraper.ErrLayoutChanged) {
+		t.Error("Attaching a bundle should keep the error kind")
 	}
-	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/JustJay7/court-data-fetcher/internal/config"
+	"github.com/JustJay7/court-data-fetcher/internal/upstream"
+)
+
+func TestHostLimiter(t *testing.T) {
+	limiter := upstream.NewLimiter(50*time.Millisecond, 10*time.Millisecond)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		limiter.Wait(ctx, "court.example")
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("Expected three requests to one host to take at least 100ms, took %v", elapsed)
+	}
+
+	// Other hosts have their own turn
+	start = time.Now()
+	limiter.Wait(ctx, "other.example")
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Errorf("Expected no wait for another host, took %v", elapsed)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Millisecond)
+	defer cancel()
+	limiter.Wait(context.Background(), "busy.example")
+	if err := limiter.Wait(ctx, "busy.example"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the wait to end with the context, got %v", err)
+	}
+}
+
+// testProxy is an HTTP proxy answering every request itself with its name
+func testProxy(name string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(name))
+	}))
+}
+
+func TestProxyRotation(t *testing.T) {
+	a, b, down := testProxy("a"), testProxy("b"), testProxy("down")
+	defer a.Close()
+	defer b.Close()
+	down.Close()
+
+	up, err := upstream.New(&config.Config{
+		Proxies:       []string{a.URL, down.URL, b.URL},
+		ProxyCooldown: time.Minute,
+	}, nil)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	client := up.Client(5 * time.Second)
+
+	var served []string
+	for i := 0; i < 4; i++ {
+		resp, err := client.Get("http://court.example/app/orders")
+		if err != nil {
+			served = append(served, "error")
+			continue
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		served = append(served, string(body))
+	}
+	// The unreachable proxy fails once and is then rested
+	if want := []string{"a", "error", "b", "a"}; !reflect.DeepEqual(served, want) {
+		t.Errorf("Requests went through %v, want %v", served, want)
+	}
+
+	statuses := up.Proxies().Status()
+	if !statuses[0].Healthy || statuses[1].Healthy || statuses[1].Failures != 1 || statuses[1].DownUntil == nil {
+		t.Errorf("Unexpected proxy status %+v", statuses)
+	}
+	if healthy := up.CheckProxies(context.Background(), "http://court.example/"); healthy != 2 {
+		t.Errorf("Expected 2 healthy proxies, got %d", healthy)
+	}
+
+	for _, invalid := range []string{"ftp://10.0.0.1:21", "http://", "://bad"} {
+		if _, err := upstream.NewPool([]string{invalid}, time.Minute); !errors.Is(err, upstream.ErrInvalidProxy) {
+			t.Errorf("Expected ErrInvalidProxy for %q, got %v", invalid, err)
+		}
+	}
+}
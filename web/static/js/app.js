@@ -11,12 +11,18 @@ document.addEventListener('DOMContentLoaded', function() {
         searchForm.addEventListener('submit', handleSearchSubmit);
     }
 
-    // Add input validation
+    // Type and year are needed unless the whole case number is typed in,
+    // e.g. "CS(OS) 1234/2023"
     const caseNumberInput = document.getElementById('case_number');
     if (caseNumberInput) {
         caseNumberInput.addEventListener('input', function(e) {
-            // Remove non-numeric characters
-            e.target.value = e.target.value.replace(/[^0-9]/g, '');
+            const numberOnly = /^\s*[0-9]*\s*$/.test(e.target.value);
+            ['case_type', 'filing_year'].forEach(function(id) {
+                const field = document.getElementById(id);
+                if (field) {
+                    field.required = numberOnly;
+                }
+            });
         });
     }
 